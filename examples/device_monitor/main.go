@@ -6,48 +6,62 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/go-gst/go-gst/pkg/gst"
 )
 
+func printDevice(prefix string, device gst.DeviceInfo) {
+	fmt.Printf("%s %s (%s)\n", prefix, device.DisplayName, device.DeviceClass)
+
+	for key, value := range device.Properties {
+		fmt.Printf("\t%s: %v\n", key, value)
+	}
+}
+
 func run() error {
 	gst.Init()
 
-	fmt.Println("Creating device monitor")
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
 
 	monitor := gst.NewDeviceMonitor()
-	fmt.Println("Created device monitor", monitor)
 
-	caps := gst.CapsFromString("video/x-raw")
+	monitor.AddFilter("Video/Source", gst.CapsFromString("video/x-raw"))
 
-	monitor.AddFilter("Video/Source", caps)
+	devices := monitor.Snapshot()
+	fmt.Printf("Got %d devices\n", len(devices))
 
-	fmt.Println("Getting device monitor bus")
-	bus := monitor.GetBus()
-	fmt.Println("Got device monitor bus")
+	for _, device := range devices {
+		printDevice("Device:", device)
+	}
 
-	fmt.Println("Starting device monitor")
-	monitor.Start()
-	fmt.Println("Started device monitor")
-	devices := monitor.GetDevices()
-	fmt.Printf("Got %d devices\n", len(devices))
-	for i, v := range devices {
-		fmt.Printf("Device: %d %s\n", i, v.GetDisplayName())
+	// the first device can be used in a pipeline
+	if len(devices) > 0 {
+		element, err := devices[0].CreateElement("")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Created element", element.GetName(), "for", devices[0].DisplayName)
 	}
 
-	for msg := range bus.Messages(context.Background()) {
-		switch msg.Type() {
-		case gst.MessageDeviceAdded:
-			message := msg.ParseDeviceAdded().GetDisplayName()
-			fmt.Println("Added: ", message)
-		case gst.MessageDeviceRemoved:
-			message := msg.ParseDeviceRemoved().GetDisplayName()
-			fmt.Println("Removed: ", message)
-		default:
-			// All messages implement a Stringer. However, this is
-			// typically an expensive thing to do and should be avoided.
-			fmt.Println("Type: ", msg.Type())
-			fmt.Println("Message: ", msg.String())
+	fmt.Println("Watching for devices, press Ctrl+C to stop")
+
+	// the devices listed above are reported as added again when the monitor starts
+	for event, err := range monitor.Watch(ctx) {
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case gst.DeviceEventAdded:
+			printDevice("Added:", event.Device)
+		case gst.DeviceEventRemoved:
+			printDevice("Removed:", event.Device)
+		case gst.DeviceEventChanged:
+			printDevice("Changed:", event.Device)
 		}
 	}
 
@@ -55,5 +69,8 @@ func run() error {
 }
 
 func main() {
-	run()
+	if err := run(); err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,226 @@
+// This example reads and rewrites the metadata of a file without building a pipeline.
+//
+// The format of the metadata (ID3v2, FLAC vorbis comments, XMP, EXIF or JPEG) is detected
+// from the first bytes of the file. The tags are printed as JSON, after the edits given on
+// the command line were applied:
+//
+//	tag-edit song.mp3
+//	tag-edit -set title="My Song" -set artist=A -set artist=B -delete comment song.flac
+//	tag-edit -json edits.json photo.jpg
+//
+// The JSON file contains an object with the tag names as keys. Arrays set multiple values and
+// null removes a tag:
+//
+//	{"title": "My Song", "artist": ["A", "B"], "comment": null}
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gsttag"
+)
+
+// stringList collects repeated flags
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+func main() {
+	var sets, deletes stringList
+
+	flag.Var(&sets, "set", "set a tag, tag=value, repeat the flag for multiple values")
+	flag.Var(&deletes, "delete", "remove a tag")
+	jsonFile := flag.String("json", "", "apply the edits of a JSON file")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Printf("USAGE: %s [-set tag=value]... [-delete tag]... [-json edits.json] <file>\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	gst.Init()
+
+	if err := run(flag.Arg(0), sets, deletes, *jsonFile); err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(2)
+	}
+}
+
+func run(path string, sets, deletes []string, jsonFile string) error {
+	read, format, err := gsttag.ReadMetadataFile(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "format:", format)
+
+	// the list must be writable to be edited
+	tags := read.Copy()
+
+	edits := make(map[string][]any)
+
+	for _, tag := range deletes {
+		edits[tag] = nil
+	}
+
+	for _, set := range sets {
+		tag, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return fmt.Errorf("invalid -set %q, expected tag=value", set)
+		}
+
+		edits[tag] = append(edits[tag], value)
+	}
+
+	if jsonFile != "" {
+		if err := readEdits(jsonFile, edits); err != nil {
+			return err
+		}
+	}
+
+	for tag, values := range edits {
+		if err := editTag(tags, tag, values); err != nil {
+			return err
+		}
+	}
+
+	if len(edits) > 0 {
+		if err := gsttag.WriteMetadataFile(path, tags); err != nil {
+			return err
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(tagMap(tags))
+}
+
+func readEdits(path string, edits map[string][]any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]any
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	for tag, value := range raw {
+		switch value := value.(type) {
+		case nil:
+			edits[tag] = nil
+		case []any:
+			edits[tag] = value
+		default:
+			edits[tag] = []any{value}
+		}
+	}
+
+	return nil
+}
+
+// editTag replaces the values of the tag, no values remove the tag
+func editTag(tags *gst.TagList, tag string, values []any) error {
+	if !gst.TagExists(tag) {
+		return fmt.Errorf("unknown tag %s", tag)
+	}
+
+	tags.RemoveTag(tag)
+
+	for _, v := range values {
+		value, err := tagValue(tag, fmt.Sprint(v))
+		if err != nil {
+			return fmt.Errorf("invalid value %v for tag %s: %w", v, tag, err)
+		}
+
+		tags.AddValue(gst.TagMergeAppend, tag, gobject.NewValue(value))
+	}
+
+	return nil
+}
+
+// tagValue parses s into the type of the tag
+func tagValue(tag string, s string) (any, error) {
+	switch typ := gst.TagGetType(tag); typ {
+	case gobject.TypeString:
+		return s, nil
+	case gobject.TypeBoolean:
+		return strconv.ParseBool(s)
+	case gobject.TypeInt:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return int32(n), err
+	case gobject.TypeUint:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return uint32(n), err
+	case gobject.TypeInt64:
+		return strconv.ParseInt(s, 10, 64)
+	case gobject.TypeUint64:
+		return strconv.ParseUint(s, 10, 64)
+	case gobject.TypeDouble:
+		return strconv.ParseFloat(s, 64)
+	case gst.TypeDateTime:
+		if dt := gst.NewDateTimeFromIso8601String(s); dt != nil {
+			return dt, nil
+		}
+
+		return nil, errors.New("expected an ISO 8601 date")
+	default:
+		return nil, fmt.Errorf("editing tags of type %s is not supported", typ.Name())
+	}
+}
+
+// tagMap converts the tags for printing, images are replaced by a short description
+func tagMap(tags *gst.TagList) map[string]any {
+	m := make(map[string]any)
+
+	for i := range uint(tags.NTags()) {
+		tag := tags.NthTagName(i)
+
+		var values []any
+
+		for j := range tags.GetTagSize(tag) {
+			switch v := tags.GetValueIndex(tag, j).(type) {
+			case *gst.DateTime:
+				values = append(values, v.ToIso8601String())
+			case *gst.Sample:
+				values = append(values, fmt.Sprintf("<sample, %d bytes>", v.GetBuffer().GetSize()))
+			default:
+				if v != gobject.InvalidValue {
+					values = append(values, v)
+				}
+			}
+		}
+
+		// GDate values have no Go representation, they are read with the struct mapping
+		if tag == gst.TAG_DATE {
+			var date struct {
+				Date time.Time `gsttag:"date"`
+			}
+
+			if err := tags.UnmarshalTags(&date); err == nil {
+				values = []any{date.Date.Format(time.DateOnly)}
+			}
+		}
+
+		if len(values) == 1 {
+			m[tag] = values[0]
+		} else if len(values) > 1 {
+			m[tag] = values
+		}
+	}
+
+	return m
+}
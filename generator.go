@@ -148,6 +148,7 @@ var Data = genmain.Data{
 					// Manually implemented:
 					typesystem.IgnoreMatching("Object.get_value"),
 					typesystem.IgnoreMatching("ControlBinding.get_value"), // TODO
+					typesystem.IgnoreMatching("ControlSource.control_source_get_value_array"),
 					typesystem.IgnoreMatching("ElementFactory.make_with_properties"),
 					typesystem.IgnoreMatching("Message.parse_property_notify"),
 					typesystem.IgnoreMatching("Message.new_property_notify"),
@@ -272,18 +273,41 @@ var Data = genmain.Data{
 			"GstRtp-1": {
 				MinVersion: "1.26",
 				MaxVersion: "1.26",
+				IgnoredDefinitions: []typesystem.IgnoreFunc{
+					// take byte arrays, manually implemented:
+					typesystem.IgnoreMatching("RTPHeaderExtension.read"),
+					typesystem.IgnoreMatching("RTPHeaderExtension.write"),
+				},
 			},
 			"GstRtsp-1": {
 				MinVersion: "1.26",
 				MaxVersion: "1.26",
+				IgnoredDefinitions: []typesystem.IgnoreFunc{
+					// takes a byte array, manually implemented:
+					typesystem.IgnoreMatching("RTSPMessage.set_body"),
+					// returns a zero terminated array of records, manually implemented:
+					typesystem.IgnoreMatching("RTSPMessage.parse_auth_credentials"),
+				},
 			},
 			"GstSdp-1": {
 				MinVersion: "1.26",
 				MaxVersion: "1.26",
+				IgnoredDefinitions: []typesystem.IgnoreFunc{
+					// takes a string array, manually implemented:
+					typesystem.IgnoreMatching("SDPMessage.add_time"),
+				},
 			},
 			"GstTag-1": {
 				MinVersion: "1.26",
 				MaxVersion: "1.26",
+				IgnoredDefinitions: []typesystem.IgnoreFunc{
+					// take byte arrays, manually implemented:
+					typesystem.IgnoreMatching("tag_list_from_vorbiscomment"),
+					typesystem.IgnoreMatching("tag_list_from_vorbiscomment_buffer"),
+					typesystem.IgnoreMatching("tag_list_to_vorbiscomment_buffer"),
+					// takes a string array, manually implemented:
+					typesystem.IgnoreMatching("tag_list_to_xmp_buffer"),
+				},
 			},
 			"GstVideo-1": {
 				MinVersion: "1.26",
@@ -309,6 +333,11 @@ var Data = genmain.Data{
 		typesystem.MarkAsManuallyExtended("Gst-1", "Bus"),
 		typesystem.MarkAsManuallyExtended("Gst-1", "ChildProxy"),
 		typesystem.MarkAsManuallyExtended("Gst-1", "TagSetter"),
+		typesystem.MarkAsManuallyExtended("Gst-1", "ControlSource"),
+		typesystem.MarkAsManuallyExtended("Gst-1", "DeviceMonitor"),
+		typesystem.MarkAsManuallyExtended("GstRtp-1", "RTPHeaderExtension"),
+		typesystem.MarkAsManuallyExtended("GstPbutils-1", "Discoverer"),
+		typesystem.MarkAsManuallyExtended("GstPlay-1", "Play"),
 
 		// Virtual methods of BaseTransform collide with Element
 		func(r *typesystem.Registry) error {
@@ -0,0 +1,54 @@
+package gst
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/gst.h>
+import "C"
+
+type ControlSourceExtManual interface {
+	// ControlSourceGetValueArray wraps gst_control_source_get_value_array
+	//
+	// It fills values with the values for the timestamps starting at timestamp in steps of
+	// interval.
+	ControlSourceGetValueArray(timestamp ClockTime, interval ClockTime, values []float64) bool
+}
+
+// ControlSourceGetValueArray wraps gst_control_source_get_value_array
+//
+// It fills values with the values for the timestamps starting at timestamp in steps of
+// interval.
+func (self *ControlSourceInstance) ControlSourceGetValueArray(timestamp ClockTime, interval ClockTime, values []float64) bool {
+	var carg0 *C.GstControlSource // in, none, converted
+	var carg1 C.GstClockTime      // in, none, casted, alias
+	var carg2 C.GstClockTime      // in, none, casted, alias
+	var carg3 C.guint             // implicit
+	var carg4 *C.gdouble          // in, none, array
+	var cret C.gboolean           // return
+
+	if len(values) == 0 {
+		return true
+	}
+
+	carg0 = (*C.GstControlSource)(UnsafeControlSourceToGlibNone(self))
+	carg1 = C.GstClockTime(timestamp)
+	carg2 = C.GstClockTime(interval)
+	carg3 = C.guint(len(values))
+	carg4 = (*C.gdouble)(unsafe.Pointer(unsafe.SliceData(values)))
+
+	cret = C.gst_control_source_get_value_array(carg0, carg1, carg2, carg3, carg4)
+	runtime.KeepAlive(self)
+	runtime.KeepAlive(values)
+
+	var goret bool
+
+	if cret != 0 {
+		goret = true
+	}
+
+	return goret
+}
@@ -0,0 +1,174 @@
+package gst
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+)
+
+// ErrDeviceElement is returned by [DeviceInfo.CreateElement] if the device could not create an
+// element.
+var ErrDeviceElement = errors.New("device could not create an element")
+
+// ErrDeviceMonitorStart is returned by [DeviceMonitorInstance.Watch] if the monitor could not be
+// started, e.g. because no device provider matches the filters.
+var ErrDeviceMonitorStart = errors.New("device monitor could not be started")
+
+type DeviceMonitorExtManual interface {
+	// Watch starts the monitor and returns the devices that are added, removed or changed
+	// until ctx is done or the iteration is stopped, then the monitor is stopped. The device
+	// providers report the devices that exist when the monitor starts as added as well. If the
+	// monitor cannot be started, [ErrDeviceMonitorStart] is yielded and the iteration ends.
+	Watch(ctx context.Context) iter.Seq2[DeviceEvent, error]
+
+	// Snapshot returns the devices that match the filters of the monitor.
+	Snapshot() []DeviceInfo
+}
+
+// DeviceEventType is the kind of change of a [DeviceEvent].
+type DeviceEventType int
+
+const (
+	// DeviceEventAdded is sent when a device was plugged in.
+	DeviceEventAdded DeviceEventType = iota
+	// DeviceEventRemoved is sent when a device was removed.
+	DeviceEventRemoved
+	// DeviceEventChanged is sent when the properties of a device changed.
+	DeviceEventChanged
+)
+
+func (t DeviceEventType) String() string {
+	switch t {
+	case DeviceEventAdded:
+		return "added"
+	case DeviceEventRemoved:
+		return "removed"
+	case DeviceEventChanged:
+		return "changed"
+	default:
+		return fmt.Sprintf("DeviceEventType(%d)", int(t))
+	}
+}
+
+// DeviceEvent is a change of a device returned by [DeviceMonitorInstance.Watch].
+type DeviceEvent struct {
+	Type   DeviceEventType
+	Device DeviceInfo
+	// Previous is the device before the change, it is only set for [DeviceEventChanged].
+	Previous DeviceInfo
+}
+
+// DeviceInfo describes a [Device] with go types.
+type DeviceInfo struct {
+	DisplayName string
+	// DeviceClass is the klass of the device, e.g. "Video/Source".
+	DeviceClass string
+	Caps        *Caps
+	// Properties contains the fields of the properties structure of the device, e.g.
+	// "device.path" or "device.api". Values without go representation are left out.
+	Properties map[string]any
+
+	// Device is the described device.
+	Device Device
+}
+
+// NewDeviceInfo reads the information of the device.
+func NewDeviceInfo(device Device) DeviceInfo {
+	info := DeviceInfo{
+		DisplayName: device.GetDisplayName(),
+		DeviceClass: device.GetDeviceClass(),
+		Caps:        device.GetCaps(),
+		Properties:  make(map[string]any),
+		Device:      device,
+	}
+
+	if props := device.GetProperties(); props != nil {
+		props.ForEach(func(field string, value any) bool {
+			if value != gobject.InvalidValue {
+				info.Properties[field] = value
+			}
+
+			return true
+		})
+	}
+
+	return info
+}
+
+// CreateElement creates an element that is configured to use the device, e.g. a v4l2src with
+// the device path. name may be empty to use a unique name.
+func (info DeviceInfo) CreateElement(name string) (Element, error) {
+	if info.Device == nil {
+		return nil, fmt.Errorf("%w: no device", ErrDeviceElement)
+	}
+
+	element := info.Device.CreateElement(name)
+	if element == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceElement, info.DisplayName)
+	}
+
+	return element, nil
+}
+
+// Watch starts the monitor and returns the devices that are added, removed or changed
+// until ctx is done or the iteration is stopped, then the monitor is stopped.
+//
+// The device providers post a [DeviceEventAdded] event for every device that exists when the
+// monitor starts, so the devices returned by an earlier [DeviceMonitorInstance.Snapshot] are
+// reported again. If the monitor cannot be started, [ErrDeviceMonitorStart] is yielded and the
+// iteration ends.
+func (monitor *DeviceMonitorInstance) Watch(ctx context.Context) iter.Seq2[DeviceEvent, error] {
+	return func(yield func(DeviceEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		bus := monitor.GetBus()
+
+		// the sync handler is installed before starting, so no device is missed
+		messages := bus.Messages(ctx)
+
+		if !monitor.Start() {
+			bus.SetSyncHandler(nil)
+			yield(DeviceEvent{}, ErrDeviceMonitorStart)
+
+			return
+		}
+		defer monitor.Stop()
+
+		for msg := range messages {
+			var event DeviceEvent
+
+			switch msg.Type() {
+			case MessageDeviceAdded:
+				event = DeviceEvent{Type: DeviceEventAdded, Device: NewDeviceInfo(msg.ParseDeviceAdded())}
+			case MessageDeviceRemoved:
+				event = DeviceEvent{Type: DeviceEventRemoved, Device: NewDeviceInfo(msg.ParseDeviceRemoved())}
+			case MessageDeviceChanged:
+				device, previous := msg.ParseDeviceChanged()
+				event = DeviceEvent{Type: DeviceEventChanged, Device: NewDeviceInfo(device), Previous: NewDeviceInfo(previous)}
+			default:
+				continue
+			}
+
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Snapshot returns the devices that match the filters of the monitor.
+func (monitor *DeviceMonitorInstance) Snapshot() []DeviceInfo {
+	devices := monitor.GetDevices()
+
+	infos := make([]DeviceInfo, 0, len(devices))
+
+	for _, device := range devices {
+		infos = append(infos, NewDeviceInfo(device))
+	}
+
+	return infos
+}
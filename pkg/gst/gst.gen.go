@@ -21441,6 +21441,7 @@ var _ ControlSource = (*ControlSourceInstance)(nil)
 // 
 // see also https://gstreamer.freedesktop.org/documentation/gstreamer/gstcontrolsource.html#GstControlSource
 type ControlSource interface {
+	ControlSourceExtManual // handwritten functions
 	Object
 	upcastToGstControlSource() *ControlSourceInstance
 
@@ -21448,10 +21449,6 @@ type ControlSource interface {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/gstreamer/gstcontrolsource.html#gst_control_source_get_value
 	ControlSourceGetValue(ClockTime) (float64, bool)
-	// ControlSourceGetValueArray wraps gst_control_source_get_value_array
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/gstreamer/gstcontrolsource.html#gst_control_source_get_value_array
-	ControlSourceGetValueArray(ClockTime, ClockTime, []float64) bool
 
 	// chain up virtual methods:
 }
@@ -21535,40 +21532,6 @@ func (self *ControlSourceInstance) ControlSourceGetValue(timestamp ClockTime) (f
 	return value, goret
 }
 
-// ControlSourceGetValueArray wraps gst_control_source_get_value_array
-// 
-// see also https://gstreamer.freedesktop.org/documentation/gstreamer/gstcontrolsource.html#gst_control_source_get_value_array
-func (self *ControlSourceInstance) ControlSourceGetValueArray(timestamp ClockTime, interval ClockTime, values []float64) bool {
-	var carg0 *C.GstControlSource // in, none, converted
-	var carg1 C.GstClockTime      // in, none, casted, alias
-	var carg2 C.GstClockTime      // in, none, casted, alias
-	var carg3 C.guint             // implicit
-	var carg4 *C.gdouble          // in, transfer: none, C Pointers: 1, Name: array[gdouble], array (inner gdouble (*typesystem.CastablePrimitive), length-by: carg3)
-	var cret  C.gboolean          // return
-
-	carg0 = (*C.GstControlSource)(UnsafeControlSourceToGlibNone(self))
-	carg1 = C.GstClockTime(timestamp)
-	carg2 = C.GstClockTime(interval)
-	_ = values
-	_ = carg4
-	_ = carg3
-	panic("unimplemented conversion of []float64 (gdouble*) because of unimplemented: non-fixed size array")
-
-	cret = C.gst_control_source_get_value_array(carg0, carg1, carg2, carg3, carg4)
-	runtime.KeepAlive(self)
-	runtime.KeepAlive(timestamp)
-	runtime.KeepAlive(interval)
-	runtime.KeepAlive(values)
-
-	var goret bool
-
-	if cret != 0 {
-		goret = true
-	}
-
-	return goret
-}
-
 // ControlSourceOverrides is the struct used to override the default implementation of virtual methods.
 // it is generic over the extending instance type.
 type ControlSourceOverrides[Instance ControlSource] struct {
@@ -22087,6 +22050,7 @@ var _ DeviceMonitor = (*DeviceMonitorInstance)(nil)
 // 
 // see also https://gstreamer.freedesktop.org/documentation/gstreamer/gstdevicemonitor.html#GstDeviceMonitor
 type DeviceMonitor interface {
+	DeviceMonitorExtManual // handwritten functions
 	Object
 	upcastToGstDeviceMonitor() *DeviceMonitorInstance
 
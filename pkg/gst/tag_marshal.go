@@ -0,0 +1,467 @@
+package gst
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+)
+
+// #cgo pkg-config: gstreamer-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/gst.h>
+import "C"
+
+// TagImage is the Go representation of image tags like [TAG_IMAGE] and [TAG_PREVIEW_IMAGE] in
+// [MarshalTags] and [TagList.UnmarshalTags].
+type TagImage struct {
+	Data []byte
+	// MIME is the media type of the image, e.g. "image/jpeg".
+	MIME string
+	// Type is the GstTagImageType of the image, see the gsttag.TagImageType constants, e.g. 1
+	// for the front cover. The zero value is the undefined image type.
+	Type int32
+}
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	tagImageType = reflect.TypeFor[TagImage]()
+)
+
+// MarshalTags converts the given go struct into a TagList. Only fields with a gsttag struct tag
+// are marshaled, the tag contains the name of the tag:
+//
+//	type Metadata struct {
+//		Title   string    `gsttag:"title"`
+//		Artists []string  `gsttag:"artist"`
+//		Track   uint      `gsttag:"track-number"`
+//		Date    time.Time `gsttag:"date"`
+//		Cover   TagImage  `gsttag:"image"`
+//	}
+//
+// Slices are marshaled as multiple values of the same tag. time.Time is converted to a GDate or
+// GstDateTime and TagImage to a GstSample, depending on the type of the tag. Numbers are
+// converted to the type of the tag. Fields with zero values are skipped, embedded structs are
+// marshaled into the same list.
+func MarshalTags(data any) (*TagList, error) {
+	valsOf := reflect.Indirect(reflect.ValueOf(data))
+	if valsOf.Kind() != reflect.Struct {
+		return nil, errors.New("cannot marshal tags: data is not a struct")
+	}
+
+	list := NewTagListEmpty()
+
+	if err := marshalTagsInto(valsOf.Type(), valsOf, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// MergeTags marshals data like [MarshalTags] and inserts the tags into the list with the given
+// merge mode. The list must be writable.
+func (list *TagList) MergeTags(data any, mode TagMergeMode) error {
+	tags, err := MarshalTags(data)
+	if err != nil {
+		return err
+	}
+
+	list.Insert(tags, mode)
+
+	return nil
+}
+
+func marshalTagsInto(typeOf reflect.Type, valsOf reflect.Value, list *TagList) error {
+	for i := 0; i < valsOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		fieldVal := valsOf.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := tagNameFromField(field)
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := marshalTagsInto(field.Type, fieldVal, list); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if fieldVal.IsZero() {
+			continue
+		}
+
+		if !TagExists(tag) {
+			return fmt.Errorf("cannot marshal field %s: unknown tag %s", field.Name, tag)
+		}
+
+		gtype := TagGetType(tag)
+
+		if field.Type.Kind() == reflect.Slice {
+			for j := 0; j < fieldVal.Len(); j++ {
+				if err := marshalTagValue(list, tag, gtype, fieldVal.Index(j)); err != nil {
+					return fmt.Errorf("cannot marshal field %s: %w", field.Name, err)
+				}
+			}
+
+			continue
+		}
+
+		if err := marshalTagValue(list, tag, gtype, fieldVal); err != nil {
+			return fmt.Errorf("cannot marshal field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func marshalTagValue(list *TagList, tag string, gtype gobject.Type, v reflect.Value) error {
+	var goValue any
+
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+
+		switch gtype {
+		case TypeDateTime:
+			goValue = dateTimeFromTime(t)
+		case gobject.Type(C.g_date_get_type()):
+			addTagDate(list, tag, t)
+
+			return nil
+		default:
+			return fmt.Errorf("tag %s of type %s cannot hold a time", tag, gtype.Name())
+		}
+	case tagImageType:
+		if gtype != TypeSample {
+			return fmt.Errorf("tag %s of type %s cannot hold an image", tag, gtype.Name())
+		}
+
+		goValue = v.Interface().(TagImage).sample()
+	default:
+		target, ok := tagPrimitiveType(gtype)
+		if !ok || !convertibleTagValue(v.Type(), target) {
+			return fmt.Errorf("tag %s of type %s cannot hold a %s", tag, gtype.Name(), v.Type().String())
+		}
+
+		goValue = v.Convert(target).Interface()
+	}
+
+	list.AddValue(TagMergeAppend, tag, gobject.NewValue(goValue))
+
+	return nil
+}
+
+// UnmarshalTags will unmarshal the tags of the list into the given pointer to a struct, see
+// [MarshalTags] for the supported fields. Fields of tags that are not in the list are left
+// unchanged. If a tag has multiple values and the field is not a slice, the first value is used.
+func (list *TagList) UnmarshalTags(data any) error {
+	valsOf := reflect.ValueOf(data)
+	if valsOf.Kind() != reflect.Pointer || valsOf.IsNil() {
+		return errors.New("data is invalid (nil or non-pointer)")
+	}
+
+	valsOf = valsOf.Elem()
+
+	if valsOf.Kind() != reflect.Struct {
+		return errors.New("cannot unmarshal into data: data is not pointer to struct")
+	}
+
+	return unmarshalTagsInto(valsOf.Type(), valsOf, list)
+}
+
+func unmarshalTagsInto(typeOf reflect.Type, valsOf reflect.Value, list *TagList) error {
+	for i := 0; i < valsOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		fieldVal := valsOf.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := tagNameFromField(field)
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := unmarshalTagsInto(field.Type, fieldVal, list); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		n := list.GetTagSize(tag)
+		if n == 0 {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice {
+			values := reflect.MakeSlice(field.Type, int(n), int(n))
+
+			for j := range n {
+				if err := unmarshalTagValue(list, tag, j, values.Index(int(j))); err != nil {
+					return fmt.Errorf("cannot unmarshal field %s: %w", field.Name, err)
+				}
+			}
+
+			fieldVal.Set(values)
+
+			continue
+		}
+
+		if err := unmarshalTagValue(list, tag, 0, fieldVal); err != nil {
+			return fmt.Errorf("cannot unmarshal field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func unmarshalTagValue(list *TagList, tag string, index uint, target reflect.Value) error {
+	var carg0 *C.GstTagList // in, none, converted
+	var carg1 *C.gchar      // in, none, string
+	var cret *C.GValue      // return, none, converted
+
+	carg0 = (*C.GstTagList)(UnsafeTagListToGlibNone(list))
+	carg1 = (*C.gchar)(unsafe.Pointer(C.CString(tag)))
+	defer C.free(unsafe.Pointer(carg1))
+
+	cret = C.gst_tag_list_get_value_index(carg0, carg1, C.guint(index))
+	defer runtime.KeepAlive(list)
+
+	if cret == nil {
+		return fmt.Errorf("tag %s has no value at index %d", tag, index)
+	}
+
+	// GDate has no Go representation in gobject
+	if cret.g_type == C.g_date_get_type() {
+		if target.Type() != timeType {
+			return fmt.Errorf("cannot convert date of tag %s to %s", tag, target.Type().String())
+		}
+
+		target.Set(reflect.ValueOf(timeFromDate((*C.GDate)(C.g_value_get_boxed(cret)))))
+
+		return nil
+	}
+
+	goValue := gobject.ValueFromNative(unsafe.Pointer(cret)).GoValue()
+
+	switch v := goValue.(type) {
+	case *DateTime:
+		if target.Type() != timeType {
+			return fmt.Errorf("cannot convert date time of tag %s to %s", tag, target.Type().String())
+		}
+
+		target.Set(reflect.ValueOf(v.goTime()))
+	case *Sample:
+		if target.Type() != tagImageType {
+			return fmt.Errorf("cannot convert sample of tag %s to %s", tag, target.Type().String())
+		}
+
+		target.Set(reflect.ValueOf(tagImageFromSample(v)))
+	default:
+		rv := reflect.ValueOf(goValue)
+
+		if goValue == gobject.InvalidValue || !convertibleTagValue(rv.Type(), target.Type()) {
+			return fmt.Errorf("cannot convert value %#v of tag %s to %s", goValue, tag, target.Type().String())
+		}
+
+		target.Set(rv.Convert(target.Type()))
+	}
+
+	return nil
+}
+
+// tagNameFromField returns the name of the tag of the field, fields without tag or with the tag
+// "-" are skipped
+func tagNameFromField(field reflect.StructField) (string, bool) {
+	fieldTag, ok := field.Tag.Lookup("gsttag")
+	if !ok {
+		return "", false
+	}
+
+	name := parseStructureTags(fieldTag).name
+
+	return name, name != "" && name != "-"
+}
+
+// tagPrimitiveType returns the go type that is used for values of the fundamental tag types
+func tagPrimitiveType(gtype gobject.Type) (reflect.Type, bool) {
+	switch gtype {
+	case gobject.TypeString:
+		return reflect.TypeFor[string](), true
+	case gobject.TypeBoolean:
+		return reflect.TypeFor[bool](), true
+	case gobject.TypeInt:
+		return reflect.TypeFor[int32](), true
+	case gobject.TypeUint:
+		return reflect.TypeFor[uint32](), true
+	case gobject.TypeInt64:
+		return reflect.TypeFor[int64](), true
+	case gobject.TypeUint64:
+		return reflect.TypeFor[uint64](), true
+	case gobject.TypeFloat:
+		return reflect.TypeFor[float32](), true
+	case gobject.TypeDouble:
+		return reflect.TypeFor[float64](), true
+	default:
+		return nil, false
+	}
+}
+
+// convertibleTagValue only allows conversions between values of the same kind, reflect would
+// also convert numbers to strings
+func convertibleTagValue(from, to reflect.Type) bool {
+	return tagKindClass(from.Kind()) != reflect.Invalid && tagKindClass(from.Kind()) == tagKindClass(to.Kind())
+}
+
+func tagKindClass(kind reflect.Kind) reflect.Kind {
+	switch kind {
+	case reflect.String, reflect.Bool:
+		return kind
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	default:
+		return reflect.Invalid
+	}
+}
+
+func dateTimeFromTime(t time.Time) *DateTime {
+	_, offset := t.Zone()
+	seconds := float64(t.Second()) + float64(t.Nanosecond()/1000)/1e6
+
+	return NewDateTime(float32(offset)/3600, int32(t.Year()), int32(t.Month()), int32(t.Day()), int32(t.Hour()), int32(t.Minute()), seconds)
+}
+
+// goTime converts the date time, missing fields are set to their lowest value
+func (datetime *DateTime) goTime() time.Time {
+	month, day := time.January, 1
+	hour, minute, second, microsecond := 0, 0, 0, 0
+	offset := float32(0)
+
+	if datetime.HasMonth() {
+		month = time.Month(datetime.GetMonth())
+	}
+
+	if datetime.HasDay() {
+		day = int(datetime.GetDay())
+	}
+
+	if datetime.HasTime() {
+		hour, minute = int(datetime.GetHour()), int(datetime.GetMinute())
+		offset = datetime.GetTimeZoneOffset()
+	}
+
+	if datetime.HasSecond() {
+		second, microsecond = int(datetime.GetSecond()), int(datetime.GetMicrosecond())
+	}
+
+	loc := time.UTC
+	if offset != 0 {
+		loc = time.FixedZone("", int(math.Round(float64(offset)*3600)))
+	}
+
+	return time.Date(int(datetime.GetYear()), month, day, hour, minute, second, microsecond*1000, loc)
+}
+
+func addTagDate(list *TagList, tag string, t time.Time) {
+	var value C.GValue
+
+	C.g_value_init(&value, C.g_date_get_type())
+	C.g_value_take_boxed(&value, C.gconstpointer(C.g_date_new_dmy(C.GDateDay(t.Day()), C.GDateMonth(t.Month()), C.GDateYear(t.Year()))))
+
+	cTag := C.CString(tag)
+	defer C.free(unsafe.Pointer(cTag))
+
+	C.gst_tag_list_add_value((*C.GstTagList)(UnsafeTagListToGlibNone(list)), C.GstTagMergeMode(C.GST_TAG_MERGE_APPEND), (*C.gchar)(unsafe.Pointer(cTag)), &value)
+	runtime.KeepAlive(list)
+
+	C.g_value_unset(&value)
+}
+
+func timeFromDate(date *C.GDate) time.Time {
+	if date == nil || C.g_date_valid(date) == 0 {
+		return time.Time{}
+	}
+
+	return time.Date(int(C.g_date_get_year(date)), time.Month(C.g_date_get_month(date)), int(C.g_date_get_day(date)), 0, 0, 0, 0, time.UTC)
+}
+
+// sample creates the sample of an image tag, the image type is only set if the gsttag library is
+// loaded
+func (img TagImage) sample() *Sample {
+	var cdata C.gconstpointer
+	if len(img.Data) > 0 {
+		cdata = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(img.Data)))
+	}
+
+	buffer := UnsafeBufferFromGlibFull(unsafe.Pointer(C.gst_buffer_new_memdup(cdata, C.gsize(len(img.Data)))))
+	runtime.KeepAlive(img.Data)
+
+	var caps *Caps
+	if img.MIME != "" {
+		caps = NewCapsEmptySimple(img.MIME)
+	}
+
+	var info *Structure
+
+	cName := C.CString("GstTagImageType")
+	defer C.free(unsafe.Pointer(cName))
+
+	if imageType := C.g_type_from_name((*C.gchar)(unsafe.Pointer(cName))); imageType != 0 {
+		info = NewStructureEmpty("GstTagImageInfo")
+
+		var value C.GValue
+
+		C.g_value_init(&value, imageType)
+		C.g_value_set_enum(&value, C.gint(img.Type))
+
+		cField := C.CString("image-type")
+		defer C.free(unsafe.Pointer(cField))
+
+		C.gst_structure_take_value((*C.GstStructure)(UnsafeStructureToGlibNone(info)), (*C.gchar)(unsafe.Pointer(cField)), &value)
+		runtime.KeepAlive(info)
+	}
+
+	return NewSample(buffer, caps, nil, info)
+}
+
+func tagImageFromSample(sample *Sample) TagImage {
+	var img TagImage
+
+	if buffer := sample.GetBuffer(); buffer != nil {
+		if info, ok := buffer.Map(MapRead); ok {
+			img.Data = append([]byte(nil), info.Data()...)
+			info.Unmap()
+		}
+	}
+
+	if caps := sample.GetCaps(); caps != nil && caps.GetSize() > 0 {
+		img.MIME = caps.GetStructure(0).GetName()
+	}
+
+	if info := sample.GetInfo(); info != nil {
+		cField := C.CString("image-type")
+		defer C.free(unsafe.Pointer(cField))
+
+		value := C.gst_structure_get_value((*C.GstStructure)(UnsafeStructureToGlibNone(info)), (*C.gchar)(unsafe.Pointer(cField)))
+		if value != nil && C.g_type_is_a(value.g_type, C.G_TYPE_ENUM) != 0 {
+			img.Type = int32(C.g_value_get_enum(value))
+		}
+
+		runtime.KeepAlive(info)
+	}
+
+	return img
+}
@@ -0,0 +1,105 @@
+package gst_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+type (
+	TagCommon struct {
+		Title string `gsttag:"title"`
+	}
+	TagMetadata struct {
+		TagCommon
+		Artists  []string      `gsttag:"artist"`
+		Track    uint          `gsttag:"track-number"`
+		Gain     float64       `gsttag:"replaygain-track-gain"`
+		Duration time.Duration `gsttag:"duration"`
+		Date     time.Time     `gsttag:"date"`
+		DateTime time.Time     `gsttag:"datetime"`
+		Cover    gst.TagImage  `gsttag:"image"`
+		Ignored  string
+	}
+
+	// errors:
+	TagUnknown struct {
+		X string `gsttag:"go-gst-unknown-tag"`
+	}
+	TagWrongType struct {
+		Title int32 `gsttag:"title"`
+	}
+)
+
+func TestTagsMarshal(t *testing.T) {
+	gst.Init()
+
+	v := TagMetadata{
+		TagCommon: TagCommon{Title: "Song"},
+		Artists:   []string{"A", "B"},
+		Track:     7,
+		Gain:      -3.5,
+		Duration:  3 * time.Minute,
+		Date:      time.Date(2020, time.March, 4, 0, 0, 0, 0, time.UTC),
+		DateTime:  time.Date(2021, time.May, 6, 7, 8, 9, 0, time.FixedZone("", 2*3600)),
+		Cover:     gst.TagImage{Data: []byte{1, 2, 3}, MIME: "image/png"},
+	}
+
+	list, err := gst.MarshalTags(v)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	t.Log(list.String())
+
+	if n := list.GetTagSize(gst.TAG_ARTIST); n != 2 {
+		t.Fatalf("expected 2 artists, got %d", n)
+	}
+
+	var got TagMetadata
+
+	if err := list.UnmarshalTags(&got); err != nil {
+		t.Fatalf("could not unmarshal %v", err)
+	}
+
+	if !got.DateTime.Equal(v.DateTime) {
+		t.Fatalf("expected %v, got %v", v.DateTime, got.DateTime)
+	}
+
+	got.DateTime = v.DateTime
+
+	if !reflect.DeepEqual(got, v) {
+		t.Fatalf("expected %#v, got %#v", v, got)
+	}
+
+	if err := list.MergeTags(TagCommon{Title: "Other"}, gst.TagMergeKeep); err != nil {
+		t.Fatal(err)
+	}
+
+	if title, _ := list.GetString(gst.TAG_TITLE); title != "Song" {
+		t.Fatalf("expected the title to be kept, got %s", title)
+	}
+
+	if err := list.MergeTags(TagCommon{Title: "Other"}, gst.TagMergeReplace); err != nil {
+		t.Fatal(err)
+	}
+
+	if title, _ := list.GetString(gst.TAG_TITLE); title != "Other" {
+		t.Fatalf("expected the title to be replaced, got %s", title)
+	}
+
+	// errors:
+	if _, err := gst.MarshalTags(TagUnknown{"x"}); err == nil {
+		t.Fatal("expected an error for an unknown tag")
+	}
+
+	if _, err := gst.MarshalTags(TagWrongType{1}); err == nil {
+		t.Fatal("expected an error for a wrong type")
+	}
+
+	if err := list.UnmarshalTags(&TagWrongType{}); err == nil {
+		t.Fatal("expected an error for a wrong type")
+	}
+}
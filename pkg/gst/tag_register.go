@@ -0,0 +1,141 @@
+package gst
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+)
+
+// #cgo pkg-config: gstreamer-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/gst.h>
+//
+// extern void _gogst_gst1_TagMergeFunc(int, GValue *, GValue *);
+//
+// // GstTagMergeFunc has no user data, so every go merge function gets its own trampoline
+// #define _GOGST_TAG_MERGE(n) \
+//   static void _gogst_gst1_tag_merge_##n(GValue *dest, const GValue *src) { _gogst_gst1_TagMergeFunc(n, dest, (GValue *) src); }
+//
+// _GOGST_TAG_MERGE(0)  _GOGST_TAG_MERGE(1)  _GOGST_TAG_MERGE(2)  _GOGST_TAG_MERGE(3)
+// _GOGST_TAG_MERGE(4)  _GOGST_TAG_MERGE(5)  _GOGST_TAG_MERGE(6)  _GOGST_TAG_MERGE(7)
+// _GOGST_TAG_MERGE(8)  _GOGST_TAG_MERGE(9)  _GOGST_TAG_MERGE(10) _GOGST_TAG_MERGE(11)
+// _GOGST_TAG_MERGE(12) _GOGST_TAG_MERGE(13) _GOGST_TAG_MERGE(14) _GOGST_TAG_MERGE(15)
+// _GOGST_TAG_MERGE(16) _GOGST_TAG_MERGE(17) _GOGST_TAG_MERGE(18) _GOGST_TAG_MERGE(19)
+// _GOGST_TAG_MERGE(20) _GOGST_TAG_MERGE(21) _GOGST_TAG_MERGE(22) _GOGST_TAG_MERGE(23)
+// _GOGST_TAG_MERGE(24) _GOGST_TAG_MERGE(25) _GOGST_TAG_MERGE(26) _GOGST_TAG_MERGE(27)
+// _GOGST_TAG_MERGE(28) _GOGST_TAG_MERGE(29) _GOGST_TAG_MERGE(30) _GOGST_TAG_MERGE(31)
+//
+// static GstTagMergeFunc _gogst_gst1_tag_merge_funcs[] = {
+//   _gogst_gst1_tag_merge_0,  _gogst_gst1_tag_merge_1,  _gogst_gst1_tag_merge_2,  _gogst_gst1_tag_merge_3,
+//   _gogst_gst1_tag_merge_4,  _gogst_gst1_tag_merge_5,  _gogst_gst1_tag_merge_6,  _gogst_gst1_tag_merge_7,
+//   _gogst_gst1_tag_merge_8,  _gogst_gst1_tag_merge_9,  _gogst_gst1_tag_merge_10, _gogst_gst1_tag_merge_11,
+//   _gogst_gst1_tag_merge_12, _gogst_gst1_tag_merge_13, _gogst_gst1_tag_merge_14, _gogst_gst1_tag_merge_15,
+//   _gogst_gst1_tag_merge_16, _gogst_gst1_tag_merge_17, _gogst_gst1_tag_merge_18, _gogst_gst1_tag_merge_19,
+//   _gogst_gst1_tag_merge_20, _gogst_gst1_tag_merge_21, _gogst_gst1_tag_merge_22, _gogst_gst1_tag_merge_23,
+//   _gogst_gst1_tag_merge_24, _gogst_gst1_tag_merge_25, _gogst_gst1_tag_merge_26, _gogst_gst1_tag_merge_27,
+//   _gogst_gst1_tag_merge_28, _gogst_gst1_tag_merge_29, _gogst_gst1_tag_merge_30, _gogst_gst1_tag_merge_31,
+// };
+//
+// static void _gogst_gst1_tag_register(const char *name, GstTagFlag flag, GType type, const char *nick, const char *blurb, int merge) {
+//   GstTagMergeFunc func = NULL;
+//
+//   if (merge >= 0) {
+//     func = _gogst_gst1_tag_merge_funcs[merge];
+//   }
+//
+//   gst_tag_register(name, flag, type, nick, blurb, func);
+// }
+import "C"
+
+// maxTagMergeFuncs is the number of C trampolines available for go merge functions
+const maxTagMergeFuncs = 32
+
+var (
+	// ErrTagExists is returned by [RegisterTag] if a tag with the name is already registered.
+	ErrTagExists = errors.New("tag already registered")
+	// ErrTooManyTagMergeFuncs is returned by [RegisterTag] if all slots for go merge functions
+	// are used.
+	ErrTooManyTagMergeFuncs = errors.New("too many tag merge functions")
+)
+
+// TagMergeFunc merges all values of a tag into a single value. It is called by the getters of a
+// [TagList], e.g. [TagList.GetString], if the tag has multiple values. The values are never
+// empty and the returned value must have the type of the tag.
+type TagMergeFunc func(values []any) any
+
+// TagMergeUseFirst is a [TagMergeFunc] that uses the first value, like gst_tag_merge_use_first.
+func TagMergeUseFirst(values []any) any {
+	return values[0]
+}
+
+// TagMergeStringsWithComma is a [TagMergeFunc] for string tags that joins the values with a
+// comma, like gst_tag_merge_strings_with_comma.
+func TagMergeStringsWithComma(values []any) any {
+	strs := make([]string, 0, len(values))
+
+	for _, v := range values {
+		strs = append(strs, fmt.Sprint(v))
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+var tagMergeFuncs struct {
+	sync.RWMutex
+
+	funcs []TagMergeFunc
+}
+
+// RegisterTag registers a new tag, so it can be used in a [TagList] and carried through tag
+// events and muxers like the predefined TAG_* tags:
+//
+//	err := gst.RegisterTag("camera-id", gst.TagFlagMeta, gobject.TypeString, "camera id", "id of the recording camera", gst.TagMergeStringsWithComma)
+//
+// merge is used to combine multiple values of the tag into one, a nil merge function marks the
+// tag as a tag with a single value. Tags can not be unregistered and at most 32 tags with go
+// merge functions can be registered per process.
+//
+// see also https://gstreamer.freedesktop.org/documentation/gstreamer/gsttaglist.html#gst_tag_register
+func RegisterTag(name string, flag TagFlag, gtype gobject.Type, nick, blurb string, merge TagMergeFunc) error {
+	tagMergeFuncs.Lock()
+	defer tagMergeFuncs.Unlock()
+
+	if TagExists(name) {
+		return fmt.Errorf("%w: %s", ErrTagExists, name)
+	}
+
+	slot := -1
+
+	if merge != nil {
+		if len(tagMergeFuncs.funcs) >= maxTagMergeFuncs {
+			return fmt.Errorf("%w: cannot register %s", ErrTooManyTagMergeFuncs, name)
+		}
+
+		slot = len(tagMergeFuncs.funcs)
+		tagMergeFuncs.funcs = append(tagMergeFuncs.funcs, merge)
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cnick := C.CString(nick)
+	defer C.free(unsafe.Pointer(cnick))
+	cblurb := C.CString(blurb)
+	defer C.free(unsafe.Pointer(cblurb))
+
+	// gst_tag_register interns the strings, so they can be freed afterwards
+	C._gogst_gst1_tag_register(cname, C.GstTagFlag(flag), C.GType(gtype), cnick, cblurb, C.int(slot))
+
+	return nil
+}
+
+// tagMergeFunc returns the go merge function of the slot
+func tagMergeFunc(slot int) TagMergeFunc {
+	tagMergeFuncs.RLock()
+	defer tagMergeFuncs.RUnlock()
+
+	return tagMergeFuncs.funcs[slot]
+}
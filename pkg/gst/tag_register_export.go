@@ -0,0 +1,39 @@
+package gst
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+)
+
+// #include <gst/gst.h>
+import "C"
+
+//export _gogst_gst1_TagMergeFunc
+func _gogst_gst1_TagMergeFunc(slot C.int, dest *C.GValue, src *C.GValue) {
+	fn := tagMergeFunc(int(slot))
+
+	// src is a GstValueList containing all values of the tag
+	n := C.gst_value_list_get_size(src)
+	values := make([]any, 0, int(n))
+
+	for i := range n {
+		value := C.gst_value_list_get_value(src, i)
+		values = append(values, gobject.ValueFromNative(unsafe.Pointer(value)).GoValue())
+	}
+
+	// a nil result falls back to the first value
+	merged := C.gst_value_list_get_value(src, 0)
+
+	result := fn(values)
+	if result != nil {
+		value := gobject.NewValue(result)
+		defer runtime.KeepAlive(value)
+
+		merged = (*C.GValue)(gobject.UnsafeValueToGlibNone(value))
+	}
+
+	C.g_value_init(dest, merged.g_type)
+	C.g_value_copy(merged, dest)
+}
@@ -0,0 +1,50 @@
+package gst_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+func TestRegisterTag(t *testing.T) {
+	gst.Init()
+
+	err := gst.RegisterTag("go-gst-camera-id", gst.TagFlagMeta, gobject.TypeString, "camera id", "id of the camera", gst.TagMergeStringsWithComma)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gst.RegisterTag("go-gst-frame-count", gst.TagFlagMeta, gobject.TypeUint, "frames", "sum of the frames", func(values []any) any {
+		var sum uint32
+
+		for _, v := range values {
+			sum += v.(uint32)
+		}
+
+		return sum
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gst.RegisterTag("go-gst-camera-id", gst.TagFlagMeta, gobject.TypeString, "", "", nil)
+	if !errors.Is(err, gst.ErrTagExists) {
+		t.Fatalf("expected ErrTagExists, got %v", err)
+	}
+
+	list := gst.NewTagListEmpty()
+	list.AddValue(gst.TagMergeAppend, "go-gst-camera-id", gobject.NewValue("cam-1"))
+	list.AddValue(gst.TagMergeAppend, "go-gst-camera-id", gobject.NewValue("cam-2"))
+	list.AddValue(gst.TagMergeAppend, "go-gst-frame-count", gobject.NewValue(uint32(10)))
+	list.AddValue(gst.TagMergeAppend, "go-gst-frame-count", gobject.NewValue(uint32(5)))
+
+	if id, ok := list.GetString("go-gst-camera-id"); !ok || id != "cam-1, cam-2" {
+		t.Fatalf("unexpected merged camera id %q", id)
+	}
+
+	if n, ok := list.GetUint("go-gst-frame-count"); !ok || n != 15 {
+		t.Fatalf("unexpected merged frame count %d", n)
+	}
+}
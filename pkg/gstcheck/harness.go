@@ -0,0 +1,78 @@
+package gstcheck
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-check-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/check/check.h>
+import "C"
+
+// the harness constructors are not introspectable, so they are implemented manually. The harness
+// is freed by [Harness.Teardown], so no finalizer is attached.
+
+// NewHarness wraps gst_harness_new
+//
+// Creates a harness for the element with the given factory name and links its "sink" and "src"
+// pads. [Harness.Teardown] must be called when the harness is not needed anymore.
+func NewHarness(elementName string) *Harness {
+	var carg1 *C.gchar     // in, none, string
+	var cret *C.GstHarness // return, full, converted
+
+	carg1 = (*C.gchar)(unsafe.Pointer(C.CString(elementName)))
+	defer C.free(unsafe.Pointer(carg1))
+
+	cret = C.gst_harness_new(carg1)
+	runtime.KeepAlive(elementName)
+
+	return UnsafeHarnessFromGlibBorrow(unsafe.Pointer(cret))
+}
+
+// NewHarnessParse wraps gst_harness_new_parse
+//
+// Creates a harness for a bin described by the launch line. [Harness.Teardown] must be called
+// when the harness is not needed anymore.
+func NewHarnessParse(launchline string) *Harness {
+	var carg1 *C.gchar     // in, none, string
+	var cret *C.GstHarness // return, full, converted
+
+	carg1 = (*C.gchar)(unsafe.Pointer(C.CString(launchline)))
+	defer C.free(unsafe.Pointer(carg1))
+
+	cret = C.gst_harness_new_parse(carg1)
+	runtime.KeepAlive(launchline)
+
+	return UnsafeHarnessFromGlibBorrow(unsafe.Pointer(cret))
+}
+
+// NewHarnessWithElement wraps gst_harness_new_with_element
+//
+// Creates a harness for the element and links the pads with the given names. An empty pad name
+// skips linking that pad. [Harness.Teardown] must be called when the harness is not needed anymore.
+func NewHarnessWithElement(element gst.Element, sinkPadName string, srcPadName string) *Harness {
+	var carg1 C.gpointer   // in, none, converted
+	var carg2 *C.gchar     // in, none, string, nullable
+	var carg3 *C.gchar     // in, none, string, nullable
+	var cret *C.GstHarness // return, full, converted
+
+	carg1 = C.gpointer(gst.UnsafeElementToGlibNone(element))
+	if sinkPadName != "" {
+		carg2 = (*C.gchar)(unsafe.Pointer(C.CString(sinkPadName)))
+		defer C.free(unsafe.Pointer(carg2))
+	}
+	if srcPadName != "" {
+		carg3 = (*C.gchar)(unsafe.Pointer(C.CString(srcPadName)))
+		defer C.free(unsafe.Pointer(carg3))
+	}
+
+	cret = C.gst_harness_new_with_element(carg1, carg2, carg3)
+	runtime.KeepAlive(element)
+	runtime.KeepAlive(sinkPadName)
+	runtime.KeepAlive(srcPadName)
+
+	return UnsafeHarnessFromGlibBorrow(unsafe.Pointer(cret))
+}
@@ -0,0 +1,296 @@
+package gstcontroller
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-controller-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/controller/controller.h>
+//
+// static gboolean _gogst_gstcontroller1_is_controllable(GObject *object, const char *property) {
+//   GParamSpec *pspec = g_object_class_find_property(G_OBJECT_GET_CLASS(object), property);
+//
+//   return pspec != NULL && (pspec->flags & GST_PARAM_CONTROLLABLE) != 0;
+// }
+import "C"
+
+var (
+	// ErrNotControllable is returned by [Automate] if the property does not exist or is not
+	// controllable.
+	ErrNotControllable = errors.New("property is not controllable")
+	// ErrInvalidControlPoints is returned by [Automate] for empty control points or normalized
+	// values outside of [0, 1].
+	ErrInvalidControlPoints = errors.New("invalid control points")
+)
+
+// NewControlPoint creates the value of a controlled property at a timestamp of the running
+// time of the pipeline.
+func NewControlPoint(timestamp gst.ClockTime, value float64) *ControlPoint {
+	var cp C.GstControlPoint
+
+	cp.timestamp = C.GstClockTime(timestamp)
+	cp.value = C.gdouble(value)
+
+	return UnsafeControlPointFromGlibFull(unsafe.Pointer(C.gst_control_point_copy(&cp)))
+}
+
+// Timestamp returns the time of the control point.
+func (cp *ControlPoint) Timestamp() gst.ClockTime {
+	return gst.ClockTime(cp.native.timestamp)
+}
+
+// Value returns the value of the control point.
+func (cp *ControlPoint) Value() float64 {
+	return float64(cp.native.value)
+}
+
+// controlPointValue is a control point that is stored by Go control sources
+type controlPointValue struct {
+	timestamp gst.ClockTime
+	value     float64
+}
+
+func controlPointValues(points []*ControlPoint) []controlPointValue {
+	values := make([]controlPointValue, 0, len(points))
+
+	for _, cp := range points {
+		values = append(values, controlPointValue{timestamp: cp.Timestamp(), value: cp.Value()})
+	}
+
+	return values
+}
+
+// AutomationMode defines how the values between two control points are computed.
+type AutomationMode int
+
+const (
+	// AutomationStep keeps the value of a control point until the next one.
+	AutomationStep AutomationMode = iota
+	// AutomationLinear interpolates linearly between the control points.
+	AutomationLinear
+	// AutomationCubic uses a cubic spline through the control points, it may overshoot.
+	AutomationCubic
+	// AutomationCubicMonotonic uses a cubic spline that does not overshoot.
+	AutomationCubicMonotonic
+	// AutomationEaseIn starts slowly and accelerates towards the next control point.
+	AutomationEaseIn
+	// AutomationEaseOut starts fast and decelerates towards the next control point.
+	AutomationEaseOut
+	// AutomationEaseInOut accelerates in the first and decelerates in the second half.
+	AutomationEaseInOut
+)
+
+func (m AutomationMode) String() string {
+	switch m {
+	case AutomationStep:
+		return "step"
+	case AutomationLinear:
+		return "linear"
+	case AutomationCubic:
+		return "cubic"
+	case AutomationCubicMonotonic:
+		return "cubic-monotonic"
+	case AutomationEaseIn:
+		return "ease-in"
+	case AutomationEaseOut:
+		return "ease-out"
+	case AutomationEaseInOut:
+		return "ease-in-out"
+	default:
+		return fmt.Sprintf("AutomationMode(%d)", int(m))
+	}
+}
+
+// Automate binds a control source with the control points to the property of the object. The
+// values are normalized, 0 is the minimum and 1 the maximum of the property. The returned
+// control source can be used to read back the curve with [ControlPoints].
+//
+// The step, linear and cubic modes use an [InterpolationControlSource], the easing modes a Go
+// control source, see [NewEasingControlSource].
+func Automate(object gst.Object, property string, points []*ControlPoint, mode AutomationMode) (gst.ControlSource, error) {
+	for _, cp := range points {
+		if v := cp.Value(); v < 0 || v > 1 {
+			return nil, fmt.Errorf("%w: normalized value %v at %s is not between 0 and 1", ErrInvalidControlPoints, v, time.Duration(cp.Timestamp()))
+		}
+	}
+
+	return automate(object, property, points, mode, NewDirectControlBinding)
+}
+
+// AutomateAbsolute is like [Automate], but the values of the control points are set on the
+// property as they are, without mapping them onto the range of the property.
+func AutomateAbsolute(object gst.Object, property string, points []*ControlPoint, mode AutomationMode) (gst.ControlSource, error) {
+	return automate(object, property, points, mode, NewDirectControlBindingAbsolute)
+}
+
+func automate(object gst.Object, property string, points []*ControlPoint, mode AutomationMode, newBinding func(gst.Object, string, gst.ControlSource) gst.ControlBinding) (gst.ControlSource, error) {
+	cproperty := C.CString(property)
+	defer C.free(unsafe.Pointer(cproperty))
+
+	if C._gogst_gstcontroller1_is_controllable((*C.GObject)(gst.UnsafeObjectToGlibNone(object)), cproperty) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotControllable, property)
+	}
+
+	cs, err := NewAutomationControlSource(points, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !object.AddControlBinding(newBinding(object, property, cs)) {
+		return nil, fmt.Errorf("%w: could not bind %s", ErrNotControllable, property)
+	}
+
+	return cs, nil
+}
+
+// NewAutomationControlSource creates a control source with the control points in the given
+// mode, without binding it to a property.
+func NewAutomationControlSource(points []*ControlPoint, mode AutomationMode) (gst.ControlSource, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: no control points", ErrInvalidControlPoints)
+	}
+
+	var interpolation InterpolationMode
+
+	switch mode {
+	case AutomationStep:
+		interpolation = InterpolationModeNone
+	case AutomationLinear:
+		interpolation = InterpolationModeLinear
+	case AutomationCubic:
+		interpolation = InterpolationModeCubic
+	case AutomationCubicMonotonic:
+		interpolation = InterpolationModeCubicMonotonic
+	case AutomationEaseIn, AutomationEaseOut, AutomationEaseInOut:
+		return NewEasingControlSource(mode, points...), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %s", ErrInvalidControlPoints, mode)
+	}
+
+	cs := NewInterpolationControlSource()
+	cs.SetObjectProperty("mode", interpolation)
+
+	timed := cs.(TimedValueControlSource)
+
+	for _, cp := range points {
+		timed.Set(cp.Timestamp(), cp.Value())
+	}
+
+	return cs, nil
+}
+
+// NewEasingControlSource creates a control source that eases between the control points with
+// one of the easing modes [AutomationEaseIn], [AutomationEaseOut] or [AutomationEaseInOut],
+// other modes interpolate linearly. There is no value before the first control point, after
+// the last one its value is kept.
+func NewEasingControlSource(mode AutomationMode, points ...*ControlPoint) gst.ControlSource {
+	curve := &easingCurve{
+		ease:   easingFunc(mode),
+		points: controlPointValues(points),
+	}
+
+	slices.SortStableFunc(curve.points, func(a, b controlPointValue) int {
+		return cmp.Compare(a.timestamp, b.timestamp)
+	})
+
+	return newGoControlSource(curve)
+}
+
+// ControlPoints returns the control points of a control source created by [Automate] or any
+// other [TimedValueControlSource], sorted by time. It returns nil for other control sources.
+func ControlPoints(cs gst.ControlSource) []*ControlPoint {
+	if source, ok := loadGoControlSource(cs); ok {
+		curve, ok := source.valuer.(*easingCurve)
+		if !ok {
+			return nil
+		}
+
+		points := make([]*ControlPoint, 0, len(curve.points))
+		for _, p := range curve.points {
+			points = append(points, NewControlPoint(p.timestamp, p.value))
+		}
+
+		return points
+	}
+
+	timed, ok := cs.(TimedValueControlSource)
+	if !ok {
+		return nil
+	}
+
+	list := C.gst_timed_value_control_source_get_all((*C.GstTimedValueControlSource)(UnsafeTimedValueControlSourceToGlibNone(timed)))
+	defer C.g_list_free(list)
+
+	var points []*ControlPoint
+
+	for l := list; l != nil; l = l.next {
+		// the list contains the control points of the source, which are copied
+		points = append(points, UnsafeControlPointFromGlibNone(unsafe.Pointer(l.data)))
+	}
+
+	return points
+}
+
+// easingCurve eases between sorted control points
+type easingCurve struct {
+	ease   func(float64) float64
+	points []controlPointValue
+}
+
+func (c *easingCurve) value(timestamp gst.ClockTime) (float64, bool) {
+	// index of the first control point after timestamp
+	i, _ := slices.BinarySearchFunc(c.points, timestamp, func(p controlPointValue, t gst.ClockTime) int {
+		if p.timestamp <= t {
+			return -1
+		}
+
+		return 1
+	})
+
+	switch {
+	case i == 0:
+		return 0, false
+	case i == len(c.points):
+		return c.points[i-1].value, true
+	}
+
+	a, b := c.points[i-1], c.points[i]
+	f := c.ease(float64(timestamp-a.timestamp) / float64(b.timestamp-a.timestamp))
+
+	return a.value + (b.value-a.value)*f, true
+}
+
+// easingFunc returns the cubic easing function of the mode, mapping [0, 1] onto [0, 1]
+func easingFunc(mode AutomationMode) func(float64) float64 {
+	switch mode {
+	case AutomationEaseIn:
+		return func(f float64) float64 {
+			return f * f * f
+		}
+	case AutomationEaseOut:
+		return func(f float64) float64 {
+			return 1 - math.Pow(1-f, 3)
+		}
+	case AutomationEaseInOut:
+		return func(f float64) float64 {
+			if f < 0.5 {
+				return 4 * f * f * f
+			}
+
+			return 1 - math.Pow(-2*f+2, 3)/2
+		}
+	default:
+		return func(f float64) float64 {
+			return f
+		}
+	}
+}
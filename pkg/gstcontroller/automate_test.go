@@ -0,0 +1,66 @@
+package gstcontroller_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstcontroller"
+)
+
+func TestAutomate(t *testing.T) {
+	gst.Init()
+
+	points := []*gstcontroller.ControlPoint{
+		gstcontroller.NewControlPoint(0, 0),
+		gstcontroller.NewControlPoint(gst.ClockTime(time.Second), 0.5),
+	}
+
+	for _, mode := range []gstcontroller.AutomationMode{gstcontroller.AutomationLinear, gstcontroller.AutomationEaseIn} {
+		t.Run(mode.String(), func(t *testing.T) {
+			volume := gst.ElementFactoryMake("volume", "")
+
+			cs, err := gstcontroller.Automate(volume, "volume", points, mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := gstcontroller.ControlPoints(cs)
+			if len(got) != len(points) {
+				t.Fatalf("expected %d control points, got %d", len(points), len(got))
+			}
+
+			for i, cp := range got {
+				if cp.Timestamp() != points[i].Timestamp() || cp.Value() != points[i].Value() {
+					t.Errorf("control point %d: expected %v at %v, got %v at %v", i, points[i].Value(), points[i].Timestamp(), cp.Value(), cp.Timestamp())
+				}
+			}
+
+			// the volume property ranges from 0 to 10
+			want := map[gstcontroller.AutomationMode]float64{
+				gstcontroller.AutomationLinear: 2.5,
+				gstcontroller.AutomationEaseIn: 0.625,
+			}[mode]
+
+			volume.SyncValues(gst.ClockTime(500 * time.Millisecond))
+
+			if got := volume.ObjectProperty("volume").(float64); math.Abs(got-want) > 1e-9 {
+				t.Fatalf("expected volume %v, got %v", want, got)
+			}
+		})
+	}
+
+	volume := gst.ElementFactoryMake("volume", "")
+
+	if _, err := gstcontroller.Automate(volume, "name", points, gstcontroller.AutomationLinear); !errors.Is(err, gstcontroller.ErrNotControllable) {
+		t.Fatalf("expected ErrNotControllable, got %v", err)
+	}
+
+	invalid := []*gstcontroller.ControlPoint{gstcontroller.NewControlPoint(0, 2)}
+
+	if _, err := gstcontroller.Automate(volume, "volume", invalid, gstcontroller.AutomationLinear); !errors.Is(err, gstcontroller.ErrInvalidControlPoints) {
+		t.Fatalf("expected ErrInvalidControlPoints, got %v", err)
+	}
+}
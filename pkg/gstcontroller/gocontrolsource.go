@@ -0,0 +1,119 @@
+package gstcontroller
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-controller-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/controller/controller.h>
+//
+// extern gboolean _gogst_gstcontroller1_ControlSourceGetValue(GstControlSource*, GstClockTime, gdouble*);
+// extern gboolean _gogst_gstcontroller1_ControlSourceGetValueArray(GstControlSource*, GstClockTime, GstClockTime, guint, gdouble*);
+//
+// static void _gogst_gstcontroller1_control_source_init(GstControlSource *source) {
+//   source->get_value = _gogst_gstcontroller1_ControlSourceGetValue;
+//   source->get_value_array = _gogst_gstcontroller1_ControlSourceGetValueArray;
+// }
+import "C"
+
+// controlValuer computes the values of a Go control source
+type controlValuer interface {
+	value(timestamp gst.ClockTime) (float64, bool)
+}
+
+// ControlSourceFunc returns the value of a control source for the running time ts, false if
+// there is no value at that time.
+type ControlSourceFunc func(ts gst.ClockTime) (float64, bool)
+
+func (fn ControlSourceFunc) value(ts gst.ClockTime) (float64, bool) {
+	return fn(ts)
+}
+
+// NewFuncControlSource creates a control source that gets its values from fn, e.g. an envelope
+// computed in Go. The source can be bound to a property with [NewDirectControlBinding].
+//
+// fn is called from the streaming threads and must be safe for concurrent use. When a whole
+// array of values is requested, e.g. by the volume element, the array is filled with a single
+// call from C into Go.
+func NewFuncControlSource(fn ControlSourceFunc) gst.ControlSource {
+	return newGoControlSource(fn)
+}
+
+// goControlSource is a GstControlSource subclass that gets its values from a controlValuer.
+// get_value and get_value_array are function pointers of the instance instead of virtual
+// methods, so they are set in the instance init.
+type goControlSource struct {
+	gst.ControlSourceInstance
+
+	valuer controlValuer
+}
+
+var goControlSourceType = sync.OnceValue(func() gobject.Type {
+	return gst.RegisterControlSourceSubClass(
+		"GoGstControlSource",
+		nil,
+		func() *goControlSource {
+			return &goControlSource{}
+		},
+		gst.ControlSourceOverrides[*goControlSource]{
+			ObjectOverrides: gst.ObjectOverrides[*goControlSource]{
+				InitiallyUnownedOverrides: gobject.InitiallyUnownedOverrides[*goControlSource]{
+					ObjectOverrides: gobject.ObjectOverrides[*goControlSource]{
+						InstanceInit: func(source *goControlSource) {
+							C._gogst_gstcontroller1_control_source_init((*C.GstControlSource)(gst.UnsafeControlSourceToGlibNone(source)))
+						},
+					},
+				},
+			},
+		},
+		nil,
+	)
+})
+
+// newGoControlSource creates a control source that gets its values from valuer
+func newGoControlSource(valuer controlValuer) gst.ControlSource {
+	obj := gobject.NewObjectWithProperties(goControlSourceType(), nil)
+
+	obj.UnsafeLoadInstanceFromPrivateData().(*goControlSource).valuer = valuer
+
+	return obj.(gst.ControlSource)
+}
+
+// loadGoControlSource returns the Go instance of cs, false if cs is not a Go control source
+func loadGoControlSource(cs gst.ControlSource) (*goControlSource, bool) {
+	if cs == nil {
+		return nil, false
+	}
+
+	instance := (*C.GTypeInstance)(gst.UnsafeControlSourceToGlibNone(cs))
+
+	if C.g_type_check_instance_is_a(instance, C.GType(goControlSourceType())) == 0 {
+		return nil, false
+	}
+
+	source, ok := cs.UnsafeLoadInstanceFromPrivateData().(*goControlSource)
+
+	return source, ok
+}
+
+// controlSourceValues fills values for the timestamps starting at timestamp in steps of
+// interval, it returns false if a value is missing
+func controlSourceValues(valuer controlValuer, timestamp, interval gst.ClockTime, values unsafe.Pointer, n int) bool {
+	out := unsafe.Slice((*float64)(values), n)
+
+	for i := range out {
+		v, ok := valuer.value(timestamp + gst.ClockTime(i)*interval)
+		if !ok {
+			return false
+		}
+
+		out[i] = v
+	}
+
+	return true
+}
@@ -0,0 +1,45 @@
+package gstcontroller
+
+import (
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-controller-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/controller/controller.h>
+import "C"
+
+//export _gogst_gstcontroller1_ControlSourceGetValue
+func _gogst_gstcontroller1_ControlSourceGetValue(carg0 *C.GstControlSource, carg1 C.GstClockTime, carg2 *C.gdouble) (cret C.gboolean) {
+	source := gst.UnsafeControlSourceFromGlibBorrow(unsafe.Pointer(carg0)).UnsafeLoadInstanceFromPrivateData().(*goControlSource)
+
+	if source.valuer == nil {
+		return C.FALSE
+	}
+
+	value, ok := source.valuer.value(gst.ClockTime(carg1))
+	if !ok {
+		return C.FALSE
+	}
+
+	*carg2 = C.gdouble(value)
+
+	return C.TRUE
+}
+
+//export _gogst_gstcontroller1_ControlSourceGetValueArray
+func _gogst_gstcontroller1_ControlSourceGetValueArray(carg0 *C.GstControlSource, carg1 C.GstClockTime, carg2 C.GstClockTime, carg3 C.guint, carg4 *C.gdouble) (cret C.gboolean) {
+	source := gst.UnsafeControlSourceFromGlibBorrow(unsafe.Pointer(carg0)).UnsafeLoadInstanceFromPrivateData().(*goControlSource)
+
+	if source.valuer == nil || carg4 == nil {
+		return C.FALSE
+	}
+
+	if controlSourceValues(source.valuer, gst.ClockTime(carg1), gst.ClockTime(carg2), unsafe.Pointer(carg4), int(carg3)) {
+		cret = C.TRUE
+	}
+
+	return cret
+}
@@ -0,0 +1,51 @@
+package gstcontroller_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstcontroller"
+)
+
+func TestFuncControlSource(t *testing.T) {
+	gst.Init()
+
+	// ramps up from 0 to 1 during the first second, no value after 2 seconds
+	cs := gstcontroller.NewFuncControlSource(func(ts gst.ClockTime) (float64, bool) {
+		d := time.Duration(ts)
+
+		if d > 2*time.Second {
+			return 0, false
+		}
+
+		return min(d.Seconds(), 1), true
+	})
+
+	if v, ok := cs.ControlSourceGetValue(gst.ClockTime(500 * time.Millisecond)); !ok || v != 0.5 {
+		t.Fatalf("unexpected value %v, %v", v, ok)
+	}
+
+	values := make([]float64, 5)
+
+	if !cs.ControlSourceGetValueArray(0, gst.ClockTime(250*time.Millisecond), values) {
+		t.Fatal("expected values")
+	}
+
+	if want := []float64{0, 0.25, 0.5, 0.75, 1}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("expected %v, got %v", want, values)
+	}
+
+	if cs.ControlSourceGetValueArray(gst.ClockTime(time.Second), gst.ClockTime(time.Second), values) {
+		t.Fatal("expected missing values after 2 seconds")
+	}
+
+	volume := gst.ElementFactoryMake("volume", "")
+	volume.AddControlBinding(gstcontroller.NewDirectControlBindingAbsolute(volume, "volume", cs))
+	volume.SyncValues(gst.ClockTime(250 * time.Millisecond))
+
+	if v := volume.ObjectProperty("volume").(float64); v != 0.25 {
+		t.Fatalf("expected volume 0.25, got %v", v)
+	}
+}
@@ -0,0 +1,200 @@
+package gstmpegts
+
+import (
+	"strings"
+	"time"
+)
+
+// #cgo pkg-config: gstreamer-mpegts-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/mpegts/mpegts.h>
+import "C"
+
+// gpsEpoch is the start of the ATSC system time
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// GPSTime converts ATSC GPS seconds to UTC. gpsUTCOffset is the number of leap seconds signalled
+// in the system time table.
+func GPSTime(seconds uint32, gpsUTCOffset uint8) time.Time {
+	return gpsEpoch.Add(time.Duration(seconds)*time.Second - time.Duration(gpsUTCOffset)*time.Second)
+}
+
+// ATSCText is a string of an ATSC multiple string structure.
+type ATSCText struct {
+	Language string
+	Text     string
+}
+
+// MGTTableEntry describes a table announced in the ATSC master guide table.
+type MGTTableEntry struct {
+	// Type is the table type, 0x0100-0x017f are EIT-0 to EIT-127 and 0x0200-0x027f the
+	// corresponding event ETTs.
+	Type        uint16
+	PID         uint16
+	Version     uint8
+	Size        uint32
+	Descriptors []DescriptorValue
+}
+
+// MGTTable is the ATSC master guide table.
+type MGTTable struct {
+	SectionHeader
+	ProtocolVersion uint8
+	Tables          []MGTTableEntry
+	Descriptors     []DescriptorValue
+}
+
+// ATSCEITTableEvent is an event of the ATSC event information table.
+type ATSCEITTableEvent struct {
+	EventID uint16
+	// StartGPS is the start in GPS seconds, see [GPSTime].
+	StartGPS uint32
+	Duration time.Duration
+	// ETMLocation is non zero if an extended text message for the event exists.
+	ETMLocation uint8
+	Title       []ATSCText
+	Descriptors []DescriptorValue
+}
+
+// ATSCEITTable is the ATSC event information table.
+type ATSCEITTable struct {
+	SectionHeader
+	ProtocolVersion uint8
+	Events          []ATSCEITTableEvent
+}
+
+// SourceID returns the source id of the virtual channel the events belong to.
+func (t *ATSCEITTable) SourceID() uint16 { return t.TableIDExtension }
+
+// ETTTable is the ATSC extended text table.
+type ETTTable struct {
+	SectionHeader
+	ProtocolVersion uint8
+	ETMID           uint32
+	Text            []ATSCText
+}
+
+// SourceID returns the source id of the virtual channel of the text.
+func (t *ETTTable) SourceID() uint16 { return uint16(t.ETMID >> 16) }
+
+// EventID returns the id of the event the text describes, it returns false for channel texts.
+func (t *ETTTable) EventID() (uint16, bool) {
+	if t.ETMID&0x03 != 0x02 {
+		return 0, false
+	}
+
+	return uint16(t.ETMID>>2) & 0x3fff, true
+}
+
+// STTTable is the ATSC system time table.
+type STTTable struct {
+	SectionHeader
+	ProtocolVersion uint8
+	SystemTime      uint32
+	GPSUTCOffset    uint8
+	// DaylightSaving is true if daylight saving time is in effect. DSDayOfMonth and DSHour give
+	// the local day of month and hour of the next transition, they are zero if none is scheduled.
+	DaylightSaving bool
+	DSDayOfMonth   uint8
+	DSHour         uint8
+	Descriptors    []DescriptorValue
+}
+
+// UTC returns the system time in UTC.
+func (t *STTTable) UTC() time.Time { return GPSTime(t.SystemTime, t.GPSUTCOffset) }
+
+// atscTexts converts a list of multiple string structures, the segments of each string are
+// decompressed and converted to UTF-8 by libgstmpegts
+func atscTexts(arr *C.GPtrArray) []ATSCText {
+	var texts []ATSCText
+
+	for _, m := range ptrArray[C.GstMpegtsAtscMultString](arr) {
+		var sb strings.Builder
+
+		for _, segment := range ptrArray[C.GstMpegtsAtscStringSegment](m.segments) {
+			sb.WriteString(cString(C.gst_mpegts_atsc_string_segment_get_string(segment)))
+		}
+
+		texts = append(texts, ATSCText{
+			Language: strings.TrimRight(cString(&m.iso_639_langcode[0]), "\x00 "),
+			Text:     sb.String(),
+		})
+	}
+
+	return texts
+}
+
+func decodeMGT(h SectionHeader, mgt *C.GstMpegtsAtscMGT) DecodedSection {
+	if mgt == nil {
+		return nil
+	}
+
+	t := &MGTTable{
+		SectionHeader:   h,
+		ProtocolVersion: uint8(mgt.protocol_version),
+		Descriptors:     descriptorValues(mgt.descriptors),
+	}
+
+	for _, e := range ptrArray[C.GstMpegtsAtscMGTTable](mgt.tables) {
+		t.Tables = append(t.Tables, MGTTableEntry{
+			Type:        uint16(e.table_type),
+			PID:         uint16(e.pid),
+			Version:     uint8(e.version_number),
+			Size:        uint32(e.number_bytes),
+			Descriptors: descriptorValues(e.descriptors),
+		})
+	}
+
+	return t
+}
+
+func decodeATSCEIT(h SectionHeader, eit *C.GstMpegtsAtscEIT) DecodedSection {
+	if eit == nil {
+		return nil
+	}
+
+	t := &ATSCEITTable{SectionHeader: h, ProtocolVersion: uint8(eit.protocol_version)}
+
+	for _, e := range ptrArray[C.GstMpegtsAtscEITEvent](eit.events) {
+		t.Events = append(t.Events, ATSCEITTableEvent{
+			EventID:     uint16(e.event_id),
+			StartGPS:    uint32(e.start_time),
+			Duration:    time.Duration(e.length_in_seconds) * time.Second,
+			ETMLocation: uint8(e.etm_location),
+			Title:       atscTexts(e.titles),
+			Descriptors: descriptorValues(e.descriptors),
+		})
+	}
+
+	return t
+}
+
+func decodeETT(h SectionHeader, ett *C.GstMpegtsAtscETT) DecodedSection {
+	if ett == nil {
+		return nil
+	}
+
+	return &ETTTable{
+		SectionHeader:   h,
+		ProtocolVersion: uint8(ett.protocol_version),
+		ETMID:           uint32(ett.etm_id),
+		Text:            atscTexts(ett.messages),
+	}
+}
+
+func decodeSTT(h SectionHeader, stt *C.GstMpegtsAtscSTT) DecodedSection {
+	if stt == nil {
+		return nil
+	}
+
+	return &STTTable{
+		SectionHeader:   h,
+		ProtocolVersion: uint8(stt.protocol_version),
+		SystemTime:      uint32(stt.system_time),
+		GPSUTCOffset:    uint8(stt.gps_utc_offset),
+		DaylightSaving:  stt.ds_status != 0,
+		DSDayOfMonth:    uint8(stt.ds_dayofmonth),
+		DSHour:          uint8(stt.ds_hour),
+		Descriptors:     descriptorValues(stt.descriptors),
+	}
+}
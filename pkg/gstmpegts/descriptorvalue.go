@@ -0,0 +1,492 @@
+package gstmpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-mpegts-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <stdlib.h>
+// #include <gst/mpegts/mpegts.h>
+import "C"
+
+// DescriptorValue is a descriptor decoded by [ParseDescriptors]. The concrete types are the
+// *DescriptorValue structs of this package, descriptors with an unknown tag are returned as
+// [UnknownDescriptorValue].
+type DescriptorValue interface {
+	// DescriptorTag returns the tag of the descriptor.
+	DescriptorTag() uint8
+}
+
+// descriptor tags of ISO/IEC 13818-1 and ETSI EN 300 468
+const (
+	descTagRegistration     = 0x05
+	descTagCA               = 0x09
+	descTagISO639Language   = 0x0a
+	descTagNetworkName      = 0x40
+	descTagServiceList      = 0x41
+	descTagService          = 0x48
+	descTagShortEvent       = 0x4d
+	descTagExtendedEvent    = 0x4e
+	descTagComponent        = 0x50
+	descTagStreamIdentifier = 0x52
+	descTagContent          = 0x54
+	descTagParentalRating   = 0x55
+	descTagLocalTimeOffset  = 0x58
+	descTagSubtitling       = 0x59
+)
+
+// RegistrationDescriptorValue is the registration descriptor (tag 0x05).
+type RegistrationDescriptorValue struct {
+	// FormatIdentifier is the registered four character code, e.g. "CUEI" or "HEVC".
+	FormatIdentifier string
+	AdditionalInfo   []byte
+}
+
+// CADescriptorValue is the conditional access descriptor (tag 0x09).
+type CADescriptorValue struct {
+	SystemID    uint16
+	PID         uint16
+	PrivateData []byte
+}
+
+// Language is an entry of the ISO 639 language descriptor.
+type Language struct {
+	// Code is the three letter ISO 639-2 code.
+	Code      string
+	AudioType uint8
+}
+
+// LanguageDescriptorValue is the ISO 639 language descriptor (tag 0x0a).
+type LanguageDescriptorValue struct {
+	Languages []Language
+}
+
+// NetworkNameDescriptorValue is the DVB network name descriptor (tag 0x40).
+type NetworkNameDescriptorValue struct {
+	Name string
+}
+
+// ServiceListItem is an entry of the DVB service list descriptor.
+type ServiceListItem struct {
+	ServiceID uint16
+	Type      uint8
+}
+
+// ServiceListDescriptorValue is the DVB service list descriptor (tag 0x41).
+type ServiceListDescriptorValue struct {
+	Services []ServiceListItem
+}
+
+// ServiceDescriptorValue is the DVB service descriptor (tag 0x48).
+type ServiceDescriptorValue struct {
+	Type     uint8
+	Provider string
+	Name     string
+}
+
+// ShortEventDescriptorValue is the DVB short event descriptor (tag 0x4d).
+type ShortEventDescriptorValue struct {
+	Language string
+	Name     string
+	Text     string
+}
+
+// ExtendedEventEntry is an item of the DVB extended event descriptor.
+type ExtendedEventEntry struct {
+	Description string
+	Item        string
+}
+
+// ExtendedEventDescriptorValue is the DVB extended event descriptor (tag 0x4e). Long texts are
+// split over multiple descriptors, Number and LastNumber give the position in the sequence.
+type ExtendedEventDescriptorValue struct {
+	Number     uint8
+	LastNumber uint8
+	Language   string
+	Items      []ExtendedEventEntry
+	Text       string
+}
+
+// ComponentDescriptorValue is the DVB component descriptor (tag 0x50).
+type ComponentDescriptorValue struct {
+	StreamContentExt uint8
+	StreamContent    uint8
+	ComponentType    uint8
+	ComponentTag     uint8
+	Language         string
+	Text             string
+}
+
+// StreamIdentifierDescriptorValue is the DVB stream identifier descriptor (tag 0x52).
+type StreamIdentifierDescriptorValue struct {
+	ComponentTag uint8
+}
+
+// ContentNibbles is an entry of the DVB content descriptor, the nibbles give the genre of the event.
+type ContentNibbles struct {
+	Level1 uint8
+	Level2 uint8
+	User   uint8
+}
+
+// ContentDescriptorValue is the DVB content descriptor (tag 0x54).
+type ContentDescriptorValue struct {
+	Contents []ContentNibbles
+}
+
+// ParentalRating is an entry of the DVB parental rating descriptor.
+type ParentalRating struct {
+	Country string
+	// Rating is the minimum age minus 3, 0 is undefined.
+	Rating uint8
+}
+
+// ParentalRatingDescriptorValue is the DVB parental rating descriptor (tag 0x55).
+type ParentalRatingDescriptorValue struct {
+	Ratings []ParentalRating
+}
+
+// LocalTimeOffset is an entry of the DVB local time offset descriptor.
+type LocalTimeOffset struct {
+	Country        string
+	RegionID       uint8
+	Offset         time.Duration
+	TimeOfChange   time.Time
+	NextTimeOffset time.Duration
+}
+
+// LocalTimeOffsetDescriptorValue is the DVB local time offset descriptor (tag 0x58).
+type LocalTimeOffsetDescriptorValue struct {
+	Offsets []LocalTimeOffset
+}
+
+// Subtitling is an entry of the DVB subtitling descriptor.
+type Subtitling struct {
+	Language          string
+	Type              uint8
+	CompositionPageID uint16
+	AncillaryPageID   uint16
+}
+
+// SubtitlingDescriptorValue is the DVB subtitling descriptor (tag 0x59).
+type SubtitlingDescriptorValue struct {
+	Subtitles []Subtitling
+}
+
+// UnknownDescriptorValue is a descriptor that is not decoded by this package.
+type UnknownDescriptorValue struct {
+	Tag  uint8
+	Data []byte
+}
+
+func (RegistrationDescriptorValue) DescriptorTag() uint8     { return descTagRegistration }
+func (CADescriptorValue) DescriptorTag() uint8               { return descTagCA }
+func (LanguageDescriptorValue) DescriptorTag() uint8         { return descTagISO639Language }
+func (NetworkNameDescriptorValue) DescriptorTag() uint8      { return descTagNetworkName }
+func (ServiceListDescriptorValue) DescriptorTag() uint8      { return descTagServiceList }
+func (ServiceDescriptorValue) DescriptorTag() uint8          { return descTagService }
+func (ShortEventDescriptorValue) DescriptorTag() uint8       { return descTagShortEvent }
+func (ExtendedEventDescriptorValue) DescriptorTag() uint8    { return descTagExtendedEvent }
+func (ComponentDescriptorValue) DescriptorTag() uint8        { return descTagComponent }
+func (StreamIdentifierDescriptorValue) DescriptorTag() uint8 { return descTagStreamIdentifier }
+func (ContentDescriptorValue) DescriptorTag() uint8          { return descTagContent }
+func (ParentalRatingDescriptorValue) DescriptorTag() uint8   { return descTagParentalRating }
+func (LocalTimeOffsetDescriptorValue) DescriptorTag() uint8  { return descTagLocalTimeOffset }
+func (SubtitlingDescriptorValue) DescriptorTag() uint8       { return descTagSubtitling }
+func (d UnknownDescriptorValue) DescriptorTag() uint8        { return d.Tag }
+
+// ParseDescriptors decodes a descriptor loop with libgstmpegts. Descriptors with an unknown tag or
+// an invalid payload are returned as [UnknownDescriptorValue], an error is only returned if the
+// loop itself is truncated.
+func ParseDescriptors(data []byte) ([]DescriptorValue, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	C.gst_mpegts_initialize()
+
+	cdata := C.CBytes(data)
+	defer C.free(cdata)
+
+	descriptors := C.gst_mpegts_parse_descriptors((*C.guint8)(cdata), C.gsize(len(data)))
+	if descriptors == nil {
+		return nil, fmt.Errorf("%w: truncated descriptor", ErrInvalidSection)
+	}
+	defer C.g_ptr_array_unref(descriptors)
+
+	return descriptorValues(descriptors), nil
+}
+
+// descriptorValues decodes the descriptors of a GPtrArray parsed by libgstmpegts
+func descriptorValues(arr *C.GPtrArray) []DescriptorValue {
+	var descriptors []DescriptorValue
+
+	for _, d := range ptrArray[C.GstMpegtsDescriptor](arr) {
+		descriptors = append(descriptors, decodeDescriptor(d))
+	}
+
+	return descriptors
+}
+
+// decodeDescriptor decodes a single descriptor, the data of the descriptor starts with the tag
+func decodeDescriptor(cdesc *C.GstMpegtsDescriptor) DescriptorValue {
+	tag := uint8(cdesc.tag)
+	payload := unsafe.Slice((*byte)(unsafe.Pointer(cdesc.data)), 2+int(cdesc.length))[2:]
+
+	d, ok := parseDescriptor(cdesc, payload)
+	if !ok {
+		return UnknownDescriptorValue{Tag: tag, Data: cloneBytes(payload)}
+	}
+
+	return d
+}
+
+// parseDescriptor decodes the descriptor with the parser of libgstmpegts for its tag
+func parseDescriptor(cdesc *C.GstMpegtsDescriptor, p []byte) (DescriptorValue, bool) {
+	desc := UnsafeDescriptorFromGlibBorrow(unsafe.Pointer(cdesc))
+
+	switch uint8(cdesc.tag) {
+	case descTagRegistration:
+		var id C.guint32
+		var info *C.guint8
+		var infoLen C.gsize
+
+		if C.gst_mpegts_descriptor_parse_registration(cdesc, &id, &info, &infoLen) == 0 {
+			return nil, false
+		}
+
+		return RegistrationDescriptorValue{
+			FormatIdentifier: string([]byte{byte(id >> 24), byte(id >> 16), byte(id >> 8), byte(id)}),
+			AdditionalInfo:   cBytes(info, infoLen),
+		}, true
+	case descTagCA:
+		var systemID, pid C.guint16
+		var data *C.guint8
+		var dataLen C.gsize
+
+		if C.gst_mpegts_descriptor_parse_ca(cdesc, &systemID, &pid, &data, &dataLen) == 0 {
+			return nil, false
+		}
+
+		return CADescriptorValue{SystemID: uint16(systemID), PID: uint16(pid), PrivateData: cBytes(data, dataLen)}, true
+	case descTagISO639Language:
+		var d LanguageDescriptorValue
+
+		for i := range desc.ParseIso639LanguageNb() {
+			code, audioType, ok := desc.ParseIso639LanguageIdx(i)
+			if !ok {
+				return nil, false
+			}
+
+			d.Languages = append(d.Languages, Language{Code: code, AudioType: uint8(audioType)})
+		}
+
+		return d, true
+	case descTagNetworkName:
+		name, ok := desc.ParseDvbNetworkName()
+
+		return NetworkNameDescriptorValue{Name: name}, ok
+	case descTagServiceList:
+		var list *C.GPtrArray
+
+		if C.gst_mpegts_descriptor_parse_dvb_service_list(cdesc, &list) == 0 {
+			return nil, false
+		}
+		defer C.g_ptr_array_unref(list)
+
+		var d ServiceListDescriptorValue
+
+		for _, item := range ptrArray[C.GstMpegtsDVBServiceListItem](list) {
+			d.Services = append(d.Services, ServiceListItem{ServiceID: uint16(item.service_id), Type: uint8(item._type)})
+		}
+
+		return d, true
+	case descTagService:
+		typ, name, provider, ok := desc.ParseDvbService()
+
+		return ServiceDescriptorValue{Type: uint8(typ), Provider: provider, Name: name}, ok
+	case descTagShortEvent:
+		language, name, text, ok := desc.ParseDvbShortEvent()
+
+		return ShortEventDescriptorValue{Language: language, Name: name, Text: text}, ok
+	case descTagExtendedEvent:
+		var res *C.GstMpegtsExtendedEventDescriptor
+
+		if C.gst_mpegts_descriptor_parse_dvb_extended_event(cdesc, &res) == 0 {
+			return nil, false
+		}
+		defer C.gst_mpegts_extended_event_descriptor_free(res)
+
+		d := ExtendedEventDescriptorValue{
+			Number:     uint8(res.descriptor_number),
+			LastNumber: uint8(res.last_descriptor_number),
+			Language:   cString(res.language_code),
+			Text:       cString(res.text),
+		}
+
+		for _, item := range ptrArray[C.GstMpegtsExtendedEventItem](res.items) {
+			d.Items = append(d.Items, ExtendedEventEntry{
+				Description: cString(item.item_description),
+				Item:        cString(item.item),
+			})
+		}
+
+		return d, true
+	case descTagComponent:
+		var res *C.GstMpegtsComponentDescriptor
+
+		if C.gst_mpegts_descriptor_parse_dvb_component(cdesc, &res) == 0 {
+			return nil, false
+		}
+		defer C.gst_mpegts_dvb_component_descriptor_free(res)
+
+		return ComponentDescriptorValue{
+			// libgstmpegts only exposes the lower nibble
+			StreamContentExt: p[0] >> 4,
+			StreamContent:    uint8(res.stream_content),
+			ComponentType:    uint8(res.component_type),
+			ComponentTag:     uint8(res.component_tag),
+			Language:         cString(res.language_code),
+			Text:             cString(res.text),
+		}, true
+	case descTagStreamIdentifier:
+		tag, ok := desc.ParseDvbStreamIdentifier()
+
+		return StreamIdentifierDescriptorValue{ComponentTag: tag}, ok
+	case descTagContent:
+		var content *C.GPtrArray
+
+		if C.gst_mpegts_descriptor_parse_dvb_content(cdesc, &content) == 0 {
+			return nil, false
+		}
+		defer C.g_ptr_array_unref(content)
+
+		var d ContentDescriptorValue
+
+		for _, c := range ptrArray[C.GstMpegtsContent](content) {
+			d.Contents = append(d.Contents, ContentNibbles{
+				Level1: uint8(c.content_nibble_1),
+				Level2: uint8(c.content_nibble_2),
+				User:   uint8(c.user_byte),
+			})
+		}
+
+		return d, true
+	case descTagParentalRating:
+		var ratings *C.GPtrArray
+
+		if C.gst_mpegts_descriptor_parse_dvb_parental_rating(cdesc, &ratings) == 0 {
+			return nil, false
+		}
+		defer C.g_ptr_array_unref(ratings)
+
+		var d ParentalRatingDescriptorValue
+
+		for _, r := range ptrArray[C.GstMpegtsDVBParentalRatingItem](ratings) {
+			d.Ratings = append(d.Ratings, ParentalRating{Country: cString(r.country_code), Rating: uint8(r.rating)})
+		}
+
+		return d, true
+	case descTagLocalTimeOffset:
+		// libgstmpegts has no parser for the local time offset descriptor
+		var d LocalTimeOffsetDescriptorValue
+
+		for ; len(p) >= 13; p = p[13:] {
+			offset := bcdHoursMinutes(p[4:])
+			next := bcdHoursMinutes(p[11:])
+
+			if p[3]&0x01 != 0 {
+				offset, next = -offset, -next
+			}
+
+			d.Offsets = append(d.Offsets, LocalTimeOffset{
+				Country:        languageCode(p),
+				RegionID:       p[3] >> 2,
+				Offset:         offset,
+				TimeOfChange:   parseDVBTime(p[6:11]),
+				NextTimeOffset: next,
+			})
+		}
+
+		return d, len(p) == 0
+	case descTagSubtitling:
+		var d SubtitlingDescriptorValue
+
+		for i := range desc.ParseDvbSubtitlingNb() {
+			language, typ, composition, ancillary, ok := desc.ParseDvbSubtitlingIdx(i)
+			if !ok {
+				return nil, false
+			}
+
+			d.Subtitles = append(d.Subtitles, Subtitling{
+				Language:          language,
+				Type:              typ,
+				CompositionPageID: composition,
+				AncillaryPageID:   ancillary,
+			})
+		}
+
+		return d, true
+	}
+
+	return nil, false
+}
+
+// languageCode returns the three letter code at the start of data
+func languageCode(data []byte) string {
+	return strings.TrimRight(string(data[:3]), "\x00 ")
+}
+
+func cloneBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return append([]byte(nil), b...)
+}
+
+// cString copies a string borrowed from a libgstmpegts structure
+func cString(s *C.gchar) string {
+	return C.GoString((*C.char)(unsafe.Pointer(s)))
+}
+
+// cBytes copies data borrowed from a libgstmpegts structure
+func cBytes(data *C.guint8, size C.gsize) []byte {
+	if data == nil {
+		return nil
+	}
+
+	return cloneBytes(unsafe.Slice((*byte)(unsafe.Pointer(data)), int(size)))
+}
+
+// bcd decodes a single binary coded decimal byte
+func bcd(b byte) int {
+	return int(b>>4)*10 + int(b&0x0f)
+}
+
+// bcdHoursMinutes decodes a 16 bit BCD hhmm value
+func bcdHoursMinutes(p []byte) time.Duration {
+	return time.Duration(bcd(p[0]))*time.Hour + time.Duration(bcd(p[1]))*time.Minute
+}
+
+// bcdDuration decodes a 24 bit BCD hhmmss value
+func bcdDuration(p []byte) time.Duration {
+	return time.Duration(bcd(p[0]))*time.Hour + time.Duration(bcd(p[1]))*time.Minute + time.Duration(bcd(p[2]))*time.Second
+}
+
+// parseDVBTime decodes the 40 bit UTC time of DVB SI tables, a 16 bit modified julian date
+// followed by a 24 bit BCD time. Undefined times (all bits set) are returned as zero time.
+func parseDVBTime(p []byte) time.Time {
+	if p[0] == 0xff && p[1] == 0xff && p[2] == 0xff && p[3] == 0xff && p[4] == 0xff {
+		return time.Time{}
+	}
+
+	mjd := int(binary.BigEndian.Uint16(p))
+
+	return time.Date(1858, time.November, 17, 0, 0, 0, 0, time.UTC).AddDate(0, 0, mjd).Add(bcdDuration(p[2:]))
+}
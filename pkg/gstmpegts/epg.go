@@ -0,0 +1,415 @@
+package gstmpegts
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// defaultGPSUTCOffset is the number of leap seconds between GPS and UTC that is used until a
+// system time table is received.
+const defaultGPSUTCOffset = 18
+
+// EPGServiceID identifies a service of the program guide. DVB services are identified by the
+// original network id, the transport stream id and the service id, ATSC virtual channels by their
+// source id in ServiceID.
+type EPGServiceID struct {
+	ATSC              bool
+	OriginalNetworkID uint16
+	TransportStreamID uint16
+	ServiceID         uint16
+}
+
+// EPGEvent is an event of the program guide.
+type EPGEvent struct {
+	EventID uint16
+	// Start is in UTC, it is the zero time if undefined.
+	Start    time.Time
+	Duration time.Duration
+	Title    string
+	// Description is the text of the extended event descriptors or extended text message, it
+	// falls back to the text of the short event descriptor.
+	Description string
+	// Content and ParentalRatings are only signalled by DVB.
+	Content         []ContentNibbles
+	ParentalRatings []ParentalRating
+}
+
+// EPGService is a service of the program guide with its events sorted by start time.
+type EPGService struct {
+	ID     EPGServiceID
+	Events []EPGEvent
+}
+
+// EPGUpdate contains the events of a service that were added or changed by a section.
+type EPGUpdate struct {
+	Service EPGServiceID
+	Events  []EPGEvent
+}
+
+// epgSectionKey identifies a section whose version was already seen
+type epgSectionKey struct {
+	pid               uint16
+	tableID           uint8
+	tableIDExtension  uint16
+	originalNetworkID uint16
+	transportStreamID uint16
+	etmID             uint32
+	sectionNumber     uint8
+}
+
+// EPG builds a program guide from DVB EIT sections and the ATSC EIT and ETT sections announced
+// in the master guide table. Sections are de-duplicated by their version number. It is safe for
+// concurrent use.
+type EPG struct {
+	languages []string
+
+	mu           sync.Mutex
+	versions     map[epgSectionKey]uint8
+	services     map[EPGServiceID]map[uint16]EPGEvent
+	atscPIDs     map[uint16]uint16
+	atscTexts    map[uint32]string
+	gpsUTCOffset uint8
+}
+
+// NewEPG creates an empty program guide. languages are the preferred ISO 639-2 codes of titles
+// and descriptions, the first text of an event is used if none matches.
+func NewEPG(languages ...string) *EPG {
+	return &EPG{
+		languages:    languages,
+		versions:     make(map[epgSectionKey]uint8),
+		services:     make(map[EPGServiceID]map[uint16]EPGEvent),
+		atscPIDs:     make(map[uint16]uint16),
+		atscTexts:    make(map[uint32]string),
+		gpsUTCOffset: defaultGPSUTCOffset,
+	}
+}
+
+// HandleSection adds the events of the section to the guide. It returns the added or changed
+// events, false is returned if the guide did not change, e.g. for sections that were already
+// seen or that do not carry event information.
+func (g *EPG) HandleSection(section DecodedSection) (EPGUpdate, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch s := section.(type) {
+	case *EITTable:
+		key := epgSectionKey{
+			tableID:           s.TableID,
+			tableIDExtension:  s.TableIDExtension,
+			originalNetworkID: s.OriginalNetworkID,
+			transportStreamID: s.TransportStreamID,
+			sectionNumber:     s.SectionNumber,
+		}
+
+		if !g.newVersion(key, s.SectionHeader) {
+			return EPGUpdate{}, false
+		}
+
+		id := EPGServiceID{
+			OriginalNetworkID: s.OriginalNetworkID,
+			TransportStreamID: s.TransportStreamID,
+			ServiceID:         s.ServiceID(),
+		}
+
+		events := make([]EPGEvent, 0, len(s.Events))
+		for _, e := range s.Events {
+			events = append(events, g.dvbEvent(e))
+		}
+
+		return g.update(id, events)
+	case *MGTTable:
+		if !g.newVersion(epgSectionKey{pid: s.PID, tableID: s.TableID}, s.SectionHeader) {
+			return EPGUpdate{}, false
+		}
+
+		clear(g.atscPIDs)
+
+		for _, t := range s.Tables {
+			if isATSCEITType(t.Type) || isATSCETTType(t.Type) {
+				g.atscPIDs[t.PID] = t.Type
+			}
+		}
+	case *STTTable:
+		g.gpsUTCOffset = s.GPSUTCOffset
+	case *ATSCEITTable:
+		if t, ok := g.atscPIDs[s.PID]; !ok || !isATSCEITType(t) {
+			return EPGUpdate{}, false
+		}
+
+		key := epgSectionKey{pid: s.PID, tableID: s.TableID, tableIDExtension: s.TableIDExtension, sectionNumber: s.SectionNumber}
+
+		if !g.newVersion(key, s.SectionHeader) {
+			return EPGUpdate{}, false
+		}
+
+		events := make([]EPGEvent, 0, len(s.Events))
+		for _, e := range s.Events {
+			events = append(events, g.atscEvent(s.SourceID(), e))
+		}
+
+		return g.update(EPGServiceID{ATSC: true, ServiceID: s.SourceID()}, events)
+	case *ETTTable:
+		if t, ok := g.atscPIDs[s.PID]; !ok || !isATSCETTType(t) {
+			return EPGUpdate{}, false
+		}
+
+		if !g.newVersion(epgSectionKey{pid: s.PID, tableID: s.TableID, etmID: s.ETMID}, s.SectionHeader) {
+			return EPGUpdate{}, false
+		}
+
+		eventID, ok := s.EventID()
+		if !ok {
+			return EPGUpdate{}, false
+		}
+
+		text := g.text(s.Text)
+		g.atscTexts[s.ETMID] = text
+
+		id := EPGServiceID{ATSC: true, ServiceID: s.SourceID()}
+
+		e, ok := g.services[id][eventID]
+		if !ok {
+			// the text is added when the event is received
+			return EPGUpdate{}, false
+		}
+
+		e.Description = text
+
+		return g.update(id, []EPGEvent{e})
+	}
+
+	return EPGUpdate{}, false
+}
+
+// newVersion records the version of the section and returns false if it was already seen
+func (g *EPG) newVersion(key epgSectionKey, h SectionHeader) bool {
+	if !h.CurrentNext {
+		return false
+	}
+
+	if v, ok := g.versions[key]; ok && v == h.Version {
+		return false
+	}
+
+	g.versions[key] = h.Version
+
+	return true
+}
+
+// update stores the events and returns the ones that changed
+func (g *EPG) update(id EPGServiceID, events []EPGEvent) (EPGUpdate, bool) {
+	service, ok := g.services[id]
+	if !ok {
+		service = make(map[uint16]EPGEvent)
+		g.services[id] = service
+	}
+
+	u := EPGUpdate{Service: id}
+
+	for _, e := range events {
+		if old, ok := service[e.EventID]; ok && reflect.DeepEqual(old, e) {
+			continue
+		}
+
+		service[e.EventID] = e
+		u.Events = append(u.Events, e)
+	}
+
+	return u, len(u.Events) > 0
+}
+
+func (g *EPG) dvbEvent(e EITTableEvent) EPGEvent {
+	event := EPGEvent{
+		EventID:  e.EventID,
+		Start:    e.StartTime,
+		Duration: e.Duration,
+	}
+
+	var short []ShortEventDescriptorValue
+	var extended []ExtendedEventDescriptorValue
+
+	for _, d := range e.Descriptors {
+		switch d := d.(type) {
+		case ShortEventDescriptorValue:
+			short = append(short, d)
+		case ExtendedEventDescriptorValue:
+			extended = append(extended, d)
+		case ContentDescriptorValue:
+			event.Content = append(event.Content, d.Contents...)
+		case ParentalRatingDescriptorValue:
+			event.ParentalRatings = append(event.ParentalRatings, d.Ratings...)
+		}
+	}
+
+	language := ""
+
+	if i := g.pickLanguage(len(short), func(i int) string { return short[i].Language }); i >= 0 {
+		language = short[i].Language
+		event.Title = short[i].Name
+		event.Description = short[i].Text
+	} else if i := g.pickLanguage(len(extended), func(i int) string { return extended[i].Language }); i >= 0 {
+		language = extended[i].Language
+	}
+
+	// long texts are split over multiple descriptors of the same language
+	extended = slices.DeleteFunc(extended, func(d ExtendedEventDescriptorValue) bool { return d.Language != language })
+	slices.SortStableFunc(extended, func(a, b ExtendedEventDescriptorValue) int { return cmp.Compare(a.Number, b.Number) })
+
+	var sb strings.Builder
+
+	for _, d := range extended {
+		for _, item := range d.Items {
+			sb.WriteString(item.Description + ": " + item.Item + "\n")
+		}
+
+		sb.WriteString(d.Text)
+	}
+
+	if sb.Len() > 0 {
+		event.Description = sb.String()
+	}
+
+	return event
+}
+
+func (g *EPG) atscEvent(sourceID uint16, e ATSCEITTableEvent) EPGEvent {
+	event := EPGEvent{
+		EventID:  e.EventID,
+		Start:    GPSTime(e.StartGPS, g.gpsUTCOffset),
+		Duration: e.Duration,
+		Title:    g.text(e.Title),
+	}
+
+	if e.ETMLocation != 0 {
+		event.Description = g.atscTexts[uint32(sourceID)<<16|uint32(e.EventID)<<2|0x02]
+	}
+
+	return event
+}
+
+// text returns the ATSC text in the preferred language
+func (g *EPG) text(texts []ATSCText) string {
+	if i := g.pickLanguage(len(texts), func(i int) string { return texts[i].Language }); i >= 0 {
+		return texts[i].Text
+	}
+
+	return ""
+}
+
+// pickLanguage returns the index of the first entry in the preferred language, the first entry if
+// none matches or -1 if there are no entries
+func (g *EPG) pickLanguage(n int, language func(int) string) int {
+	for _, preferred := range g.languages {
+		for i := range n {
+			if strings.EqualFold(language(i), preferred) {
+				return i
+			}
+		}
+	}
+
+	if n > 0 {
+		return 0
+	}
+
+	return -1
+}
+
+// Service returns the service with its events sorted by start time.
+func (g *EPG) Service(id EPGServiceID) (EPGService, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	events, ok := g.services[id]
+	if !ok {
+		return EPGService{}, false
+	}
+
+	return g.service(id, events), true
+}
+
+// Services returns all services of the guide.
+func (g *EPG) Services() []EPGService {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	services := make([]EPGService, 0, len(g.services))
+	for id, events := range g.services {
+		services = append(services, g.service(id, events))
+	}
+
+	slices.SortFunc(services, func(a, b EPGService) int {
+		return cmp.Or(
+			cmp.Compare(a.ID.OriginalNetworkID, b.ID.OriginalNetworkID),
+			cmp.Compare(a.ID.TransportStreamID, b.ID.TransportStreamID),
+			cmp.Compare(a.ID.ServiceID, b.ID.ServiceID),
+		)
+	})
+
+	return services
+}
+
+func (g *EPG) service(id EPGServiceID, events map[uint16]EPGEvent) EPGService {
+	s := EPGService{ID: id, Events: make([]EPGEvent, 0, len(events))}
+
+	for _, e := range events {
+		s.Events = append(s.Events, e)
+	}
+
+	slices.SortFunc(s.Events, func(a, b EPGEvent) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.EventID, b.EventID))
+	})
+
+	return s
+}
+
+// Updates adds the sections of the section messages in messages to the guide and yields the
+// resulting updates until an EOS message. Sections that cannot be decoded and error messages are
+// yielded with an error, see [Sections].
+//
+//	for update, err := range epg.Updates(messages) {
+//		...
+//	}
+func (g *EPG) Updates(messages iter.Seq[*gst.Message]) iter.Seq2[EPGUpdate, error] {
+	return func(yield func(EPGUpdate, error) bool) {
+		for section, err := range Sections(messages) {
+			if err != nil {
+				if !yield(EPGUpdate{}, err) {
+					return
+				}
+
+				continue
+			}
+
+			update, ok := g.HandleSection(section)
+			if !ok {
+				continue
+			}
+
+			if !yield(update, nil) {
+				return
+			}
+		}
+	}
+}
+
+// BusUpdates attaches the guide to the bus and yields the updates of the sections posted by
+// tsdemux until ctx is done, the bus posts EOS or an error, which is yielded as well. The other
+// messages of the bus reach the application as usual, see [BusSections].
+//
+//	for update, err := range epg.BusUpdates(ctx, pipeline.GetBus()) {
+//		...
+//	}
+func (g *EPG) BusUpdates(ctx context.Context, bus gst.Bus) iter.Seq2[EPGUpdate, error] {
+	return g.Updates(busSectionMessages(ctx, bus))
+}
+
+func isATSCEITType(t uint16) bool { return t >= 0x0100 && t <= 0x017f }
+func isATSCETTType(t uint16) bool { return t >= 0x0200 && t <= 0x027f }
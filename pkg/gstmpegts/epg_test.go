@@ -0,0 +1,134 @@
+package gstmpegts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gstmpegts"
+)
+
+// withVersion changes the version of a long section built by buildSection
+func withVersion(data []byte, version byte) []byte {
+	data = append([]byte(nil), data[:len(data)-4]...)
+	data[5] = 0xc1 | version<<1
+
+	return appendCRC(data)
+}
+
+// atscText creates a multiple string structure with a single uncompressed string
+func atscText(text string) []byte {
+	return append([]byte{0x01, 'e', 'n', 'g', 0x01, 0x00, 0x00, byte(len(text))}, text...)
+}
+
+func handle(t *testing.T, epg *gstmpegts.EPG, pid uint16, data []byte) (gstmpegts.EPGUpdate, bool) {
+	t.Helper()
+
+	section, err := gstmpegts.ParseSectionData(pid, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return epg.HandleSection(section)
+}
+
+func TestEPGDVB(t *testing.T) {
+	descriptors := []byte{
+		0x4d, 0x0c, 'd', 'e', 'u', 0x04, 'T', 'e', 's', 't', 0x03, 'a', 'b', 'c', // short event
+		0x4d, 0x0c, 'e', 'n', 'g', 0x04, 'N', 'e', 'w', 's', 0x03, 'a', 'b', 'c', // short event
+		0x4e, 0x0a, 0x11, 'e', 'n', 'g', 0x00, 0x04, 'p', 'a', 'r', 't', // extended event, second part
+		0x4e, 0x0a, 0x01, 'e', 'n', 'g', 0x00, 0x04, 'f', 'i', 'r', 's', // extended event, first part
+		0x54, 0x02, 0x12, 0x00, // content
+	}
+
+	body := []byte{0x00, 0x01, 0x00, 0x02, 0x00, 0x4e}
+	body = append(body, 0x00, 0x07, 0xc0, 0x79, 0x12, 0x45, 0x00, 0x01, 0x30, 0x00)
+	body = append(body, 0x80|byte(len(descriptors)>>8), byte(len(descriptors)))
+	body = append(body, descriptors...)
+
+	data := buildSection(0x4e, 10, body)
+
+	epg := gstmpegts.NewEPG("eng")
+
+	update, ok := handle(t, epg, 0x12, data)
+	if !ok || len(update.Events) != 1 {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	id := gstmpegts.EPGServiceID{OriginalNetworkID: 2, TransportStreamID: 1, ServiceID: 10}
+	if update.Service != id {
+		t.Errorf("unexpected service %+v", update.Service)
+	}
+
+	e := update.Events[0]
+	if e.EventID != 7 || e.Title != "News" || e.Description != "firspart" || e.Duration != 90*time.Minute {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if len(e.Content) != 1 || e.Content[0].Level1 != 1 {
+		t.Errorf("unexpected content %+v", e.Content)
+	}
+
+	if _, ok := handle(t, epg, 0x12, data); ok {
+		t.Error("update for a section that was already seen")
+	}
+
+	// a new version without changes does not produce an update
+	if _, ok := handle(t, epg, 0x12, withVersion(data, 4)); ok {
+		t.Error("update for an unchanged event")
+	}
+
+	services := epg.Services()
+	if len(services) != 1 || services[0].ID != id || len(services[0].Events) != 1 {
+		t.Errorf("unexpected services %+v", services)
+	}
+}
+
+func TestEPGATSC(t *testing.T) {
+	epg := gstmpegts.NewEPG()
+
+	mgt := []byte{
+		0x00, 0x00, 0x02, // protocol version, tables defined
+		0x01, 0x00, 0xfd, 0x00, 0xe1, 0x00, 0x00, 0x00, 0x10, 0xf0, 0x00, // EIT-0 on 0x1d00
+		0x02, 0x00, 0xfd, 0x01, 0xe1, 0x00, 0x00, 0x00, 0x10, 0xf0, 0x00, // ETT-0 on 0x1d01
+		0xf0, 0x00,
+	}
+
+	if _, ok := handle(t, epg, 0x1ffb, buildSection(0xc7, 0, mgt)); ok {
+		t.Error("update for the MGT")
+	}
+
+	start := time.Date(2020, time.January, 1, 20, 0, 0, 0, time.UTC)
+	gps := uint32(start.Sub(time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC))/time.Second) + 18
+
+	title := atscText("News")
+
+	eit := []byte{0x00, 0x01, 0xc0, 0x01, byte(gps >> 24), byte(gps >> 16), byte(gps >> 8), byte(gps), 0xd0, 0x07, 0x08, byte(len(title))}
+	eit = append(eit, title...)
+	eit = append(eit, 0xf0, 0x00)
+
+	// EIT sections on PIDs that are not announced in the MGT are ignored
+	if _, ok := handle(t, epg, 0x1d02, buildSection(0xcb, 5, eit)); ok {
+		t.Error("update for an unknown PID")
+	}
+
+	update, ok := handle(t, epg, 0x1d00, buildSection(0xcb, 5, eit))
+	if !ok || update.Service != (gstmpegts.EPGServiceID{ATSC: true, ServiceID: 5}) || len(update.Events) != 1 {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	e := update.Events[0]
+	if e.EventID != 1 || !e.Start.Equal(start) || e.Duration != 30*time.Minute || e.Title != "News" || e.Description != "" {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	ett := append([]byte{0x00, 0x00, 0x05, 0x00, 0x06}, atscText("Evening news")...)
+
+	update, ok = handle(t, epg, 0x1d01, buildSection(0xcc, 0, ett))
+	if !ok || len(update.Events) != 1 || update.Events[0].Description != "Evening news" {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	if _, ok := handle(t, epg, 0x1d01, buildSection(0xcc, 0, ett)); ok {
+		t.Error("update for an ETT that was already seen")
+	}
+}
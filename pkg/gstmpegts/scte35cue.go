@@ -0,0 +1,140 @@
+package gstmpegts
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-mpegts-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/mpegts/mpegts.h>
+import "C"
+
+// ErrSCTE35CueNotHandled is returned if the muxer did not accept a cue event.
+var ErrSCTE35CueNotHandled = errors.New("SCTE 35 cue not handled")
+
+// ErrSCTE35NoRunningTime is returned if a position cannot be converted to a running time.
+var ErrSCTE35NoRunningTime = errors.New("no running time for SCTE 35 cue")
+
+// SCTE35Cue is a splice_insert ad marker that is inserted into the output of mpegtsmux with
+// [InsertSCTE35Cue].
+type SCTE35Cue struct {
+	EventID uint32
+	// Out is true for the start of a break (out of network) and false for its end.
+	Out bool
+	// RunningTime of the splice point, mpegtsmux converts it to the PTS of the output. It is
+	// ignored for immediate cues, see [SCTE35RunningTimeAt] for pipeline positions.
+	RunningTime gst.ClockTime
+	// Immediate requests the splice at the next possible point instead of at RunningTime.
+	Immediate bool
+	// Duration of the break for cues with Out set, the break returns automatically after it.
+	// Zero means no break duration is signalled.
+	Duration gst.ClockTime
+	// PTSAdjustment in 90 kHz ticks is signalled to the receiver, which adds it to all PTS
+	// values of the section.
+	PTSAdjustment uint64
+}
+
+// EnableSCTE35 configures mpegtsmux to write SCTE 35 sections on pid. It has to be called
+// before the muxer starts.
+func EnableSCTE35(mux gst.Element, pid uint16) {
+	mux.SetObjectProperty("scte-35-pid", uint(pid))
+}
+
+// NewSCTE35CueSection creates the splice_insert section of the cue. The splice times are running
+// times, the section is meant to be sent to mpegtsmux and not to be packetized directly.
+func NewSCTE35CueSection(cue SCTE35Cue) *Section {
+	sit := C.gst_mpegts_scte_sit_new()
+	event := C.gst_mpegts_scte_splice_event_new()
+
+	sit.splice_command_type = C.GstMpegtsSCTESpliceCommandType(C.GST_MTS_SCTE_SPLICE_COMMAND_INSERT)
+	sit.pts_adjustment = C.guint64(cue.PTSAdjustment & 0x1ffffffff)
+	sit.is_running_time = C.TRUE
+
+	event.insert_event = C.TRUE
+	event.splice_event_id = C.guint32(cue.EventID)
+	event.out_of_network_indicator = cbool(cue.Out)
+	event.program_splice_flag = C.TRUE
+	event.splice_immediate_flag = cbool(cue.Immediate)
+
+	if !cue.Immediate {
+		event.program_splice_time_specified = C.TRUE
+		event.program_splice_time = C.guint64(cue.RunningTime)
+	}
+
+	if cue.Out && cue.Duration != 0 && cue.Duration != gst.ClockTimeNone {
+		event.duration_flag = C.TRUE
+		event.break_duration_auto_return = C.TRUE
+		event.break_duration = C.guint64(cue.Duration)
+	}
+
+	C.g_ptr_array_add(sit.splices, C.gpointer(unsafe.Pointer(event)))
+
+	return sectionFromSCTESIT(sit)
+}
+
+// NewSCTE35CancelSection creates a splice_insert section that cancels the cue with eventID.
+func NewSCTE35CancelSection(eventID uint32) *Section {
+	return SectionFromScteSit(NewScteCancel(eventID), 0)
+}
+
+// sectionFromSCTESIT takes ownership of sit and wraps it in a section. The pid is set by the muxer.
+func sectionFromSCTESIT(sit *C.GstMpegtsSCTESIT) *Section {
+	cret := C.gst_mpegts_section_from_scte_sit(sit, 0)
+
+	return UnsafeSectionFromGlibFull(unsafe.Pointer(cret))
+}
+
+// InsertSCTE35Cue sends the cue to mpegtsmux, which has to be configured with [EnableSCTE35].
+// The muxer converts the running times to the PTS of its output.
+func InsertSCTE35Cue(mux gst.Element, cue SCTE35Cue) error {
+	return sendSCTE35Section(mux, NewSCTE35CueSection(cue))
+}
+
+// CancelSCTE35Cue sends a cancellation of the cue with eventID to mpegtsmux.
+func CancelSCTE35Cue(mux gst.Element, eventID uint32) error {
+	return sendSCTE35Section(mux, NewSCTE35CancelSection(eventID))
+}
+
+func sendSCTE35Section(mux gst.Element, section *Section) error {
+	if section == nil {
+		return fmt.Errorf("%w: could not create section", ErrSCTE35CueNotHandled)
+	}
+
+	if !mux.SendEvent(EventNewMpegtsSection(section)) {
+		return fmt.Errorf("%w: rejected by %s", ErrSCTE35CueNotHandled, mux.GetName())
+	}
+
+	return nil
+}
+
+// SCTE35RunningTimeAt converts the stream position of the pipeline to the running time that is
+// used in [SCTE35Cue], assuming a playback rate of 1. position must not be in the past.
+func SCTE35RunningTimeAt(pipeline gst.Element, position gst.ClockTime) (gst.ClockTime, error) {
+	now := pipeline.GetCurrentRunningTime()
+	if now == gst.ClockTimeNone {
+		return 0, fmt.Errorf("%w: %s has no clock", ErrSCTE35NoRunningTime, pipeline.GetName())
+	}
+
+	current, ok := pipeline.QueryPosition(gst.FormatTime)
+	if !ok || current < 0 {
+		return 0, fmt.Errorf("%w: position query failed", ErrSCTE35NoRunningTime)
+	}
+
+	if position < gst.ClockTime(current) {
+		return 0, fmt.Errorf("%w: position %v is before the current position %v", ErrSCTE35NoRunningTime, position, gst.ClockTime(current))
+	}
+
+	return now + position - gst.ClockTime(current), nil
+}
+
+func cbool(b bool) C.gboolean {
+	if b {
+		return C.TRUE
+	}
+
+	return C.FALSE
+}
@@ -0,0 +1,181 @@
+package gstmpegts
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// SpliceEventType is the kind of a received [SpliceEvent].
+type SpliceEventType int
+
+const (
+	// SpliceEventOut is the start of a break.
+	SpliceEventOut SpliceEventType = iota
+	// SpliceEventIn is the end of a break.
+	SpliceEventIn
+	// SpliceEventCancel cancels a previously signalled event.
+	SpliceEventCancel
+	// SpliceEventTimeSignal is a time_signal, the meaning is given by the segmentation descriptors.
+	SpliceEventTimeSignal
+	// SpliceEventHeartbeat is a splice_null command.
+	SpliceEventHeartbeat
+)
+
+func (t SpliceEventType) String() string {
+	switch t {
+	case SpliceEventOut:
+		return "out"
+	case SpliceEventIn:
+		return "in"
+	case SpliceEventCancel:
+		return "cancel"
+	case SpliceEventTimeSignal:
+		return "time-signal"
+	case SpliceEventHeartbeat:
+		return "heartbeat"
+	default:
+		return "unknown"
+	}
+}
+
+// SpliceEvent is the simplified view of a SCTE 35 splice info section.
+type SpliceEvent struct {
+	Type SpliceEventType
+	// PID the section was received on.
+	PID     uint16
+	EventID uint32
+	// Immediate is true if the splice happens at the next possible point, PTS is unset then.
+	Immediate bool
+	// HasPTS is true if PTS is set. It is unset for immediate and component splices.
+	HasPTS bool
+	// PTS of the splice point in 90 kHz ticks with the PTS adjustment of the section applied.
+	PTS uint64
+	// Duration of the break, zero if not signalled.
+	Duration time.Duration
+	// AutoReturn is true if the break ends automatically after Duration.
+	AutoReturn bool
+	// Segmentation contains the segmentation descriptors of the section.
+	Segmentation []SegmentationDescriptorValue
+	// Section is the decoded section.
+	Section *SpliceInfoSection
+}
+
+// SpliceEvent converts the section into a [SpliceEvent]. It returns false for encrypted sections
+// and for commands that do not describe a splice.
+func (s *SpliceInfoSection) SpliceEvent() (SpliceEvent, bool) {
+	if s.Encrypted {
+		return SpliceEvent{}, false
+	}
+
+	event := SpliceEvent{PID: s.PID, Section: s}
+
+	for _, d := range s.Descriptors {
+		if seg, ok := d.(SegmentationDescriptorValue); ok {
+			event.Segmentation = append(event.Segmentation, seg)
+		}
+	}
+
+	switch c := s.Command.(type) {
+	case SpliceNull:
+		event.Type = SpliceEventHeartbeat
+	case TimeSignal:
+		event.Type = SpliceEventTimeSignal
+		event.setTime(s, c.Time)
+
+		// the duration of a time signal is given by its segmentation descriptor
+		for _, seg := range event.Segmentation {
+			if seg.Duration != nil {
+				event.EventID = seg.EventID
+				event.Duration = PTSToDuration(*seg.Duration)
+
+				break
+			}
+		}
+	case SpliceInsert:
+		event.EventID = c.EventID
+
+		switch {
+		case c.Cancel:
+			event.Type = SpliceEventCancel
+
+			return event, true
+		case c.OutOfNetwork:
+			event.Type = SpliceEventOut
+		default:
+			event.Type = SpliceEventIn
+		}
+
+		event.Immediate = c.Immediate
+
+		if c.ProgramSplice && !c.Immediate {
+			event.setTime(s, c.Time)
+		}
+
+		if c.BreakDuration != nil {
+			event.Duration = PTSToDuration(c.BreakDuration.Duration)
+			event.AutoReturn = c.BreakDuration.AutoReturn
+		}
+	default:
+		return SpliceEvent{}, false
+	}
+
+	return event, true
+}
+
+func (e *SpliceEvent) setTime(s *SpliceInfoSection, t SpliceTime) {
+	if !t.Specified {
+		return
+	}
+
+	e.HasPTS = true
+	e.PTS = (t.PTS + s.PTSAdjustment) & 0x1ffffffff
+}
+
+// SpliceEvents yields the splice events of the SCTE 35 sections posted by tsdemux in messages
+// until an EOS message, all other messages and sections are skipped. Sections that cannot be
+// decoded and error messages are yielded with an error, see [Sections].
+//
+//	for event, err := range gstmpegts.SpliceEvents(messages) {
+//		...
+//	}
+func SpliceEvents(messages iter.Seq[*gst.Message]) iter.Seq2[SpliceEvent, error] {
+	return func(yield func(SpliceEvent, error) bool) {
+		for section, err := range Sections(messages) {
+			if err != nil {
+				if !yield(SpliceEvent{}, err) {
+					return
+				}
+
+				continue
+			}
+
+			s, ok := section.(*SpliceInfoSection)
+			if !ok {
+				continue
+			}
+
+			event, ok := s.SpliceEvent()
+			if !ok {
+				continue
+			}
+
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// BusSpliceEvents yields the splice events of the SCTE 35 sections posted by tsdemux on the bus
+// until ctx is done, the bus posts EOS or an error, which is yielded as well. The other messages
+// of the bus reach the application as usual, see [BusSections].
+//
+//	for event, err := range gstmpegts.BusSpliceEvents(ctx, pipeline.GetBus()) {
+//		...
+//	}
+func BusSpliceEvents(ctx context.Context, bus gst.Bus) iter.Seq2[SpliceEvent, error] {
+	return SpliceEvents(busSectionMessages(ctx, bus))
+}
@@ -0,0 +1,46 @@
+package gstmpegts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gstmpegts"
+)
+
+func TestSpliceEvent(t *testing.T) {
+	command := []byte{
+		0x00, 0x00, 0x00, 0x07, // event id
+		0x7f,                         // not cancelled
+		0xef,                         // out of network, program splice, duration
+		0xff, 0xff, 0xff, 0xff, 0xf0, // splice time 0x1fffffff0
+		0x7e, 0x00, 0x0d, 0xbb, 0xa0, // no auto return, 10s break
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	// a PTS adjustment of 0x20 wraps the splice time around
+	body := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0xf0 | byte(len(command)>>8), byte(len(command)), 0x05}
+	body = append(body, command...)
+	body = append(body, 0x00, 0x00)
+
+	section, err := gstmpegts.ParseSectionData(0x1f0, buildShortSection(0xfc, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, ok := section.(*gstmpegts.SpliceInfoSection).SpliceEvent()
+	if !ok {
+		t.Fatal("no splice event")
+	}
+
+	if event.Type != gstmpegts.SpliceEventOut || event.EventID != 7 || event.PID != 0x1f0 {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	if !event.HasPTS || event.PTS != 0x10 {
+		t.Errorf("unexpected PTS %#x", event.PTS)
+	}
+
+	if event.Duration != 10*time.Second || event.AutoReturn {
+		t.Errorf("unexpected duration %v", event.Duration)
+	}
+}
@@ -0,0 +1,404 @@
+package gstmpegts
+
+import (
+	"encoding/binary"
+	"time"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-mpegts-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/mpegts/mpegts.h>
+import "C"
+
+// splice command types of SCTE 35
+const (
+	spliceCommandNull                 = 0x00
+	spliceCommandInsert               = 0x05
+	spliceCommandTimeSignal           = 0x06
+	spliceCommandBandwidthReservation = 0x07
+)
+
+// splice descriptor tags of SCTE 35
+const (
+	spliceDescriptorAvail        = 0x00
+	spliceDescriptorSegmentation = 0x02
+	spliceDescriptorTime         = 0x03
+)
+
+// PTSToDuration converts 90 kHz ticks of SCTE 35 times and durations to a duration.
+func PTSToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / 90000
+}
+
+// SpliceInfoSection is a SCTE 35 splice info section.
+type SpliceInfoSection struct {
+	SectionHeader
+	ProtocolVersion uint8
+	// Encrypted is true if the command and the descriptors are encrypted, they are not decoded then.
+	Encrypted bool
+	// PTSAdjustment in 90 kHz ticks is added to all PTS values of the section.
+	PTSAdjustment uint64
+	Tier          uint16
+	Command       SpliceCommand
+	Descriptors   []SpliceDescriptor
+}
+
+// SpliceCommand is the command of a [SpliceInfoSection]. The concrete types are [SpliceNull],
+// [SpliceInsert], [TimeSignal], [BandwidthReservation] and [SpliceUnknownCommand].
+type SpliceCommand interface {
+	// SpliceCommandType returns the splice_command_type of the command.
+	SpliceCommandType() uint8
+}
+
+// SpliceTime is an optional PTS in 90 kHz ticks.
+type SpliceTime struct {
+	Specified bool
+	PTS       uint64
+}
+
+// SpliceBreakDuration is the duration of a break in 90 kHz ticks.
+type SpliceBreakDuration struct {
+	AutoReturn bool
+	Duration   uint64
+}
+
+// SpliceInsertComponent is the splice time of a single component of a component splice.
+type SpliceInsertComponent struct {
+	Tag  uint8
+	Time SpliceTime
+}
+
+// SpliceNull is the splice_null command, it is used as heartbeat.
+type SpliceNull struct{}
+
+// SpliceInsert is the splice_insert command that signals the start or the end of a break.
+type SpliceInsert struct {
+	EventID uint32
+	// Cancel is true if a previously sent event with the same id is cancelled, all other fields
+	// are unset then.
+	Cancel bool
+	// OutOfNetwork is true at the start of a break and false at its end.
+	OutOfNetwork bool
+	// ProgramSplice is true if all components are spliced at Time, otherwise Components is set.
+	ProgramSplice bool
+	// Immediate is true if the splice happens at the next possible point.
+	Immediate       bool
+	Time            SpliceTime
+	Components      []SpliceInsertComponent
+	BreakDuration   *SpliceBreakDuration
+	UniqueProgramID uint16
+	AvailNum        uint8
+	AvailsExpected  uint8
+}
+
+// TimeSignal is the time_signal command, the meaning is given by the segmentation descriptors.
+type TimeSignal struct {
+	Time SpliceTime
+}
+
+// BandwidthReservation is the bandwidth_reservation command.
+type BandwidthReservation struct{}
+
+// SpliceUnknownCommand is a command that is not decoded by this package, e.g. splice_schedule
+// or a private command.
+type SpliceUnknownCommand struct {
+	Type uint8
+	Data []byte
+}
+
+func (SpliceNull) SpliceCommandType() uint8             { return spliceCommandNull }
+func (SpliceInsert) SpliceCommandType() uint8           { return spliceCommandInsert }
+func (TimeSignal) SpliceCommandType() uint8             { return spliceCommandTimeSignal }
+func (BandwidthReservation) SpliceCommandType() uint8   { return spliceCommandBandwidthReservation }
+func (c SpliceUnknownCommand) SpliceCommandType() uint8 { return c.Type }
+
+// SpliceDescriptor is a descriptor of a [SpliceInfoSection]. The concrete types are
+// [AvailDescriptorValue], [SegmentationDescriptorValue], [TimeDescriptorValue] and
+// [SpliceUnknownDescriptor].
+type SpliceDescriptor interface {
+	// SpliceDescriptorTag returns the splice_descriptor_tag of the descriptor.
+	SpliceDescriptorTag() uint8
+}
+
+// AvailDescriptorValue is the SCTE 35 avail descriptor.
+type AvailDescriptorValue struct {
+	ProviderAvailID uint32
+}
+
+// SegmentationComponent is the PTS offset of a single component of a segmentation descriptor.
+type SegmentationComponent struct {
+	Tag       uint8
+	PTSOffset uint64
+}
+
+// SegmentationDescriptorValue is the SCTE 35 segmentation descriptor.
+type SegmentationDescriptorValue struct {
+	EventID uint32
+	// Cancel is true if a previously sent event with the same id is cancelled, all other fields
+	// are unset then.
+	Cancel                bool
+	ProgramSegmentation   bool
+	DeliveryNotRestricted bool
+	WebDeliveryAllowed    bool
+	NoRegionalBlackout    bool
+	ArchiveAllowed        bool
+	DeviceRestrictions    uint8
+	Components            []SegmentationComponent
+	// Duration in 90 kHz ticks, nil if not signalled.
+	Duration            *uint64
+	UPIDType            uint8
+	UPID                []byte
+	TypeID              uint8
+	SegmentNum          uint8
+	SegmentsExpected    uint8
+	SubSegmentNum       uint8
+	SubSegmentsExpected uint8
+}
+
+// TimeDescriptorValue is the SCTE 35 time descriptor.
+type TimeDescriptorValue struct {
+	TAISeconds uint64
+	TAINanos   uint32
+	UTCOffset  uint16
+}
+
+// SpliceUnknownDescriptor is a splice descriptor that is not decoded by this package.
+type SpliceUnknownDescriptor struct {
+	Tag        uint8
+	Identifier string
+	Data       []byte
+}
+
+func (AvailDescriptorValue) SpliceDescriptorTag() uint8        { return spliceDescriptorAvail }
+func (SegmentationDescriptorValue) SpliceDescriptorTag() uint8 { return spliceDescriptorSegmentation }
+func (TimeDescriptorValue) SpliceDescriptorTag() uint8         { return spliceDescriptorTime }
+func (d SpliceUnknownDescriptor) SpliceDescriptorTag() uint8   { return d.Tag }
+
+// decodeSpliceInfoSection decodes the splice info section parsed by libgstmpegts. The splice
+// descriptors are decoded by this package, libgstmpegts only splits the descriptor loop.
+func decodeSpliceInfoSection(h SectionHeader, section *C.GstMpegtsSection) DecodedSection {
+	sit := C.gst_mpegts_section_get_scte_sit(section)
+	if sit == nil {
+		return nil
+	}
+
+	data := unsafe.Slice((*byte)(unsafe.Pointer(section.data)), int(section.section_length))
+
+	s := &SpliceInfoSection{
+		SectionHeader:   h,
+		ProtocolVersion: data[3],
+		Encrypted:       sit.encrypted_packet != 0,
+		PTSAdjustment:   uint64(sit.pts_adjustment),
+		Tier:            uint16(sit.tier),
+	}
+
+	// the command of encrypted sections is not parsed by libgstmpegts
+	if s.Encrypted {
+		s.Command = SpliceUnknownCommand{Type: data[13], Data: cloneBytes(data[14 : len(data)-4])}
+
+		return s
+	}
+
+	switch sit.splice_command_type {
+	case C.GST_MTS_SCTE_SPLICE_COMMAND_NULL:
+		s.Command = SpliceNull{}
+	case C.GST_MTS_SCTE_SPLICE_COMMAND_BANDWIDTH:
+		s.Command = BandwidthReservation{}
+	case C.GST_MTS_SCTE_SPLICE_COMMAND_TIME:
+		s.Command = TimeSignal{Time: SpliceTime{Specified: sit.splice_time_specified != 0, PTS: uint64(sit.splice_time)}}
+	case C.GST_MTS_SCTE_SPLICE_COMMAND_INSERT:
+		splices := ptrArray[C.GstMpegtsSCTESpliceEvent](sit.splices)
+		if len(splices) != 1 {
+			return nil
+		}
+
+		s.Command = spliceInsert(splices[0])
+	default:
+		command := SpliceUnknownCommand{Type: uint8(sit.splice_command_type)}
+
+		// 0 is used by legacy encoders that do not know the command length
+		if n := int(sit.splice_command_length); n > 0 {
+			command.Data = cloneBytes(data[14 : 14+n])
+		}
+
+		s.Command = command
+	}
+
+	for _, d := range ptrArray[C.GstMpegtsDescriptor](sit.descriptors) {
+		payload := unsafe.Slice((*byte)(unsafe.Pointer(d.data)), 2+int(d.length))[2:]
+
+		s.Descriptors = append(s.Descriptors, parseSpliceDescriptor(uint8(d.tag), payload))
+	}
+
+	return s
+}
+
+func spliceInsert(e *C.GstMpegtsSCTESpliceEvent) SpliceInsert {
+	s := SpliceInsert{
+		EventID: uint32(e.splice_event_id),
+		Cancel:  e.splice_event_cancel_indicator != 0,
+	}
+
+	if s.Cancel {
+		return s
+	}
+
+	s.OutOfNetwork = e.out_of_network_indicator != 0
+	s.ProgramSplice = e.program_splice_flag != 0
+	s.Immediate = e.splice_immediate_flag != 0
+	s.Time = SpliceTime{Specified: e.program_splice_time_specified != 0, PTS: uint64(e.program_splice_time)}
+	s.UniqueProgramID = uint16(e.unique_program_id)
+	s.AvailNum = uint8(e.avail_num)
+	s.AvailsExpected = uint8(e.avails_expected)
+
+	for _, c := range ptrArray[C.GstMpegtsSCTESpliceComponent](e.components) {
+		s.Components = append(s.Components, SpliceInsertComponent{
+			Tag:  uint8(c.tag),
+			Time: SpliceTime{Specified: c.splice_time_specified != 0, PTS: uint64(c.splice_time)},
+		})
+	}
+
+	if e.duration_flag != 0 {
+		s.BreakDuration = &SpliceBreakDuration{
+			AutoReturn: e.break_duration_auto_return != 0,
+			Duration:   uint64(e.break_duration),
+		}
+	}
+
+	return s
+}
+
+// parseSpliceDescriptor decodes a splice descriptor, invalid descriptors are returned as
+// [SpliceUnknownDescriptor]
+func parseSpliceDescriptor(tag uint8, p []byte) SpliceDescriptor {
+	unknown := SpliceUnknownDescriptor{Tag: tag, Data: cloneBytes(p)}
+
+	if len(p) < 4 {
+		return unknown
+	}
+
+	unknown.Identifier = string(p[:4])
+	unknown.Data = cloneBytes(p[4:])
+
+	if unknown.Identifier != "CUEI" {
+		return unknown
+	}
+
+	p = p[4:]
+
+	switch tag {
+	case spliceDescriptorAvail:
+		if len(p) < 4 {
+			return unknown
+		}
+
+		return AvailDescriptorValue{ProviderAvailID: binary.BigEndian.Uint32(p)}
+	case spliceDescriptorTime:
+		if len(p) < 12 {
+			return unknown
+		}
+
+		return TimeDescriptorValue{
+			TAISeconds: uint64(binary.BigEndian.Uint16(p))<<32 | uint64(binary.BigEndian.Uint32(p[2:])),
+			TAINanos:   binary.BigEndian.Uint32(p[6:]),
+			UTCOffset:  binary.BigEndian.Uint16(p[10:]),
+		}
+	case spliceDescriptorSegmentation:
+		if d, ok := parseSegmentationDescriptor(p); ok {
+			return d
+		}
+	}
+
+	return unknown
+}
+
+func parseSegmentationDescriptor(p []byte) (SegmentationDescriptorValue, bool) {
+	var d SegmentationDescriptorValue
+
+	if len(p) < 5 {
+		return d, false
+	}
+
+	d.EventID = binary.BigEndian.Uint32(p)
+	d.Cancel = p[4]&0x80 != 0
+	p = p[5:]
+
+	if d.Cancel {
+		return d, true
+	}
+
+	if len(p) < 1 {
+		return d, false
+	}
+
+	flags := p[0]
+	p = p[1:]
+
+	d.ProgramSegmentation = flags&0x80 != 0
+	durationFlag := flags&0x40 != 0
+	d.DeliveryNotRestricted = flags&0x20 != 0
+
+	if !d.DeliveryNotRestricted {
+		d.WebDeliveryAllowed = flags&0x10 != 0
+		d.NoRegionalBlackout = flags&0x08 != 0
+		d.ArchiveAllowed = flags&0x04 != 0
+		d.DeviceRestrictions = flags & 0x03
+	}
+
+	if !d.ProgramSegmentation {
+		if len(p) < 1 {
+			return d, false
+		}
+
+		count := int(p[0])
+		p = p[1:]
+
+		if len(p) < count*6 {
+			return d, false
+		}
+
+		for range count {
+			d.Components = append(d.Components, SegmentationComponent{
+				Tag:       p[0],
+				PTSOffset: uint64(p[1]&0x01)<<32 | uint64(binary.BigEndian.Uint32(p[2:])),
+			})
+			p = p[6:]
+		}
+	}
+
+	if durationFlag {
+		if len(p) < 5 {
+			return d, false
+		}
+
+		duration := uint64(p[0])<<32 | uint64(binary.BigEndian.Uint32(p[1:]))
+		d.Duration = &duration
+		p = p[5:]
+	}
+
+	if len(p) < 2 || len(p) < 2+int(p[1]) {
+		return d, false
+	}
+
+	d.UPIDType = p[0]
+	d.UPID = cloneBytes(p[2 : 2+int(p[1])])
+	p = p[2+int(p[1]):]
+
+	if len(p) < 3 {
+		return d, false
+	}
+
+	d.TypeID = p[0]
+	d.SegmentNum = p[1]
+	d.SegmentsExpected = p[2]
+	p = p[3:]
+
+	// sub segments are only signalled for some segmentation types
+	if len(p) >= 2 {
+		d.SubSegmentNum = p[0]
+		d.SubSegmentsExpected = p[1]
+	}
+
+	return d, true
+}
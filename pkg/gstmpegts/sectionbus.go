@@ -0,0 +1,101 @@
+package gstmpegts
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// messageQueue is an unbounded queue of messages, the sync handler of a bus runs on the
+// streaming threads and must not block
+type messageQueue struct {
+	mu       sync.Mutex
+	messages []*gst.Message
+	notify   chan struct{}
+}
+
+func newMessageQueue() *messageQueue {
+	return &messageQueue{notify: make(chan struct{}, 1)}
+}
+
+func (q *messageQueue) push(message *gst.Message) {
+	q.mu.Lock()
+	q.messages = append(q.messages, message)
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop waits for the next message, it returns false when ctx is done
+func (q *messageQueue) pop(ctx context.Context) (*gst.Message, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.messages) > 0 {
+			message := q.messages[0]
+			q.messages[0] = nil
+			q.messages = q.messages[1:]
+			q.mu.Unlock()
+
+			return message, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.notify:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// busSectionMessages yields the section, EOS and error messages posted on the bus until ctx is
+// done or the iteration is stopped. Section messages are taken off the bus, all other messages
+// pass through to the handlers of the application.
+func busSectionMessages(ctx context.Context, bus gst.Bus) iter.Seq[*gst.Message] {
+	return func(yield func(*gst.Message) bool) {
+		queue := newMessageQueue()
+
+		bus.SetSyncHandler(func(_ gst.Bus, message *gst.Message) gst.BusSyncReply {
+			switch message.Type() {
+			case gst.MessageElement:
+				if MessageParseMpegtsSection(message) == nil {
+					return gst.BusPass
+				}
+
+				queue.push(message)
+
+				return gst.BusDrop
+			case gst.MessageEOS, gst.MessageError:
+				queue.push(message)
+			}
+
+			return gst.BusPass
+		})
+		defer bus.SetSyncHandler(nil)
+
+		for {
+			message, ok := queue.pop(ctx)
+			if !ok || !yield(message) {
+				return
+			}
+		}
+	}
+}
+
+// BusSections yields the decoded sections posted by tsdemux on the bus until ctx is done, the
+// bus posts EOS or an error, which is yielded as well. Only the section messages are taken off
+// the bus, EOS, errors and all other messages still reach the watch or the pop calls of the
+// application. It installs a sync handler on the bus while iterating, so it cannot be used
+// together with [gst.Bus.Messages] or another sync handler.
+//
+//	for section, err := range gstmpegts.BusSections(ctx, pipeline.GetBus()) {
+//		...
+//	}
+func BusSections(ctx context.Context, bus gst.Bus) iter.Seq2[DecodedSection, error] {
+	return Sections(busSectionMessages(ctx, bus))
+}
@@ -0,0 +1,506 @@
+package gstmpegts
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-mpegts-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/mpegts/mpegts.h>
+//
+// static gboolean _gogst_mpegts_datetime_to_unix(GstDateTime *dt, gint64 *secs) {
+//   GDateTime *g;
+//
+//   if (dt == NULL)
+//     return FALSE;
+//
+//   g = gst_date_time_to_g_date_time (dt);
+//   if (g == NULL)
+//     return FALSE;
+//
+//   *secs = g_date_time_to_unix (g);
+//   g_date_time_unref (g);
+//
+//   return TRUE;
+// }
+import "C"
+
+// ErrInvalidSection is returned when section data cannot be decoded.
+var ErrInvalidSection = errors.New("invalid MPEG-TS section")
+
+// SectionHeader contains the common fields of a section. For sections without the long section
+// syntax (TDT, TOT and SCTE-35) only PID and TableID are set.
+type SectionHeader struct {
+	// PID is the PID the section was received on, or 0x1fff if unknown.
+	PID     uint16
+	TableID uint8
+
+	// TableIDExtension is e.g. the transport stream id of a PAT or the program number of a PMT.
+	TableIDExtension  uint16
+	Version           uint8
+	CurrentNext       bool
+	SectionNumber     uint8
+	LastSectionNumber uint8
+}
+
+// Header returns the header of the section.
+func (h SectionHeader) Header() SectionHeader { return h }
+
+// DecodedSection is a section decoded by [DecodeSection] or [ParseSectionData]. The concrete
+// types are [PATTable], [CATTable], [PMTTable], [NITTable], [SDTTable], [EITTable], [TDTTable],
+// [TOTTable], [SpliceInfoSection], the ATSC tables [MGTTable], [ATSCEITTable], [ETTTable] and
+// [STTTable], and [UnknownTable].
+type DecodedSection interface {
+	Header() SectionHeader
+}
+
+// PATProgram is an entry of the program association table.
+type PATProgram struct {
+	ProgramNumber uint16
+	// PID is the PID of the PMT, or the network PID for program number 0.
+	PID uint16
+}
+
+// PATTable is the program association table.
+type PATTable struct {
+	SectionHeader
+	Programs []PATProgram
+}
+
+// TransportStreamID returns the id of the transport stream.
+func (t *PATTable) TransportStreamID() uint16 { return t.TableIDExtension }
+
+// CATTable is the conditional access table.
+type CATTable struct {
+	SectionHeader
+	Descriptors []DescriptorValue
+}
+
+// PMTTableStream is an elementary stream of a program map table.
+type PMTTableStream struct {
+	StreamType  uint8
+	PID         uint16
+	Descriptors []DescriptorValue
+}
+
+// PMTTable is the program map table of a single program.
+type PMTTable struct {
+	SectionHeader
+	PCRPID      uint16
+	Descriptors []DescriptorValue
+	Streams     []PMTTableStream
+}
+
+// ProgramNumber returns the program number the table describes.
+func (t *PMTTable) ProgramNumber() uint16 { return t.TableIDExtension }
+
+// NITTableStream is a transport stream of a network information table.
+type NITTableStream struct {
+	TransportStreamID uint16
+	OriginalNetworkID uint16
+	Descriptors       []DescriptorValue
+}
+
+// NITTable is the DVB network information table.
+type NITTable struct {
+	SectionHeader
+	// Actual is true if the table describes the network of the transport stream it was received on.
+	Actual           bool
+	Descriptors      []DescriptorValue
+	TransportStreams []NITTableStream
+}
+
+// NetworkID returns the id of the network.
+func (t *NITTable) NetworkID() uint16 { return t.TableIDExtension }
+
+// SDTTableService is a service of a service description table.
+type SDTTableService struct {
+	ServiceID           uint16
+	EITSchedule         bool
+	EITPresentFollowing bool
+	RunningStatus       uint8
+	FreeCAMode          bool
+	Descriptors         []DescriptorValue
+}
+
+// SDTTable is the DVB service description table.
+type SDTTable struct {
+	SectionHeader
+	// Actual is true if the table describes the transport stream it was received on.
+	Actual            bool
+	OriginalNetworkID uint16
+	Services          []SDTTableService
+}
+
+// TransportStreamID returns the id of the described transport stream.
+func (t *SDTTable) TransportStreamID() uint16 { return t.TableIDExtension }
+
+// EITTableEvent is an event of an event information table.
+type EITTableEvent struct {
+	EventID uint16
+	// StartTime is in UTC, it is the zero time if undefined.
+	StartTime     time.Time
+	Duration      time.Duration
+	RunningStatus uint8
+	FreeCAMode    bool
+	Descriptors   []DescriptorValue
+}
+
+// EITTable is the DVB event information table.
+type EITTable struct {
+	SectionHeader
+	// Actual is true if the table describes the transport stream it was received on.
+	Actual bool
+	// PresentFollowing is true for present/following tables and false for schedule tables.
+	PresentFollowing         bool
+	TransportStreamID        uint16
+	OriginalNetworkID        uint16
+	SegmentLastSectionNumber uint8
+	LastTableID              uint8
+	Events                   []EITTableEvent
+}
+
+// ServiceID returns the id of the service the events belong to.
+func (t *EITTable) ServiceID() uint16 { return t.TableIDExtension }
+
+// TDTTable is the DVB time and date table.
+type TDTTable struct {
+	SectionHeader
+	UTC time.Time
+}
+
+// TOTTable is the DVB time offset table.
+type TOTTable struct {
+	SectionHeader
+	UTC         time.Time
+	Descriptors []DescriptorValue
+}
+
+// UnknownTable is a section with a table id that is not decoded by this package.
+type UnknownTable struct {
+	SectionHeader
+	// Data is the payload of the section without header and CRC.
+	Data []byte
+}
+
+// DecodeSection decodes the section into one of the [DecodedSection] types. The tables are
+// parsed with libgstmpegts, which also verifies the CRC of long sections and converts the DVB and
+// ATSC strings to UTF-8.
+func DecodeSection(section *Section) (DecodedSection, error) {
+	C.gst_mpegts_initialize()
+
+	s := (*C.GstMpegtsSection)(UnsafeSectionToGlibNone(section))
+	defer runtime.KeepAlive(section)
+
+	h := SectionHeader{PID: uint16(s.pid), TableID: uint8(s.table_id)}
+
+	if s.short_section == 0 {
+		h.TableIDExtension = uint16(s.subtable_extension)
+		h.Version = uint8(s.version_number)
+		h.CurrentNext = s.current_next_indicator != 0
+		h.SectionNumber = uint8(s.section_number)
+		h.LastSectionNumber = uint8(s.last_section_number)
+	}
+
+	var decoded DecodedSection
+
+	switch s.section_type {
+	case C.GST_MPEGTS_SECTION_PAT:
+		decoded = decodePAT(h, s)
+	case C.GST_MPEGTS_SECTION_CAT:
+		decoded = decodeCAT(h, s)
+	case C.GST_MPEGTS_SECTION_PMT:
+		decoded = decodePMT(h, C.gst_mpegts_section_get_pmt(s))
+	case C.GST_MPEGTS_SECTION_NIT:
+		decoded = decodeNIT(h, C.gst_mpegts_section_get_nit(s))
+	case C.GST_MPEGTS_SECTION_SDT:
+		decoded = decodeSDT(h, C.gst_mpegts_section_get_sdt(s))
+	case C.GST_MPEGTS_SECTION_EIT:
+		decoded = decodeEIT(h, C.gst_mpegts_section_get_eit(s))
+	case C.GST_MPEGTS_SECTION_TDT:
+		decoded = decodeTDT(h, s)
+	case C.GST_MPEGTS_SECTION_TOT:
+		decoded = decodeTOT(h, C.gst_mpegts_section_get_tot(s))
+	case C.GST_MPEGTS_SECTION_SCTE_SIT:
+		decoded = decodeSpliceInfoSection(h, s)
+	case C.GST_MPEGTS_SECTION_ATSC_MGT:
+		decoded = decodeMGT(h, C.gst_mpegts_section_get_atsc_mgt(s))
+	case C.GST_MPEGTS_SECTION_ATSC_EIT:
+		decoded = decodeATSCEIT(h, C.gst_mpegts_section_get_atsc_eit(s))
+	case C.GST_MPEGTS_SECTION_ATSC_ETT:
+		decoded = decodeETT(h, C.gst_mpegts_section_get_atsc_ett(s))
+	case C.GST_MPEGTS_SECTION_ATSC_STT:
+		decoded = decodeSTT(h, C.gst_mpegts_section_get_atsc_stt(s))
+	default:
+		return &UnknownTable{SectionHeader: h, Data: sectionPayload(s)}, nil
+	}
+
+	if decoded == nil {
+		return nil, fmt.Errorf("%w: could not parse table 0x%02x on PID 0x%04x", ErrInvalidSection, h.TableID, h.PID)
+	}
+
+	return decoded, nil
+}
+
+// ParseSectionData decodes the raw data of a section, starting with the table id. The table is
+// identified by the table id and the pid, e.g. a PAT is only decoded on PID 0 and an EIT on
+// PID 0x12, tables that are not identified are returned as [UnknownTable].
+func ParseSectionData(pid uint16, data []byte) (DecodedSection, error) {
+	if len(data) < 3 {
+		return nil, fmt.Errorf("%w: short section", ErrInvalidSection)
+	}
+
+	C.gst_mpegts_initialize()
+
+	// the section takes ownership of the data
+	cdata := C.g_malloc(C.gsize(len(data)))
+	copy(unsafe.Slice((*byte)(cdata), len(data)), data)
+
+	csection := C.gst_mpegts_section_new(C.guint16(pid), (*C.guint8)(cdata), C.gsize(len(data)))
+	if csection == nil {
+		return nil, fmt.Errorf("%w: invalid section header", ErrInvalidSection)
+	}
+
+	return DecodeSection(UnsafeSectionFromGlibFull(unsafe.Pointer(csection)))
+}
+
+// sectionPayload returns the data of the section without header and CRC
+func sectionPayload(s *C.GstMpegtsSection) []byte {
+	if s.data == nil {
+		return nil
+	}
+
+	data := unsafe.Slice((*byte)(unsafe.Pointer(s.data)), int(s.section_length))
+
+	if s.short_section != 0 {
+		return cloneBytes(data[min(3, len(data)):])
+	}
+
+	if len(data) < 12 {
+		return nil
+	}
+
+	return cloneBytes(data[8 : len(data)-4])
+}
+
+// ptrArray returns the elements of a GPtrArray
+func ptrArray[T any](arr *C.GPtrArray) []*T {
+	if arr == nil || arr.len == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((**T)(unsafe.Pointer(arr.pdata)), int(arr.len))
+}
+
+// dateTimeToGo converts a GstDateTime parsed by libgstmpegts, nil and incomplete dates are
+// returned as zero time
+func dateTimeToGo(dt *C.GstDateTime) time.Time {
+	var secs C.gint64
+
+	if C._gogst_mpegts_datetime_to_unix(dt, &secs) == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(secs), 0).UTC()
+}
+
+func decodePAT(h SectionHeader, s *C.GstMpegtsSection) DecodedSection {
+	programs := C.gst_mpegts_section_get_pat(s)
+	if programs == nil {
+		return nil
+	}
+	defer C.g_ptr_array_unref(programs)
+
+	t := &PATTable{SectionHeader: h}
+
+	for _, p := range ptrArray[C.GstMpegtsPatProgram](programs) {
+		t.Programs = append(t.Programs, PATProgram{
+			ProgramNumber: uint16(p.program_number),
+			PID:           uint16(p.network_or_program_map_PID),
+		})
+	}
+
+	return t
+}
+
+func decodeCAT(h SectionHeader, s *C.GstMpegtsSection) DecodedSection {
+	descriptors := C.gst_mpegts_section_get_cat(s)
+	if descriptors == nil {
+		return nil
+	}
+	defer C.g_ptr_array_unref(descriptors)
+
+	return &CATTable{SectionHeader: h, Descriptors: descriptorValues(descriptors)}
+}
+
+func decodePMT(h SectionHeader, pmt *C.GstMpegtsPMT) DecodedSection {
+	if pmt == nil {
+		return nil
+	}
+
+	t := &PMTTable{
+		SectionHeader: h,
+		PCRPID:        uint16(pmt.pcr_pid),
+		Descriptors:   descriptorValues(pmt.descriptors),
+	}
+
+	for _, s := range ptrArray[C.GstMpegtsPMTStream](pmt.streams) {
+		t.Streams = append(t.Streams, PMTTableStream{
+			StreamType:  uint8(s.stream_type),
+			PID:         uint16(s.pid),
+			Descriptors: descriptorValues(s.descriptors),
+		})
+	}
+
+	return t
+}
+
+func decodeNIT(h SectionHeader, nit *C.GstMpegtsNIT) DecodedSection {
+	if nit == nil {
+		return nil
+	}
+
+	t := &NITTable{
+		SectionHeader: h,
+		Actual:        nit.actual_network != 0,
+		Descriptors:   descriptorValues(nit.descriptors),
+	}
+
+	for _, s := range ptrArray[C.GstMpegtsNITStream](nit.streams) {
+		t.TransportStreams = append(t.TransportStreams, NITTableStream{
+			TransportStreamID: uint16(s.transport_stream_id),
+			OriginalNetworkID: uint16(s.original_network_id),
+			Descriptors:       descriptorValues(s.descriptors),
+		})
+	}
+
+	return t
+}
+
+func decodeSDT(h SectionHeader, sdt *C.GstMpegtsSDT) DecodedSection {
+	if sdt == nil {
+		return nil
+	}
+
+	t := &SDTTable{
+		SectionHeader:     h,
+		Actual:            sdt.actual_ts != 0,
+		OriginalNetworkID: uint16(sdt.original_network_id),
+	}
+
+	for _, s := range ptrArray[C.GstMpegtsSDTService](sdt.services) {
+		t.Services = append(t.Services, SDTTableService{
+			ServiceID:           uint16(s.service_id),
+			EITSchedule:         s.EIT_schedule_flag != 0,
+			EITPresentFollowing: s.EIT_present_following_flag != 0,
+			RunningStatus:       uint8(s.running_status),
+			FreeCAMode:          s.free_CA_mode != 0,
+			Descriptors:         descriptorValues(s.descriptors),
+		})
+	}
+
+	return t
+}
+
+func decodeEIT(h SectionHeader, eit *C.GstMpegtsEIT) DecodedSection {
+	if eit == nil {
+		return nil
+	}
+
+	t := &EITTable{
+		SectionHeader:            h,
+		Actual:                   eit.actual_stream != 0,
+		PresentFollowing:         eit.present_following != 0,
+		TransportStreamID:        uint16(eit.transport_stream_id),
+		OriginalNetworkID:        uint16(eit.original_network_id),
+		SegmentLastSectionNumber: uint8(eit.segment_last_section_number),
+		LastTableID:              uint8(eit.last_table_id),
+	}
+
+	for _, e := range ptrArray[C.GstMpegtsEITEvent](eit.events) {
+		t.Events = append(t.Events, EITTableEvent{
+			EventID:       uint16(e.event_id),
+			StartTime:     dateTimeToGo(e.start_time),
+			Duration:      time.Duration(e.duration) * time.Second,
+			RunningStatus: uint8(e.running_status),
+			FreeCAMode:    e.free_CA_mode != 0,
+			Descriptors:   descriptorValues(e.descriptors),
+		})
+	}
+
+	return t
+}
+
+func decodeTDT(h SectionHeader, s *C.GstMpegtsSection) DecodedSection {
+	utc := C.gst_mpegts_section_get_tdt(s)
+	if utc == nil {
+		return nil
+	}
+	defer C.gst_date_time_unref(utc)
+
+	return &TDTTable{SectionHeader: h, UTC: dateTimeToGo(utc)}
+}
+
+func decodeTOT(h SectionHeader, tot *C.GstMpegtsTOT) DecodedSection {
+	if tot == nil {
+		return nil
+	}
+
+	return &TOTTable{
+		SectionHeader: h,
+		UTC:           dateTimeToGo(tot.utc_time),
+		Descriptors:   descriptorValues(tot.descriptors),
+	}
+}
+
+// MessageDecodeSection decodes the section of a section message posted by tsdemux. It returns
+// nil and no error if the message does not carry a section.
+func MessageDecodeSection(message *gst.Message) (DecodedSection, error) {
+	section := MessageParseMpegtsSection(message)
+	if section == nil {
+		return nil, nil
+	}
+
+	return DecodeSection(section)
+}
+
+// Sections yields the decoded sections of the section messages in messages until an EOS message,
+// other messages are skipped. Sections that cannot be decoded and error messages are yielded with
+// an error, the iteration ends after an error message. Use [BusSections] to get the sections of
+// a bus without consuming its other messages, or [MessageDecodeSection] to decode single
+// messages in an existing bus handler.
+//
+//	for section, err := range gstmpegts.Sections(messages) {
+//		...
+//	}
+func Sections(messages iter.Seq[*gst.Message]) iter.Seq2[DecodedSection, error] {
+	return func(yield func(DecodedSection, error) bool) {
+		for message := range messages {
+			switch message.Type() {
+			case gst.MessageEOS:
+				return
+			case gst.MessageError:
+				_, err := message.ParseError()
+				yield(nil, err)
+
+				return
+			}
+
+			section, err := MessageDecodeSection(message)
+			if section == nil && err == nil {
+				continue
+			}
+
+			if !yield(section, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package gstmpegts_test
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstmpegts"
+)
+
+// crc computes the CRC-32/MPEG-2 checksum
+func crc(data []byte) uint32 {
+	c := uint32(0xffffffff)
+
+	for _, b := range data {
+		c ^= uint32(b) << 24
+
+		for range 8 {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ 0x04c11db7
+			} else {
+				c <<= 1
+			}
+		}
+	}
+
+	return c
+}
+
+// buildSection creates a long section with the given table id extension and version 3
+func buildSection(tableID byte, ext uint16, body []byte) []byte {
+	length := 5 + len(body) + 4
+
+	data := []byte{tableID, 0xb0 | byte(length>>8), byte(length), byte(ext >> 8), byte(ext), 0xc1 | 3<<1, 0, 0}
+	data = append(data, body...)
+
+	return appendCRC(data)
+}
+
+// buildShortSection creates a section without the long section syntax that ends with a CRC
+func buildShortSection(tableID byte, body []byte) []byte {
+	length := len(body) + 4
+
+	data := append([]byte{tableID, 0x30 | byte(length>>8), byte(length)}, body...)
+
+	return appendCRC(data)
+}
+
+func appendCRC(data []byte) []byte {
+	c := crc(data)
+
+	return append(data, byte(c>>24), byte(c>>16), byte(c>>8), byte(c))
+}
+
+func TestParsePAT(t *testing.T) {
+	data := buildSection(0x00, 1, []byte{0x00, 0x00, 0xe0, 0x10, 0x00, 0x01, 0xe1, 0x00})
+
+	section, err := gstmpegts.ParseSectionData(0, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pat, ok := section.(*gstmpegts.PATTable)
+	if !ok {
+		t.Fatalf("unexpected section %T", section)
+	}
+
+	if pat.TransportStreamID() != 1 || pat.Version != 3 || !pat.CurrentNext {
+		t.Errorf("unexpected header %+v", pat.Header())
+	}
+
+	expected := []gstmpegts.PATProgram{{ProgramNumber: 0, PID: 0x10}, {ProgramNumber: 1, PID: 0x100}}
+	if !reflect.DeepEqual(pat.Programs, expected) {
+		t.Errorf("unexpected programs %+v", pat.Programs)
+	}
+}
+
+func TestParsePMT(t *testing.T) {
+	body := []byte{
+		0xe1, 0x01, // PCR PID
+		0xf0, 0x06, 0x05, 0x04, 'C', 'U', 'E', 'I', // registration descriptor
+		0x1b, 0xe1, 0x01, 0xf0, 0x00, // H.264
+		0x0f, 0xe1, 0x02, 0xf0, 0x06, 0x0a, 0x04, 'e', 'n', 'g', 0x00, // AAC with language
+		0x86, 0xe1, 0x03, 0xf0, 0x00, // SCTE 35
+	}
+
+	section, err := gstmpegts.ParseSectionData(0x100, buildSection(0x02, 1, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pmt, ok := section.(*gstmpegts.PMTTable)
+	if !ok {
+		t.Fatalf("unexpected section %T", section)
+	}
+
+	if pmt.PID != 0x100 || pmt.ProgramNumber() != 1 || pmt.PCRPID != 0x101 {
+		t.Errorf("unexpected table %+v", pmt)
+	}
+
+	if !reflect.DeepEqual(pmt.Descriptors, []gstmpegts.DescriptorValue{gstmpegts.RegistrationDescriptorValue{FormatIdentifier: "CUEI"}}) {
+		t.Errorf("unexpected descriptors %+v", pmt.Descriptors)
+	}
+
+	if len(pmt.Streams) != 3 || pmt.Streams[1].StreamType != 0x0f || pmt.Streams[2].PID != 0x103 {
+		t.Fatalf("unexpected streams %+v", pmt.Streams)
+	}
+
+	language := gstmpegts.LanguageDescriptorValue{Languages: []gstmpegts.Language{{Code: "eng"}}}
+	if !reflect.DeepEqual(pmt.Streams[1].Descriptors, []gstmpegts.DescriptorValue{language}) {
+		t.Errorf("unexpected stream descriptors %+v", pmt.Streams[1].Descriptors)
+	}
+}
+
+func TestParseSDT(t *testing.T) {
+	service := []byte{0x48, 0x0b, 0x01, 0x03, 'g', 'o', 'g', 0x05, 0x15, 'N', 'e', 'w', 's'}
+
+	body := append([]byte{0x00, 0x02, 0xff, 0x00, 0x0a, 0xff, 0x80 | byte(len(service)>>8), byte(len(service))}, service...)
+
+	section, err := gstmpegts.ParseSectionData(0x11, buildSection(0x42, 1, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sdt, ok := section.(*gstmpegts.SDTTable)
+	if !ok {
+		t.Fatalf("unexpected section %T", section)
+	}
+
+	if !sdt.Actual || sdt.OriginalNetworkID != 2 || len(sdt.Services) != 1 {
+		t.Fatalf("unexpected table %+v", sdt)
+	}
+
+	s := sdt.Services[0]
+	if s.ServiceID != 10 || !s.EITSchedule || !s.EITPresentFollowing || s.RunningStatus != 4 {
+		t.Errorf("unexpected service %+v", s)
+	}
+
+	expected := gstmpegts.ServiceDescriptorValue{Type: 1, Provider: "gog", Name: "News"}
+	if !reflect.DeepEqual(s.Descriptors, []gstmpegts.DescriptorValue{expected}) {
+		t.Errorf("unexpected descriptors %+v", s.Descriptors)
+	}
+}
+
+func TestParseEIT(t *testing.T) {
+	descriptors := []byte{
+		0x4d, 0x0c, 'e', 'n', 'g', 0x04, 'T', 'e', 's', 't', 0x03, 'a', 'b', 'c', // short event
+		0x54, 0x02, 0x12, 0x00, // content
+		0x55, 0x04, 'G', 'B', 'R', 0x09, // parental rating
+	}
+
+	body := []byte{0x00, 0x01, 0x00, 0x02, 0x00, 0x4e}
+	body = append(body, 0x00, 0x07, 0xc0, 0x79, 0x12, 0x45, 0x00, 0x01, 0x30, 0x00)
+	body = append(body, 0x80|byte(len(descriptors)>>8), byte(len(descriptors)))
+	body = append(body, descriptors...)
+
+	section, err := gstmpegts.ParseSectionData(0x12, buildSection(0x4e, 10, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eit, ok := section.(*gstmpegts.EITTable)
+	if !ok {
+		t.Fatalf("unexpected section %T", section)
+	}
+
+	if eit.ServiceID() != 10 || !eit.Actual || !eit.PresentFollowing || eit.TransportStreamID != 1 || len(eit.Events) != 1 {
+		t.Fatalf("unexpected table %+v", eit)
+	}
+
+	e := eit.Events[0]
+
+	if !e.StartTime.Equal(time.Date(1993, time.October, 13, 12, 45, 0, 0, time.UTC)) {
+		t.Errorf("unexpected start time %v", e.StartTime)
+	}
+
+	if e.Duration != 90*time.Minute || e.RunningStatus != 4 {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	expected := []gstmpegts.DescriptorValue{
+		gstmpegts.ShortEventDescriptorValue{Language: "eng", Name: "Test", Text: "abc"},
+		gstmpegts.ContentDescriptorValue{Contents: []gstmpegts.ContentNibbles{{Level1: 1, Level2: 2}}},
+		gstmpegts.ParentalRatingDescriptorValue{Ratings: []gstmpegts.ParentalRating{{Country: "GBR", Rating: 9}}},
+	}
+
+	if !reflect.DeepEqual(e.Descriptors, expected) {
+		t.Errorf("unexpected descriptors %+v", e.Descriptors)
+	}
+}
+
+func TestParseTDT(t *testing.T) {
+	data := []byte{0x70, 0x70, 0x05, 0xc0, 0x79, 0x12, 0x45, 0x30}
+
+	section, err := gstmpegts.ParseSectionData(0x14, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tdt, ok := section.(*gstmpegts.TDTTable)
+	if !ok || !tdt.UTC.Equal(time.Date(1993, time.October, 13, 12, 45, 30, 0, time.UTC)) {
+		t.Errorf("unexpected section %+v", section)
+	}
+}
+
+func TestParseSpliceInsert(t *testing.T) {
+	command := []byte{
+		0x00, 0x00, 0x00, 0x2a, // event id
+		0x7f,                         // not cancelled
+		0xef,                         // out of network, program splice, duration
+		0xfe, 0x00, 0x01, 0x5f, 0x90, // splice time 90000
+		0xfe, 0x00, 0x29, 0x32, 0xe0, // auto return, 30s break
+		0x00, 0x01, 0x02, 0x03, // unique program id, avail num, avails expected
+	}
+
+	body := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xf0 | byte(len(command)>>8), byte(len(command)), 0x05}
+	body = append(body, command...)
+	body = append(body, 0x00, 0x0a, 0x00, 0x08, 'C', 'U', 'E', 'I', 0x00, 0x00, 0x00, 0x07)
+
+	section, err := gstmpegts.ParseSectionData(0x1f0, buildShortSection(0xfc, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	splice, ok := section.(*gstmpegts.SpliceInfoSection)
+	if !ok {
+		t.Fatalf("unexpected section %T", section)
+	}
+
+	insert, ok := splice.Command.(gstmpegts.SpliceInsert)
+	if !ok {
+		t.Fatalf("unexpected command %T", splice.Command)
+	}
+
+	expected := gstmpegts.SpliceInsert{
+		EventID:         42,
+		OutOfNetwork:    true,
+		ProgramSplice:   true,
+		Time:            gstmpegts.SpliceTime{Specified: true, PTS: 90000},
+		BreakDuration:   &gstmpegts.SpliceBreakDuration{AutoReturn: true, Duration: 30 * 90000},
+		UniqueProgramID: 1,
+		AvailNum:        2,
+		AvailsExpected:  3,
+	}
+
+	if !reflect.DeepEqual(insert, expected) {
+		t.Errorf("unexpected splice insert %+v", insert)
+	}
+
+	if gstmpegts.PTSToDuration(insert.BreakDuration.Duration) != 30*time.Second {
+		t.Errorf("unexpected break duration %v", gstmpegts.PTSToDuration(insert.BreakDuration.Duration))
+	}
+
+	if !reflect.DeepEqual(splice.Descriptors, []gstmpegts.SpliceDescriptor{gstmpegts.AvailDescriptorValue{ProviderAvailID: 7}}) {
+		t.Errorf("unexpected descriptors %+v", splice.Descriptors)
+	}
+}
+
+func TestParseSectionCRCMismatch(t *testing.T) {
+	data := buildSection(0x00, 1, []byte{0x00, 0x01, 0xe1, 0x00})
+	data[len(data)-1] ^= 0xff
+
+	if _, err := gstmpegts.ParseSectionData(0, data); !errors.Is(err, gstmpegts.ErrInvalidSection) {
+		t.Errorf("expected invalid section error, got %v", err)
+	}
+}
+
+func TestParseDescriptorsCharset(t *testing.T) {
+	// network name in ISO 8859-5 and a service name in UTF-8
+	data := []byte{
+		0x40, 0x04, 0x01, 0xbf, 0xe0, 0xd8,
+		0x48, 0x08, 0x01, 0x00, 0x05, 0x15, 0xd0, 0x9f, 0xd1, 0x80,
+	}
+
+	descriptors, err := gstmpegts.ParseDescriptors(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []gstmpegts.DescriptorValue{
+		gstmpegts.NetworkNameDescriptorValue{Name: "При"},
+		gstmpegts.ServiceDescriptorValue{Type: 1, Name: "Пр"},
+	}
+
+	if !reflect.DeepEqual(descriptors, expected) {
+		t.Errorf("unexpected descriptors %+v", descriptors)
+	}
+}
+
+func TestSections(t *testing.T) {
+	gst.Init()
+
+	tdt := gstmpegts.NewSection(0x14, []byte{0x70, 0x70, 0x05, 0xc0, 0x79, 0x12, 0x45, 0x30})
+	failure := errors.New("demux failed")
+
+	messages := []*gst.Message{
+		gst.NewMessageApplication(nil, gst.NewStructureEmpty("other")),
+		gstmpegts.MessageNewMpegtsSection(nil, tdt),
+		gst.NewMessageError(nil, "", failure),
+		gstmpegts.MessageNewMpegtsSection(nil, tdt),
+	}
+
+	var sections []gstmpegts.DecodedSection
+	var errs []error
+
+	for section, err := range gstmpegts.Sections(slices.Values(messages)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		sections = append(sections, section)
+	}
+
+	if len(sections) != 1 {
+		t.Errorf("expected the section before the error, got %v", sections)
+	}
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), failure.Error()) {
+		t.Errorf("expected the error message to end the sections, got %v", errs)
+	}
+
+	messages[2] = gst.NewMessageEOS(nil)
+
+	count := 0
+	for range gstmpegts.Sections(slices.Values(messages)) {
+		count++
+	}
+
+	if count != 1 {
+		t.Errorf("expected EOS to end the sections, got %d", count)
+	}
+}
@@ -0,0 +1,151 @@
+package gstpbutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// ErrMissingPlugins is returned in a [BatchResult] if plugins are missing to discover the URI.
+var ErrMissingPlugins = errors.New("missing plugins")
+
+// BatchOptions configures [DiscoverBatch].
+type BatchOptions struct {
+	// Workers is the number of discoverers that run concurrently, it defaults to the number of
+	// CPUs.
+	Workers int
+	// Timeout is the timeout of a single URI, it defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// BatchResult is the result of a single URI of [DiscoverBatch].
+type BatchResult struct {
+	URI string
+	// Info is the discovered information, it may be set even if Err is not nil.
+	Info DiscovererInfo
+	Err  error
+	// MissingPlugins contains the installer details of the missing plugins, they can be passed
+	// to InstallPluginsAsync.
+	MissingPlugins []string
+}
+
+// DiscoverBatch discovers the uris with a pool of discoverers and yields the results in the
+// order of completion. uris is consumed lazily from a single goroutine, which has returned once
+// the iteration over the results ended. The discovery stops when ctx is done or when the caller
+// stops the iteration.
+//
+//	for result := range gstpbutils.DiscoverBatch(ctx, slices.Values(uris), gstpbutils.BatchOptions{}) {
+//		if result.Err != nil {
+//			...
+//		}
+//	}
+func DiscoverBatch(ctx context.Context, uris iter.Seq[string], opts BatchOptions) iter.Seq[BatchResult] {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	return func(yield func(BatchResult) bool) {
+		discoverers := make([]Discoverer, 0, opts.Workers)
+
+		for range opts.Workers {
+			d, err := NewDiscoverer(gst.ClockTime(opts.Timeout))
+			if err != nil {
+				yield(BatchResult{Err: fmt.Errorf("could not create discoverer: %w", err)})
+
+				return
+			}
+
+			discoverers = append(discoverers, d)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		jobs := make(chan string)
+		results := make(chan BatchResult, opts.Workers)
+		produced := make(chan struct{})
+
+		go func() {
+			defer close(produced)
+			defer close(jobs)
+
+			for uri := range uris {
+				select {
+				case jobs <- uri:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		var wg sync.WaitGroup
+
+		for _, d := range discoverers {
+			wg.Go(func() {
+				for uri := range jobs {
+					info, err := d.DiscoverURIContext(ctx, uri)
+					if ctx.Err() != nil {
+						return
+					}
+
+					select {
+					case results <- newBatchResult(uri, info, err):
+					case <-ctx.Done():
+						return
+					}
+				}
+			})
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for result := range results {
+			if !yield(result) {
+				break
+			}
+		}
+
+		// stop the workers and wait for them, so no discoverer is running anymore
+		cancel()
+
+		for range results {
+		}
+
+		// the producer may still be inside the iterator of the caller
+		<-produced
+	}
+}
+
+func newBatchResult(uri string, info DiscovererInfo, err error) BatchResult {
+	r := BatchResult{URI: uri, Info: info, Err: err}
+
+	if info == nil || info.GetResult() != DiscovererMissingPlugins {
+		return r
+	}
+
+	r.MissingPlugins = info.GetMissingElementsInstallerDetails()
+
+	missing := fmt.Errorf("%w: %s", ErrMissingPlugins, strings.Join(r.MissingPlugins, ", "))
+
+	if err != nil {
+		r.Err = errors.Join(missing, err)
+	} else {
+		r.Err = missing
+	}
+
+	return r
+}
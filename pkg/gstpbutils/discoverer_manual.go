@@ -0,0 +1,78 @@
+package gstpbutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/go-gst/go-glib/pkg/glib/v2"
+)
+
+// ErrDiscovererBusy is returned if the discoverer did not accept the URI.
+var ErrDiscovererBusy = errors.New("discoverer did not accept the uri")
+
+type DiscovererExtManual interface {
+	// DiscoverURIContext discovers the uri like DiscoverURI, but cancels the discovery when ctx
+	// is done. It uses the async API of the discoverer and must not be called concurrently on
+	// the same discoverer.
+	DiscoverURIContext(ctx context.Context, uri string) (DiscovererInfo, error)
+}
+
+// DiscoverURIContext discovers the uri like DiscoverURI, but cancels the discovery when ctx
+// is done. It uses the async API of the discoverer and must not be called concurrently on
+// the same discoverer.
+func (discoverer *DiscovererInstance) DiscoverURIContext(ctx context.Context, uri string) (DiscovererInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the async discoverer dispatches its signals on the thread default main context, which is
+	// iterated on this goroutine
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	mainContext := glib.NewMainContext()
+	mainContext.PushThreadDefault()
+	defer mainContext.PopThreadDefault()
+
+	var info DiscovererInfo
+	var discoverErr error
+	done := false
+
+	discovered := discoverer.ConnectDiscovered(func(_ Discoverer, i DiscovererInfo, err error) {
+		if i != nil && i.GetURI() != uri {
+			return
+		}
+
+		info, discoverErr, done = i, err, true
+	})
+	defer discoverer.HandlerDisconnect(discovered)
+
+	finished := discoverer.ConnectFinished(func(Discoverer) {
+		done = true
+	})
+	defer discoverer.HandlerDisconnect(finished)
+
+	discoverer.Start()
+	defer discoverer.Stop()
+
+	if !discoverer.DiscoverURIAsync(uri) {
+		return nil, fmt.Errorf("%w: %s", ErrDiscovererBusy, uri)
+	}
+
+	stop := context.AfterFunc(ctx, mainContext.Wakeup)
+	defer stop()
+
+	for !done && ctx.Err() == nil {
+		mainContext.Iteration(true)
+	}
+
+	if info == nil && discoverErr == nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, discoverErr
+}
@@ -0,0 +1,255 @@
+package gstpbutils
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/core/transfer"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-pbutils-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/pbutils/pbutils.h>
+import "C"
+
+var (
+	// ErrInvalidCaps is returned if the caps of a profile cannot be parsed.
+	ErrInvalidCaps = errors.New("invalid caps")
+	// ErrInvalidEncodingProfile is returned if a profile cannot be built or added to a target.
+	ErrInvalidEncodingProfile = errors.New("invalid encoding profile")
+	// ErrInvalidEncodingTarget is returned if the name or category of a target is not valid.
+	ErrInvalidEncodingTarget = errors.New("invalid encoding target")
+	// ErrEncodingProfileNotFound is returned if a target does not contain the requested profile.
+	ErrEncodingProfileNotFound = errors.New("encoding profile not found")
+)
+
+// EncodingStreamOptions configures a stream profile of an [EncodingProfileBuilder].
+type EncodingStreamOptions struct {
+	// Name is the name of the stream profile, it is optional.
+	Name string
+	// Preset is the name of the element preset that is applied to the encoder.
+	Preset string
+	// PresetName is the name of the element factory that is used as encoder, e.g. "x264enc".
+	PresetName string
+	// Restriction are the caps of the raw stream before it is encoded, e.g.
+	// "video/x-raw,width=1280,height=720".
+	Restriction string
+	// Presence is the number of times the stream profile can be used, 0 means any number of
+	// times.
+	Presence uint
+	// VariableFramerate disables the framerate conversion of video streams.
+	VariableFramerate bool
+	// Pass is the pass number of multi-pass video encodings, 0 means single pass.
+	Pass uint
+}
+
+type encodingStream struct {
+	video bool
+	caps  string
+	opts  EncodingStreamOptions
+}
+
+// EncodingProfileBuilder assembles an [EncodingProfile] from caps strings. Errors are reported by
+// Build.
+//
+//	profile, err := gstpbutils.NewEncodingProfileBuilder("webm", "video/webm").
+//		Video("video/x-vp8", gstpbutils.EncodingStreamOptions{Restriction: "video/x-raw,width=1280,height=720"}).
+//		Audio("audio/x-vorbis", gstpbutils.EncodingStreamOptions{}).
+//		Build()
+type EncodingProfileBuilder struct {
+	name        string
+	description string
+	caps        string
+	preset      string
+	streams     []encodingStream
+}
+
+// NewEncodingProfileBuilder creates a builder for a profile with the given container caps, e.g.
+// "video/quicktime,variant=iso". If containerCaps is empty the profile has no container and must
+// contain exactly one stream.
+func NewEncodingProfileBuilder(name, containerCaps string) *EncodingProfileBuilder {
+	return &EncodingProfileBuilder{name: name, caps: containerCaps}
+}
+
+// Description sets the description of the profile.
+func (b *EncodingProfileBuilder) Description(description string) *EncodingProfileBuilder {
+	b.description = description
+
+	return b
+}
+
+// Preset sets the element preset that is applied to the muxer.
+func (b *EncodingProfileBuilder) Preset(preset string) *EncodingProfileBuilder {
+	b.preset = preset
+
+	return b
+}
+
+// Video adds a video stream that is encoded to caps.
+func (b *EncodingProfileBuilder) Video(caps string, opts EncodingStreamOptions) *EncodingProfileBuilder {
+	b.streams = append(b.streams, encodingStream{video: true, caps: caps, opts: opts})
+
+	return b
+}
+
+// Audio adds an audio stream that is encoded to caps. VariableFramerate and Pass are ignored.
+func (b *EncodingProfileBuilder) Audio(caps string, opts EncodingStreamOptions) *EncodingProfileBuilder {
+	b.streams = append(b.streams, encodingStream{caps: caps, opts: opts})
+
+	return b
+}
+
+// Build creates the profile. It is an [EncodingContainerProfile] unless the builder has no
+// container caps.
+func (b *EncodingProfileBuilder) Build() (EncodingProfile, error) {
+	if len(b.streams) == 0 {
+		return nil, fmt.Errorf("%w: %s: no streams", ErrInvalidEncodingProfile, b.name)
+	}
+
+	if b.caps == "" {
+		if len(b.streams) != 1 {
+			return nil, fmt.Errorf("%w: %s: a profile without container must have exactly one stream", ErrInvalidEncodingProfile, b.name)
+		}
+
+		profile, err := b.streams[0].build()
+		if err != nil {
+			return nil, err
+		}
+
+		profile.SetName(b.name)
+		profile.SetDescription(b.description)
+
+		return profile, nil
+	}
+
+	format, err := parseCaps(b.caps)
+	if err != nil {
+		return nil, err
+	}
+
+	container := NewEncodingContainerProfile(b.name, b.description, format, b.preset)
+
+	for _, s := range b.streams {
+		profile, err := s.build()
+		if err != nil {
+			return nil, err
+		}
+
+		if !container.AddProfile(profile) {
+			return nil, fmt.Errorf("%w: %s: could not add stream %s", ErrInvalidEncodingProfile, b.name, s.caps)
+		}
+	}
+
+	return container, nil
+}
+
+func (s encodingStream) build() (EncodingProfile, error) {
+	format, err := parseCaps(s.caps)
+	if err != nil {
+		return nil, err
+	}
+
+	var restriction *gst.Caps
+
+	if s.opts.Restriction != "" {
+		if restriction, err = parseCaps(s.opts.Restriction); err != nil {
+			return nil, err
+		}
+	}
+
+	var profile EncodingProfile
+
+	if s.video {
+		video := NewEncodingVideoProfile(format, s.opts.Preset, restriction, s.opts.Presence)
+		video.SetVariableframerate(s.opts.VariableFramerate)
+		video.SetPass(s.opts.Pass)
+
+		profile = video
+	} else {
+		profile = NewEncodingAudioProfile(format, s.opts.Preset, restriction, s.opts.Presence)
+	}
+
+	if s.opts.Name != "" {
+		profile.SetName(s.opts.Name)
+	}
+
+	if s.opts.PresetName != "" {
+		profile.SetPresetName(s.opts.PresetName)
+	}
+
+	return profile, nil
+}
+
+func parseCaps(caps string) (*gst.Caps, error) {
+	parsed := gst.CapsFromString(caps)
+	if parsed == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCaps, caps)
+	}
+
+	return parsed, nil
+}
+
+// NewEncodingTarget wraps gst_encoding_target_new and adds the profiles to the target. name and
+// category may only contain lowercase ASCII letters, digits and hyphens.
+func NewEncodingTarget(name, category, description string, profiles ...EncodingProfile) (EncodingTarget, error) {
+	var carg1 *C.gchar            // in, none, string
+	var carg2 *C.gchar            // in, none, string
+	var carg3 *C.gchar            // in, none, string
+	var cret *C.GstEncodingTarget // return, full, converted, nullable
+
+	carg1 = (*C.gchar)(transfer.GLibString(name))
+	defer C.g_free(C.gpointer(carg1))
+	carg2 = (*C.gchar)(transfer.GLibString(category))
+	defer C.g_free(C.gpointer(carg2))
+	carg3 = (*C.gchar)(transfer.GLibString(description))
+	defer C.g_free(C.gpointer(carg3))
+
+	cret = C.gst_encoding_target_new(carg1, carg2, carg3, nil)
+
+	if cret == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrInvalidEncodingTarget, category, name)
+	}
+
+	target := UnsafeEncodingTargetFromGlibFull(unsafe.Pointer(cret))
+
+	for _, profile := range profiles {
+		if !target.AddProfile(profile) {
+			return nil, fmt.Errorf("%w: %s: duplicate profile name in target %s", ErrInvalidEncodingProfile, profile.GetName(), name)
+		}
+	}
+
+	return target, nil
+}
+
+// LoadEncodingProfile loads the profile with the given name from the .gep encoding target file at
+// path.
+func LoadEncodingProfile(path, name string) (EncodingProfile, error) {
+	target, err := EncodingTargetLoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load encoding target %s: %w", path, err)
+	}
+
+	profile := target.GetProfile(name)
+	if profile == nil {
+		return nil, fmt.Errorf("%w: %s in %s", ErrEncodingProfileNotFound, name, path)
+	}
+
+	return profile, nil
+}
+
+// SaveEncodingProfiles saves the profiles as encoding target to the .gep file at path, they can
+// be loaded with [LoadEncodingProfile] or [EncodingTargetLoadFromFile].
+func SaveEncodingProfiles(path, name, category, description string, profiles ...EncodingProfile) error {
+	target, err := NewEncodingTarget(name, category, description, profiles...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := target.SaveToFile(path); err != nil {
+		return fmt.Errorf("could not save encoding target %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -2299,6 +2299,7 @@ var _ Discoverer = (*DiscovererInstance)(nil)
 // 
 // see also https://gstreamer.freedesktop.org/documentation/pbutils/gstdiscoverer.html#GstDiscoverer
 type Discoverer interface {
+	DiscovererExtManual // handwritten functions
 	gobject.Object
 	upcastToGstDiscoverer() *DiscovererInstance
 
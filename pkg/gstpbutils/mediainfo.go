@@ -0,0 +1,364 @@
+package gstpbutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-pbutils-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/pbutils/pbutils.h>
+import "C"
+
+// MediaInfo is the result of a discovery converted to plain Go values, see [NewMediaInfo]. It
+// can be marshalled to JSON, durations are given in seconds.
+type MediaInfo struct {
+	URI string `json:"uri"`
+	// Result is the nick of the discoverer result, e.g. "ok" or "missing-plugins".
+	Result          string         `json:"result"`
+	Duration        time.Duration  `json:"duration"`
+	Seekable        bool           `json:"seekable"`
+	Live            bool           `json:"live"`
+	Tags            map[string]any `json:"tags,omitempty"`
+	TOC             []TOCEntry     `json:"toc,omitempty"`
+	MissingElements []string       `json:"missing_elements,omitempty"`
+	Containers      []StreamInfo   `json:"containers,omitempty"`
+	Audio           []StreamInfo   `json:"audio,omitempty"`
+	Video           []StreamInfo   `json:"video,omitempty"`
+	Subtitles       []StreamInfo   `json:"subtitles,omitempty"`
+	// Other contains the streams that are neither containers nor audio, video or subtitles.
+	Other []StreamInfo `json:"other,omitempty"`
+}
+
+// StreamInfo describes a single stream of a [MediaInfo]. Exactly one of Audio, Video and
+// Subtitle is set for streams of these kinds.
+type StreamInfo struct {
+	// Type is the stream type nick, e.g. "container", "audio" or "video".
+	Type         string `json:"type"`
+	StreamID     string `json:"stream_id,omitempty"`
+	StreamNumber int    `json:"stream_number"`
+	Caps         string `json:"caps,omitempty"`
+	// MediaType is the name of the first structure of the caps, e.g. "video/x-h264".
+	MediaType string `json:"media_type,omitempty"`
+	// CapsFields are the fields of the first structure of the caps. Values that have no plain Go
+	// representation, like fractions and ranges, are serialized to strings.
+	CapsFields map[string]any `json:"caps_fields,omitempty"`
+	Tags       map[string]any `json:"tags,omitempty"`
+	TOC        []TOCEntry     `json:"toc,omitempty"`
+	Audio      *AudioInfo     `json:"audio,omitempty"`
+	Video      *VideoInfo     `json:"video,omitempty"`
+	Subtitle   *SubtitleInfo  `json:"subtitle,omitempty"`
+}
+
+// AudioInfo are the audio specific fields of a [StreamInfo].
+type AudioInfo struct {
+	Channels    uint   `json:"channels"`
+	ChannelMask uint64 `json:"channel_mask,omitempty"`
+	SampleRate  uint   `json:"sample_rate"`
+	Depth       uint   `json:"depth,omitempty"`
+	Bitrate     uint   `json:"bitrate,omitempty"`
+	MaxBitrate  uint   `json:"max_bitrate,omitempty"`
+	Language    string `json:"language,omitempty"`
+}
+
+// VideoInfo are the video specific fields of a [StreamInfo].
+type VideoInfo struct {
+	Width          uint `json:"width"`
+	Height         uint `json:"height"`
+	Depth          uint `json:"depth,omitempty"`
+	FramerateNum   uint `json:"framerate_num"`
+	FramerateDenom uint `json:"framerate_denom"`
+	ParNum         uint `json:"par_num"`
+	ParDenom       uint `json:"par_denom"`
+	Interlaced     bool `json:"interlaced"`
+	Image          bool `json:"image"`
+	Bitrate        uint `json:"bitrate,omitempty"`
+	MaxBitrate     uint `json:"max_bitrate,omitempty"`
+}
+
+// SubtitleInfo are the subtitle specific fields of a [StreamInfo].
+type SubtitleInfo struct {
+	Language string `json:"language,omitempty"`
+}
+
+// TOCEntry is an entry of the table of contents of a [MediaInfo] or [StreamInfo].
+type TOCEntry struct {
+	UID string `json:"uid"`
+	// Type is the nick of the entry type, e.g. "chapter" or "edition".
+	Type string `json:"type"`
+	// Start and Stop are -1 if unknown.
+	Start   time.Duration  `json:"start"`
+	Stop    time.Duration  `json:"stop"`
+	Tags    map[string]any `json:"tags,omitempty"`
+	Entries []TOCEntry     `json:"entries,omitempty"`
+}
+
+// NewMediaInfo converts the discoverer result into a [MediaInfo].
+func NewMediaInfo(info DiscovererInfo) *MediaInfo {
+	m := &MediaInfo{
+		URI:             info.GetURI(),
+		Result:          discovererResultNick(info.GetResult()),
+		Duration:        clockTimeDuration(info.GetDuration()),
+		Seekable:        info.GetSeekable(),
+		Live:            info.GetLive(),
+		TOC:             tocEntries(info.GetToc()),
+		MissingElements: info.GetMissingElementsInstallerDetails(),
+	}
+
+	// the global tags are the tags of the top level stream, usually the container
+	if top := info.GetStreamInfo(); top != nil {
+		m.Tags = tagListMap(top.GetTags())
+	}
+
+	for _, s := range info.GetStreamList() {
+		if _, ok := s.(DiscovererContainerInfo); ok {
+			continue
+		}
+
+		stream := newStreamInfo(s)
+
+		switch {
+		case stream.Audio != nil:
+			m.Audio = append(m.Audio, stream)
+		case stream.Video != nil:
+			m.Video = append(m.Video, stream)
+		case stream.Subtitle != nil:
+			m.Subtitles = append(m.Subtitles, stream)
+		default:
+			m.Other = append(m.Other, stream)
+		}
+	}
+
+	for _, s := range info.GetContainerStreams() {
+		m.Containers = append(m.Containers, newStreamInfo(s))
+	}
+
+	return m
+}
+
+func newStreamInfo(info DiscovererStreamInfo) StreamInfo {
+	s := StreamInfo{
+		Type:         info.GetStreamTypeNick(),
+		StreamID:     info.GetStreamID(),
+		StreamNumber: int(info.GetStreamNumber()),
+		Tags:         tagListMap(info.GetTags()),
+		TOC:          tocEntries(info.GetToc()),
+	}
+
+	if caps := info.GetCaps(); caps != nil {
+		s.Caps = caps.String()
+
+		if caps.GetSize() > 0 {
+			structure := caps.GetStructure(0)
+			s.MediaType = structure.GetName()
+			s.CapsFields = structureFields(structure)
+		}
+	}
+
+	switch info := info.(type) {
+	case DiscovererAudioInfo:
+		s.Audio = &AudioInfo{
+			Channels:    info.GetChannels(),
+			ChannelMask: info.GetChannelMask(),
+			SampleRate:  info.GetSampleRate(),
+			Depth:       info.GetDepth(),
+			Bitrate:     info.GetBitrate(),
+			MaxBitrate:  info.GetMaxBitrate(),
+			Language:    info.GetLanguage(),
+		}
+	case DiscovererVideoInfo:
+		s.Video = &VideoInfo{
+			Width:          info.GetWidth(),
+			Height:         info.GetHeight(),
+			Depth:          info.GetDepth(),
+			FramerateNum:   info.GetFramerateNum(),
+			FramerateDenom: info.GetFramerateDenom(),
+			ParNum:         info.GetParNum(),
+			ParDenom:       info.GetParDenom(),
+			Interlaced:     info.IsInterlaced(),
+			Image:          info.IsImage(),
+			Bitrate:        info.GetBitrate(),
+			MaxBitrate:     info.GetMaxBitrate(),
+		}
+	case DiscovererSubtitleInfo:
+		s.Subtitle = &SubtitleInfo{Language: info.GetLanguage()}
+	}
+
+	return s
+}
+
+func tocEntries(toc *gst.Toc) []TOCEntry {
+	if toc == nil {
+		return nil
+	}
+
+	return convertTOCEntries(toc.GetEntries())
+}
+
+func convertTOCEntries(entries []*gst.TocEntry) []TOCEntry {
+	var converted []TOCEntry
+
+	for _, e := range entries {
+		entry := TOCEntry{
+			UID:     e.GetUid(),
+			Type:    gst.TocEntryTypeGetNick(e.GetEntryType()),
+			Start:   -1,
+			Stop:    -1,
+			Tags:    tagListMap(e.GetTags()),
+			Entries: convertTOCEntries(e.GetSubEntries()),
+		}
+
+		if start, stop, ok := e.GetStartStopTimes(); ok {
+			entry.Start = clockTimeDuration(gst.ClockTime(start))
+			entry.Stop = clockTimeDuration(gst.ClockTime(stop))
+		}
+
+		converted = append(converted, entry)
+	}
+
+	return converted
+}
+
+// tagListMap converts the tags into a map, tags with multiple values are stored as slice.
+// Values without a plain representation, like samples, are skipped.
+func tagListMap(tags *gst.TagList) map[string]any {
+	if tags == nil || tags.IsEmpty() {
+		return nil
+	}
+
+	m := make(map[string]any)
+
+	for i := range uint(tags.NTags()) {
+		tag := tags.NthTagName(i)
+
+		var values []any
+
+		for j := range tags.GetTagSize(tag) {
+			if v, ok := plainValue(tags.GetValueIndex(tag, j)); ok {
+				values = append(values, v)
+			}
+		}
+
+		switch len(values) {
+		case 0:
+		case 1:
+			m[tag] = values[0]
+		default:
+			m[tag] = values
+		}
+	}
+
+	return m
+}
+
+// plainValue returns v if it can be marshalled to JSON as is, or its string representation
+func plainValue(v any) (any, bool) {
+	if v == gobject.InvalidValue {
+		return nil, false
+	}
+
+	switch v := v.(type) {
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, true
+	case *gst.DateTime:
+		return v.ToIso8601String(), true
+	case fmt.Stringer:
+		return v.String(), true
+	default:
+		return nil, false
+	}
+}
+
+// structureFields converts the fields of the structure into a map
+func structureFields(structure *gst.Structure) map[string]any {
+	var carg0 *C.GstStructure // in, none, converted
+
+	carg0 = (*C.GstStructure)(gst.UnsafeStructureToGlibNone(structure))
+	defer runtime.KeepAlive(structure)
+
+	n := int(C.gst_structure_n_fields(carg0))
+	if n == 0 {
+		return nil
+	}
+
+	fields := make(map[string]any, n)
+
+	for i := range n {
+		name := C.gst_structure_nth_field_name(carg0, C.guint(i))
+		value := C.gst_structure_get_value(carg0, name)
+
+		switch v := gobject.ValueFromNative(unsafe.Pointer(value)).GoValue().(type) {
+		case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			fields[C.GoString(name)] = v
+		default:
+			serialized := C.gst_value_serialize(value)
+			fields[C.GoString(name)] = C.GoString(serialized)
+			C.g_free(C.gpointer(serialized))
+		}
+	}
+
+	return fields
+}
+
+func discovererResultNick(r DiscovererResult) string {
+	switch r {
+	case DiscovererOK:
+		return "ok"
+	case DiscovererURIInvalid:
+		return "uri-invalid"
+	case DiscovererError:
+		return "error"
+	case DiscovererTimeout:
+		return "timeout"
+	case DiscovererBusy:
+		return "busy"
+	case DiscovererMissingPlugins:
+		return "missing-plugins"
+	default:
+		return "unknown"
+	}
+}
+
+func clockTimeDuration(t gst.ClockTime) time.Duration {
+	if t == gst.ClockTimeNone {
+		return -1
+	}
+
+	return time.Duration(t)
+}
+
+// jsonSeconds converts a duration to seconds, unknown durations are -1
+func jsonSeconds(d time.Duration) float64 {
+	if d < 0 {
+		return -1
+	}
+
+	return d.Seconds()
+}
+
+// MarshalJSON implements [json.Marshaler], the duration is given in seconds.
+func (m MediaInfo) MarshalJSON() ([]byte, error) {
+	type plain MediaInfo
+
+	return json.Marshal(struct {
+		plain
+		Duration float64 `json:"duration"`
+	}{plain(m), jsonSeconds(m.Duration)})
+}
+
+// MarshalJSON implements [json.Marshaler], start and stop are given in seconds.
+func (e TOCEntry) MarshalJSON() ([]byte, error) {
+	type plain TOCEntry
+
+	return json.Marshal(struct {
+		plain
+		Start float64 `json:"start"`
+		Stop  float64 `json:"stop"`
+	}{plain(e), jsonSeconds(e.Start), jsonSeconds(e.Stop)})
+}
@@ -0,0 +1,70 @@
+package gstpbutils_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gstpbutils"
+)
+
+func TestMediaInfoMarshalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		info gstpbutils.MediaInfo
+		want string
+	}{
+		{
+			name: "known",
+			info: gstpbutils.MediaInfo{
+				URI:      "file:///a.ogg",
+				Result:   "ok",
+				Duration: 1500 * time.Millisecond,
+				TOC: []gstpbutils.TOCEntry{{
+					UID:   "edition",
+					Type:  "edition",
+					Start: 0,
+					Stop:  90 * time.Second,
+					Entries: []gstpbutils.TOCEntry{
+						{UID: "chapter", Type: "chapter", Start: 250 * time.Millisecond, Stop: 90 * time.Second},
+					},
+				}},
+			},
+			want: `{"uri":"file:///a.ogg","result":"ok","duration":1.5,"seekable":false,"live":false,
+				"toc":[{"uid":"edition","type":"edition","start":0,"stop":90,
+					"entries":[{"uid":"chapter","type":"chapter","start":0.25,"stop":90}]}]}`,
+		},
+		{
+			name: "unknown",
+			info: gstpbutils.MediaInfo{
+				Result:   "ok",
+				Duration: -1,
+				TOC:      []gstpbutils.TOCEntry{{UID: "chapter", Type: "chapter", Start: -1, Stop: -1}},
+			},
+			want: `{"uri":"","result":"ok","duration":-1,"seekable":false,"live":false,
+				"toc":[{"uid":"chapter","type":"chapter","start":-1,"stop":-1}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.info)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got, want any
+
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("unexpected json %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package gstpbutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+var (
+	// ErrNoSink is returned by [Transcode] if no sink element handles the destination URI.
+	ErrNoSink = errors.New("no sink for uri")
+	// ErrTranscodeFailed is returned by [Transcode] if the pipeline posted an error, the error of
+	// the message is wrapped as well.
+	ErrTranscodeFailed = errors.New("transcoding failed")
+)
+
+// transcodeProgressInterval is the interval in which the position of the pipeline is queried
+const transcodeProgressInterval = 200 * time.Millisecond
+
+// TranscodeProgress is called by [Transcode] with the current position and the duration of the
+// source, duration is -1 if it is unknown.
+type TranscodeProgress func(position, duration time.Duration)
+
+// Transcode decodes srcURI with uridecodebin, encodes it with encodebin according to profile and
+// writes it to dstURI. progress is called periodically and once more after the end of the
+// stream, it may be nil. Streams of the source that are not covered by the profile are dropped.
+// Transcode blocks until the transcoding is done, failed or ctx is done, in which case ctx.Err()
+// is returned.
+func Transcode(ctx context.Context, srcURI, dstURI string, profile EncodingProfile, progress TranscodeProgress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	decodebin := gst.ElementFactoryMake("uridecodebin", "")
+	encodebin := gst.ElementFactoryMake("encodebin", "")
+
+	if decodebin == nil || encodebin == nil {
+		return fmt.Errorf("%w: uridecodebin and encodebin are required", ErrMissingPlugins)
+	}
+
+	sink, err := gst.ElementMakeFromURI(gst.URISink, dstURI, "")
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrNoSink, dstURI, err)
+	}
+
+	decodebin.SetObjectProperty("uri", srcURI)
+	encodebin.SetObjectProperty("profile", profile)
+
+	pipeline := gst.NewPipeline("").(gst.Pipeline)
+	pipeline.AddMany(decodebin, encodebin, sink)
+
+	if !encodebin.Link(sink) {
+		return fmt.Errorf("%w: could not link encodebin to %s", ErrTranscodeFailed, sink.GetName())
+	}
+
+	decodebin.ConnectPadAdded(func(_ gst.Element, pad gst.Pad) {
+		linkTranscodeStream(pipeline, encodebin, pad)
+	})
+
+	defer pipeline.SetState(gst.StateNull)
+
+	bus := pipeline.GetBus()
+
+	if pipeline.SetState(gst.StatePlaying) == gst.StateChangeFailure {
+		if msg := bus.TimedPopFiltered(0, gst.MessageError); msg != nil {
+			return transcodeError(msg)
+		}
+
+		return fmt.Errorf("%w: could not start the pipeline", ErrTranscodeFailed)
+	}
+
+	duration := time.Duration(-1)
+
+	report := func() {
+		if progress == nil {
+			return
+		}
+
+		if duration < 0 {
+			if d, ok := pipeline.QueryDuration(gst.FormatTime); ok && d >= 0 {
+				duration = time.Duration(d)
+			}
+		}
+
+		if position, ok := pipeline.QueryPosition(gst.FormatTime); ok && position >= 0 {
+			progress(time.Duration(position), duration)
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		msg := bus.TimedPopFiltered(gst.ClockTime(transcodeProgressInterval), gst.MessageEOS|gst.MessageError)
+		if msg == nil {
+			report()
+
+			continue
+		}
+
+		if msg.Type() == gst.MessageError {
+			return transcodeError(msg)
+		}
+
+		report()
+
+		return nil
+	}
+}
+
+// linkTranscodeStream links a decoded pad to a new pad of encodebin, or drops the stream if the
+// profile has no matching stream profile
+func linkTranscodeStream(pipeline gst.Pipeline, encodebin gst.Element, pad gst.Pad) {
+	caps := pad.GetCurrentCaps()
+	if caps == nil {
+		caps = pad.QueryCaps(nil)
+	}
+
+	if sinkpad, ok := encodebin.Emit("request-pad", caps).(gst.Pad); ok && sinkpad != nil {
+		if pad.Link(sinkpad) == gst.PadLinkOK {
+			return
+		}
+
+		encodebin.ReleaseRequestPad(sinkpad)
+	}
+
+	fakesink := gst.ElementFactoryMake("fakesink", "")
+	fakesink.SetObjectProperty("sync", false)
+	fakesink.SetObjectProperty("async", false)
+
+	pipeline.Add(fakesink)
+	fakesink.SyncStateWithParent()
+
+	pad.Link(fakesink.GetStaticPad("sink"))
+}
+
+func transcodeError(msg *gst.Message) error {
+	debug, err := msg.ParseError()
+
+	source := "pipeline"
+	if src := msg.Source(); src != nil {
+		source = src.GetName()
+	}
+
+	if debug != "" {
+		return fmt.Errorf("%w: %s: %w (%s)", ErrTranscodeFailed, source, err, debug)
+	}
+
+	return fmt.Errorf("%w: %s: %w", ErrTranscodeFailed, source, err)
+}
@@ -0,0 +1,101 @@
+package gstplay
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+const (
+	// DefaultPositionUpdateInterval is the default interval of [PlayPositionUpdatedEvent]s.
+	DefaultPositionUpdateInterval = 100 * time.Millisecond
+	// MaxPositionUpdateInterval is the largest supported position update interval.
+	MaxPositionUpdateInterval = 10 * time.Second
+)
+
+var (
+	// ErrInvalidPlayConfig is returned if a [PlayConfig] contains invalid values.
+	ErrInvalidPlayConfig = errors.New("invalid play config")
+	// ErrPlayConfigNotApplied is returned if the play rejected the config, this happens if it
+	// is not stopped.
+	ErrPlayConfigNotApplied = errors.New("play config not applied, the play is not stopped")
+)
+
+// PlayConfig is the configuration of a [Play], it mirrors the gst_play_config_* helpers that
+// operate on the config structure of [PlayInstance.GetConfig].
+type PlayConfig struct {
+	// UserAgent is used for HTTP requests, empty uses the default user agent of the source
+	// element.
+	UserAgent string
+	// PositionUpdateInterval is the interval of [PlayPositionUpdatedEvent]s with millisecond
+	// precision, 0 disables the updates.
+	PositionUpdateInterval time.Duration
+	// SeekAccurate enables accurate seeking, which is slower than seeking to the nearest key
+	// frame.
+	SeekAccurate bool
+	// PipelineDumpInErrorDetails adds a dot dump of the pipeline to the details of
+	// [PlayErrorEvent]s.
+	PipelineDumpInErrorDetails bool
+}
+
+// DefaultPlayConfig returns the config a new [Play] starts with.
+func DefaultPlayConfig() PlayConfig {
+	return PlayConfig{
+		PositionUpdateInterval: DefaultPositionUpdateInterval,
+	}
+}
+
+// Validate returns an error wrapping [ErrInvalidPlayConfig] if the config can not be applied.
+func (c PlayConfig) Validate() error {
+	if c.PositionUpdateInterval < 0 || c.PositionUpdateInterval > MaxPositionUpdateInterval {
+		return fmt.Errorf("%w: position update interval %s is not between 0 and %s", ErrInvalidPlayConfig, c.PositionUpdateInterval, MaxPositionUpdateInterval)
+	}
+
+	return nil
+}
+
+// Load reads the current config of the play.
+func (c *PlayConfig) Load(play Play) {
+	c.loadStructure(play.GetConfig())
+}
+
+// Apply validates the config and sets it on the play. The play must be stopped, otherwise
+// [ErrPlayConfigNotApplied] is returned.
+func (c PlayConfig) Apply(play Play) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	// the config structure may contain fields without helpers, so they are kept
+	config := play.GetConfig()
+
+	c.applyStructure(config)
+
+	if !play.SetConfig(config) {
+		return ErrPlayConfigNotApplied
+	}
+
+	return nil
+}
+
+func (c *PlayConfig) loadStructure(config *gst.Structure) {
+	c.UserAgent = PlayConfigGetUserAgent(config)
+	c.PositionUpdateInterval = time.Duration(PlayConfigGetPositionUpdateInterval(config)) * time.Millisecond
+	c.SeekAccurate = PlayConfigGetSeekAccurate(config)
+	c.PipelineDumpInErrorDetails = PlayConfigGetPipelineDumpInErrorDetails(config)
+}
+
+func (c PlayConfig) applyStructure(config *gst.Structure) {
+	// gst_play_config_set_user_agent does not accept NULL to unset the user agent
+	if c.UserAgent != "" {
+		PlayConfigSetUserAgent(config, c.UserAgent)
+	} else {
+		config.RemoveField("user-agent")
+	}
+
+	PlayConfigSetPositionUpdateInterval(config, uint(c.PositionUpdateInterval/time.Millisecond))
+	PlayConfigSetSeekAccurate(config, c.SeekAccurate)
+	PlayConfigSetPipelineDumpInErrorDetails(config, c.PipelineDumpInErrorDetails)
+}
@@ -0,0 +1,73 @@
+package gstplay
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+func TestPlayConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		interval time.Duration
+		valid    bool
+	}{
+		{0, true},
+		{DefaultPositionUpdateInterval, true},
+		{MaxPositionUpdateInterval, true},
+		{-time.Millisecond, false},
+		{MaxPositionUpdateInterval + time.Millisecond, false},
+	} {
+		err := PlayConfig{PositionUpdateInterval: tc.interval}.Validate()
+
+		if tc.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.interval, err)
+		}
+
+		if !tc.valid && !errors.Is(err, ErrInvalidPlayConfig) {
+			t.Errorf("%s: expected ErrInvalidPlayConfig, got %v", tc.interval, err)
+		}
+	}
+}
+
+func TestPlayConfigStructure(t *testing.T) {
+	gst.Init()
+
+	config := gst.NewStructureEmpty("play-config")
+	config.SetValue("custom", "kept")
+
+	want := PlayConfig{
+		UserAgent:                  "go-gst",
+		PositionUpdateInterval:     250 * time.Millisecond,
+		SeekAccurate:               true,
+		PipelineDumpInErrorDetails: true,
+	}
+
+	want.applyStructure(config)
+
+	var got PlayConfig
+	got.loadStructure(config)
+
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// an empty user agent removes the field instead of setting an empty string
+	want.UserAgent = ""
+	want.applyStructure(config)
+
+	if config.HasField("user-agent") {
+		t.Errorf("the user agent was not removed from %s", config.String())
+	}
+
+	if !config.HasField("custom") {
+		t.Errorf("unrelated fields must be kept in %s", config.String())
+	}
+
+	got.loadStructure(config)
+
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
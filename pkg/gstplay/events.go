@@ -0,0 +1,142 @@
+package gstplay
+
+import (
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// PlayEvent is a decoded message of the play message bus, see [PlayInstance.Events] and
+// [ParsePlayEvent]. It is one of the *Event types of this package.
+type PlayEvent interface {
+	playEvent()
+}
+
+type (
+	// PlayURILoadedEvent is sent when the uri of the play was loaded.
+	PlayURILoadedEvent struct {
+		URI string
+	}
+
+	// PlayStateChangedEvent is sent when the state of the play changed.
+	PlayStateChangedEvent struct {
+		State PlayState
+	}
+
+	// PlayPositionUpdatedEvent is sent periodically while playing, see
+	// [PlayConfigSetPositionUpdateInterval].
+	PlayPositionUpdatedEvent struct {
+		Position time.Duration
+	}
+
+	// PlayDurationChangedEvent is sent when the duration of the media changed. The duration is
+	// -1 if it is unknown.
+	PlayDurationChangedEvent struct {
+		Duration time.Duration
+	}
+
+	// PlayBufferingEvent is sent while buffering with the buffering level in percent.
+	PlayBufferingEvent struct {
+		Percent uint
+	}
+
+	// PlayEndOfStreamEvent is sent when the end of the media was reached.
+	PlayEndOfStreamEvent struct{}
+
+	// PlayErrorEvent is sent when an error occurred, playback is stopped afterwards.
+	PlayErrorEvent struct {
+		Err error
+		// Details contains additional information about the error, it may be nil.
+		Details *gst.Structure
+	}
+
+	// PlayWarningEvent is sent when a warning occurred.
+	PlayWarningEvent struct {
+		Err error
+		// Details contains additional information about the warning, it may be nil.
+		Details *gst.Structure
+	}
+
+	// PlayVideoDimensionsChangedEvent is sent when the size of the video changed. Both are 0 if
+	// there is no video.
+	PlayVideoDimensionsChangedEvent struct {
+		Width, Height uint
+	}
+
+	// PlayMediaInfoUpdatedEvent is sent when the media info changed, e.g. when the streams are
+	// known.
+	PlayMediaInfoUpdatedEvent struct {
+		Info PlayMediaInfo
+	}
+
+	// PlayVolumeChangedEvent is sent when the volume changed.
+	PlayVolumeChangedEvent struct {
+		Volume float64
+	}
+
+	// PlayMuteChangedEvent is sent when the audio was muted or unmuted.
+	PlayMuteChangedEvent struct {
+		Muted bool
+	}
+
+	// PlaySeekDoneEvent is sent when a seek finished.
+	PlaySeekDoneEvent struct {
+		Position time.Duration
+	}
+)
+
+func (PlayURILoadedEvent) playEvent()              {}
+func (PlayStateChangedEvent) playEvent()           {}
+func (PlayPositionUpdatedEvent) playEvent()        {}
+func (PlayDurationChangedEvent) playEvent()        {}
+func (PlayBufferingEvent) playEvent()              {}
+func (PlayEndOfStreamEvent) playEvent()            {}
+func (PlayErrorEvent) playEvent()                  {}
+func (PlayWarningEvent) playEvent()                {}
+func (PlayVideoDimensionsChangedEvent) playEvent() {}
+func (PlayMediaInfoUpdatedEvent) playEvent()       {}
+func (PlayVolumeChangedEvent) playEvent()          {}
+func (PlayMuteChangedEvent) playEvent()            {}
+func (PlaySeekDoneEvent) playEvent()               {}
+
+// ParsePlayEvent decodes a message of the play message bus. It returns nil if the message is
+// not a play message.
+func ParsePlayEvent(msg *gst.Message) PlayEvent {
+	if !PlayIsPlayMessage(msg) {
+		return nil
+	}
+
+	switch PlayMessageParseType(msg) {
+	case PlayMessageURILoaded:
+		return PlayURILoadedEvent{URI: PlayMessageParseURILoaded(msg)}
+	case PlayMessagePositionUpdated:
+		return PlayPositionUpdatedEvent{Position: clockTimeDuration(PlayMessageParsePositionUpdated(msg))}
+	case PlayMessageDurationChanged:
+		return PlayDurationChangedEvent{Duration: clockTimeDuration(PlayMessageParseDurationChanged(msg))}
+	case PlayMessageStateChanged:
+		return PlayStateChangedEvent{State: PlayMessageParseStateChanged(msg)}
+	case PlayMessageBuffering:
+		return PlayBufferingEvent{Percent: PlayMessageParseBuffering(msg)}
+	case PlayMessageEndOfStream:
+		return PlayEndOfStreamEvent{}
+	case PlayMessageError:
+		details, err := PlayMessageParseError(msg)
+		return PlayErrorEvent{Err: err, Details: details}
+	case PlayMessageWarning:
+		details, err := PlayMessageParseWarning(msg)
+		return PlayWarningEvent{Err: err, Details: details}
+	case PlayMessageVideoDimensionsChanged:
+		width, height := PlayMessageParseVideoDimensionsChanged(msg)
+		return PlayVideoDimensionsChangedEvent{Width: width, Height: height}
+	case PlayMessageMediaInfoUpdated:
+		return PlayMediaInfoUpdatedEvent{Info: PlayMessageParseMediaInfoUpdated(msg)}
+	case PlayMessageVolumeChanged:
+		return PlayVolumeChangedEvent{Volume: PlayMessageParseVolumeChanged(msg)}
+	case PlayMessageMuteChanged:
+		return PlayMuteChangedEvent{Muted: PlayMessageParseMutedChanged(msg)}
+	case PlayMessageSeekDone:
+		return PlaySeekDoneEvent{Position: clockTimeDuration(PlayMessageParseSeekDone(msg))}
+	default:
+		return nil
+	}
+}
@@ -783,6 +783,7 @@ var _ Play = (*PlayInstance)(nil)
 // 
 // see also https://gstreamer.freedesktop.org/documentation/play/gstplay-types.html#GstPlay
 type Play interface {
+	PlayExtManual // handwritten functions
 	gst.Object
 	upcastToGstPlay() *PlayInstance
 
@@ -0,0 +1,50 @@
+package gstplay
+
+import (
+	"context"
+	"image"
+	"iter"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+type PlayExtManual interface {
+	// Events returns the messages of the play message bus as typed [PlayEvent]s, until ctx is
+	// done or the iteration is stopped. It installs a sync handler on the message bus, so no
+	// main loop is needed, but it can not be combined with a [PlaySignalAdapter] or another
+	// call to Events at the same time.
+	Events(ctx context.Context) iter.Seq[PlayEvent]
+
+	// Snapshot returns the current video frame in the given format as an image. Raw frames are
+	// converted to an *image.RGBA, jpeg and png frames are decoded. [PlayThumbnailRawNative] is
+	// only supported for packed RGB formats.
+	Snapshot(format PlaySnapshotFormat) (image.Image, error)
+}
+
+// Events returns the messages of the play message bus as typed [PlayEvent]s, until ctx is
+// done or the iteration is stopped. It installs a sync handler on the message bus, so no
+// main loop is needed, but it can not be combined with a [PlaySignalAdapter] or another
+// call to Events at the same time.
+func (play *PlayInstance) Events(ctx context.Context) iter.Seq[PlayEvent] {
+	return func(yield func(PlayEvent) bool) {
+		for msg := range play.GetMessageBus().Messages(ctx) {
+			event := ParsePlayEvent(msg)
+			if event == nil {
+				continue
+			}
+
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func clockTimeDuration(t gst.ClockTime) time.Duration {
+	if t == gst.ClockTimeNone {
+		return -1
+	}
+
+	return time.Duration(t)
+}
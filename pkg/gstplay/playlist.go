@@ -0,0 +1,547 @@
+package gstplay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+var (
+	// ErrPlaylistEmpty is returned if a [Playlist] has no items to play.
+	ErrPlaylistEmpty = errors.New("playlist is empty")
+	// ErrPlaylistEnd is returned by [Playlist.Next] and [Playlist.Previous] if there is no
+	// item in that direction and repeat is disabled.
+	ErrPlaylistEnd = errors.New("end of playlist")
+	// ErrPlaylistIndex is returned by [Playlist.Jump] for an index out of range.
+	ErrPlaylistIndex = errors.New("playlist index out of range")
+)
+
+// playlistNotifyName is the name of the application message that wakes up
+// [Playlist.Events] to deliver the queued playlist events
+const playlistNotifyName = "go-gst-playlist-notify"
+
+// PlaylistItem is an entry of a [Playlist].
+type PlaylistItem struct {
+	URI string
+	// SubtitleURI is an optional external subtitle file.
+	SubtitleURI string
+	// Start is the position the playback of the item starts at, 0 starts at the beginning.
+	Start time.Duration
+	// Stop is the position the playlist advances to the next item at, 0 plays the item until
+	// the end.
+	Stop time.Duration
+}
+
+// PlaylistRepeat is the repeat mode of a [Playlist].
+type PlaylistRepeat int
+
+const (
+	// PlaylistRepeatNone stops after the last item.
+	PlaylistRepeatNone PlaylistRepeat = iota
+	// PlaylistRepeatOne repeats the current item until [Playlist.Next] is called.
+	PlaylistRepeatOne
+	// PlaylistRepeatAll starts over after the last item.
+	PlaylistRepeatAll
+)
+
+func (r PlaylistRepeat) String() string {
+	switch r {
+	case PlaylistRepeatNone:
+		return "none"
+	case PlaylistRepeatOne:
+		return "one"
+	case PlaylistRepeatAll:
+		return "all"
+	default:
+		return fmt.Sprintf("PlaylistRepeat(%d)", int(r))
+	}
+}
+
+type (
+	// PlaylistTrackChangedEvent is sent by [Playlist.Events] when the playlist started playing
+	// another item.
+	PlaylistTrackChangedEvent struct {
+		// Index is the index of the item in [Playlist.Items].
+		Index int
+		Item  PlaylistItem
+		// Gapless is true if the item was preloaded and played without stopping the pipeline.
+		Gapless bool
+	}
+
+	// PlaylistFinishedEvent is sent by [Playlist.Events] when the last item ended and repeat
+	// is disabled.
+	PlaylistFinishedEvent struct{}
+)
+
+func (PlaylistTrackChangedEvent) playEvent() {}
+func (PlaylistFinishedEvent) playEvent()     {}
+
+// Playlist plays a queue of URIs on a [Play]. It advances to the next item at the end of
+// stream or at the stop offset of an item, supports shuffle and repeat and seeks to the start
+// offsets of the items.
+//
+// When the pipeline of the play signals about-to-finish, the next item is preloaded into the
+// pipeline so it plays without a gap. This is skipped if the current item has a stop offset or
+// the next item has a start offset, those items are loaded with [PlayInstance.SetURI] instead.
+// The switch to the preloaded item is detected by the stream-start message of the pipeline.
+//
+// The preloaded item is set on the pipeline directly, because [PlayInstance.SetURI] would stop
+// the playback. After a gapless switch [PlayInstance.GetURI] and [PlayInstance.GetSubtitleURI]
+// still return the previous item and the media info of the play may describe it as well, use
+// [Playlist.Current] and [PlaylistTrackChangedEvent] instead.
+//
+// The playlist is driven by [Playlist.Events], which must be iterated while playing. Errors do
+// not advance the playlist, call [Playlist.Next] to skip a broken item.
+type Playlist struct {
+	play     Play
+	pipeline gst.Element
+	bus      gst.Bus
+
+	aboutToFinish gobject.SignalHandle
+	streamStart   gobject.SignalHandle
+
+	mu sync.Mutex
+
+	items   []PlaylistItem
+	order   []int // indices into items in play order
+	pos     int   // position in order, -1 if nothing was played yet
+	shuffle bool
+	repeat  PlaylistRepeat
+
+	// preloaded is the index in items of the item that was set on the pipeline on
+	// about-to-finish, -1 if none. It is an index and not a position in order, because the
+	// order changes with Add and SetShuffle while the pipeline keeps the preloaded uri.
+	preloaded   int
+	seekPending bool
+
+	pending []PlayEvent
+}
+
+// NewPlaylist creates an empty playlist for the play. [Playlist.Close] must be called when the
+// playlist is not used anymore.
+func NewPlaylist(play Play) *Playlist {
+	pipeline := play.GetPipeline()
+
+	p := &Playlist{
+		play:      play,
+		pipeline:  pipeline,
+		bus:       pipeline.GetBus(),
+		pos:       -1,
+		preloaded: -1,
+	}
+
+	p.aboutToFinish = p.pipeline.Connect("about-to-finish", func(gst.Element) {
+		p.preload()
+	})
+
+	// the play dispatches the messages of the pipeline bus as signals on its own thread
+	p.streamStart = p.bus.ConnectMessage(func(_ gst.Bus, msg *gst.Message) {
+		if msg.Type() == gst.MessageStreamStart {
+			p.streamStarted()
+		}
+	})
+
+	return p
+}
+
+// Close disconnects the playlist from the play, the play keeps playing the current item.
+func (p *Playlist) Close() {
+	p.pipeline.HandlerDisconnect(p.aboutToFinish)
+	p.bus.HandlerDisconnect(p.streamStart)
+}
+
+// Add appends the items to the playlist. In shuffle mode they are inserted at random
+// positions after the current item and after an item that is preloaded for gapless playback.
+func (p *Playlist) Add(items ...PlaylistItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	after := p.pos
+	if p.preloaded >= 0 {
+		after = max(after, p.preloadedPos())
+	}
+
+	for _, item := range items {
+		p.items = append(p.items, item)
+		index := len(p.items) - 1
+
+		if p.shuffle {
+			at := after + 1 + rand.IntN(len(p.order)-after)
+			p.order = slices.Insert(p.order, at, index)
+		} else {
+			p.order = append(p.order, index)
+		}
+	}
+}
+
+// Clear removes all items and stops the play.
+func (p *Playlist) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.items = nil
+	p.order = nil
+	p.pos = -1
+	p.preloaded = -1
+	p.seekPending = false
+
+	p.play.Stop()
+}
+
+// Items returns a copy of the items in the order they were added.
+func (p *Playlist) Items() []PlaylistItem {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.items)
+}
+
+// Current returns the index of the current item, false if nothing was played yet.
+func (p *Playlist) Current() (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pos < 0 {
+		return 0, false
+	}
+
+	return p.order[p.pos], true
+}
+
+// SetShuffle enables or disables the random play order. The current item stays current and an
+// item that is preloaded for gapless playback stays the next one.
+func (p *Playlist) SetShuffle(shuffle bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if shuffle == p.shuffle {
+		return
+	}
+
+	p.shuffle = shuffle
+
+	current := -1
+	if p.pos >= 0 {
+		current = p.order[p.pos]
+	}
+
+	if shuffle {
+		p.order = p.shuffledOrder(current)
+
+		// the pipeline switches to the preloaded item next
+		if p.preloaded >= 0 && p.preloaded != current {
+			i := slices.Index(p.order, p.preloaded)
+			p.order = slices.Insert(slices.Delete(p.order, i, i+1), 1, p.preloaded)
+		}
+	} else {
+		p.order = p.order[:0]
+		for i := range p.items {
+			p.order = append(p.order, i)
+		}
+	}
+
+	if current >= 0 {
+		p.pos = slices.Index(p.order, current)
+	}
+}
+
+// SetRepeat sets the repeat mode.
+func (p *Playlist) SetRepeat(repeat PlaylistRepeat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.repeat = repeat
+}
+
+// Play starts playing the current item, or the first item if nothing was played yet.
+func (p *Playlist) Play() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.order) == 0 {
+		return ErrPlaylistEmpty
+	}
+
+	if p.pos < 0 {
+		p.load(0)
+		return nil
+	}
+
+	p.play.Play()
+
+	return nil
+}
+
+// Next plays the next item. It returns [ErrPlaylistEnd] after the last item if repeat is
+// disabled.
+func (p *Playlist) Next() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.order) == 0 {
+		return ErrPlaylistEmpty
+	}
+
+	next, ok := p.next(false)
+	if !ok {
+		return ErrPlaylistEnd
+	}
+
+	p.load(next)
+
+	return nil
+}
+
+// Previous plays the previous item. It returns [ErrPlaylistEnd] at the first item if repeat is
+// disabled.
+func (p *Playlist) Previous() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.order) == 0 {
+		return ErrPlaylistEmpty
+	}
+
+	switch {
+	case p.pos > 0:
+		p.load(p.pos - 1)
+	case p.repeat != PlaylistRepeatNone:
+		p.load(len(p.order) - 1)
+	default:
+		return ErrPlaylistEnd
+	}
+
+	return nil
+}
+
+// Jump plays the item with the given index of [Playlist.Items].
+func (p *Playlist) Jump(index int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if index < 0 || index >= len(p.items) {
+		return fmt.Errorf("%w: %d", ErrPlaylistIndex, index)
+	}
+
+	p.load(slices.Index(p.order, index))
+
+	return nil
+}
+
+// Events returns the events of the play like [PlayInstance.Events], extended by
+// [PlaylistTrackChangedEvent] and [PlaylistFinishedEvent]. The playlist only advances while
+// the events are iterated.
+func (p *Playlist) Events(ctx context.Context) iter.Seq[PlayEvent] {
+	return func(yield func(PlayEvent) bool) {
+		for msg := range p.play.GetMessageBus().Messages(ctx) {
+			var events []PlayEvent
+
+			if s := msg.GetStructure(); msg.Type() == gst.MessageApplication && s != nil && s.HasName(playlistNotifyName) {
+				events = p.takePending()
+			} else if event := ParsePlayEvent(msg); event != nil {
+				p.handle(event)
+				events = []PlayEvent{event}
+			}
+
+			for _, event := range events {
+				if !yield(event) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle advances the playlist for events of the play
+func (p *Playlist) handle(event PlayEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pos < 0 {
+		return
+	}
+
+	switch event := event.(type) {
+	case PlayStateChangedEvent:
+		if p.seekPending && (event.State == PlayStatePlaying || event.State == PlayStatePaused) {
+			p.seekPending = false
+			p.play.Seek(gst.ClockTime(p.items[p.order[p.pos]].Start))
+		}
+	case PlayPositionUpdatedEvent:
+		if stop := p.items[p.order[p.pos]].Stop; stop > 0 && event.Position >= stop && !p.seekPending {
+			p.advance()
+		}
+	case PlayEndOfStreamEvent:
+		// the pipeline did not switch to the preloaded item, so it is loaded normally
+		if p.preloaded >= 0 {
+			p.load(p.preloadedPos())
+			return
+		}
+
+		p.advance()
+	}
+}
+
+// streamStarted switches to the preloaded item when the pipeline started playing it, it is
+// called on the thread of the play
+func (p *Playlist) streamStarted() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// streams of items loaded with SetURI start while nothing is preloaded
+	if p.pos < 0 || p.preloaded < 0 {
+		return
+	}
+
+	p.setPos(p.preloadedPos())
+	p.preloaded = -1
+	p.queue(p.trackChanged(true))
+}
+
+// preloadedPos returns the position of the preloaded item in order
+func (p *Playlist) preloadedPos() int {
+	// repeating the current item must not jump to another occurrence of it
+	if p.order[p.pos] == p.preloaded {
+		return p.pos
+	}
+
+	return slices.Index(p.order, p.preloaded)
+}
+
+// advance loads the next item at the end of the current item
+func (p *Playlist) advance() {
+	next, ok := p.next(true)
+	if !ok {
+		p.pos = len(p.order) - 1
+		p.preloaded = -1
+		p.play.Stop()
+		p.queue(PlaylistFinishedEvent{})
+
+		return
+	}
+
+	p.load(next)
+}
+
+// preload sets the next item on the pipeline, it is called on the streaming thread
+func (p *Playlist) preload() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pos < 0 || p.preloaded >= 0 {
+		return
+	}
+
+	current := p.items[p.order[p.pos]]
+	if current.Stop > 0 {
+		return
+	}
+
+	next, ok := p.next(true)
+	if !ok {
+		return
+	}
+
+	item := p.items[p.order[next]]
+
+	// the subtitle of the current item can not be removed from a running pipeline
+	if item.Start > 0 || (current.SubtitleURI != "" && item.SubtitleURI == "") {
+		return
+	}
+
+	p.pipeline.SetObjectProperty("uri", item.URI)
+	if item.SubtitleURI != "" {
+		p.pipeline.SetObjectProperty("suburi", item.SubtitleURI)
+	}
+
+	p.preloaded = p.order[next]
+}
+
+// next returns the position in order after the current one, auto is true when the current
+// item ended by itself. It wraps around to 0 if repeat is enabled.
+func (p *Playlist) next(auto bool) (int, bool) {
+	switch {
+	case p.pos < 0:
+		return 0, true
+	case auto && p.repeat == PlaylistRepeatOne:
+		return p.pos, true
+	case p.pos+1 < len(p.order):
+		return p.pos + 1, true
+	case p.repeat != PlaylistRepeatNone:
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// setPos makes pos the current position in order. Shuffled playback uses a new order when
+// it starts over, beginning with the item at pos.
+func (p *Playlist) setPos(pos int) {
+	if p.shuffle && pos == 0 && p.pos == len(p.order)-1 && p.pos > 0 {
+		p.order = p.shuffledOrder(p.order[0])
+	}
+
+	p.pos = pos
+}
+
+// load plays the item at the position in order
+func (p *Playlist) load(pos int) {
+	p.setPos(pos)
+	p.preloaded = -1
+
+	item := p.items[p.order[pos]]
+
+	p.play.SetURI(item.URI)
+	p.play.SetSubtitleURI(item.SubtitleURI)
+	p.seekPending = item.Start > 0
+	p.play.Play()
+
+	p.queue(p.trackChanged(false))
+}
+
+func (p *Playlist) trackChanged(gapless bool) PlaylistTrackChangedEvent {
+	index := p.order[p.pos]
+
+	return PlaylistTrackChangedEvent{
+		Index:   index,
+		Item:    p.items[index],
+		Gapless: gapless,
+	}
+}
+
+// shuffledOrder returns a random order of all items, starting with first if it is not -1
+func (p *Playlist) shuffledOrder(first int) []int {
+	order := rand.Perm(len(p.items))
+
+	if first >= 0 {
+		i := slices.Index(order, first)
+		order[0], order[i] = order[i], order[0]
+	}
+
+	return order
+}
+
+// queue adds an event for Events and wakes it up with an application message
+func (p *Playlist) queue(event PlayEvent) {
+	p.pending = append(p.pending, event)
+
+	p.play.GetMessageBus().Post(gst.NewMessageApplication(nil, gst.NewStructureEmpty(playlistNotifyName)))
+}
+
+func (p *Playlist) takePending() []PlayEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := p.pending
+	p.pending = nil
+
+	return events
+}
@@ -0,0 +1,77 @@
+package gstplay
+
+import (
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// playIface is embedded by fakePlay under another name, because fakePlay implements Play()
+type playIface = Play
+
+// fakePlay drives a real playbin without GstPlay, the methods of the embedded nil Play that
+// the playlist does not use panic
+type fakePlay struct {
+	playIface
+
+	pipeline gst.Element
+	bus      gst.Bus
+	uri      string
+}
+
+func (f *fakePlay) GetPipeline() gst.Element { return f.pipeline }
+func (f *fakePlay) GetMessageBus() gst.Bus   { return f.bus }
+func (f *fakePlay) SetURI(uri string)        { f.uri = uri }
+func (f *fakePlay) SetSubtitleURI(string)    {}
+func (f *fakePlay) Play()                    {}
+func (f *fakePlay) Stop()                    {}
+func (f *fakePlay) Seek(gst.ClockTime)       {}
+
+func TestPlaylistPreloadSurvivesReorder(t *testing.T) {
+	gst.Init()
+
+	pipeline := gst.ElementFactoryMake("playbin", "")
+	if pipeline == nil {
+		t.Skip("playbin is not available")
+	}
+
+	play := &fakePlay{pipeline: pipeline, bus: gst.NewBus()}
+
+	p := NewPlaylist(play)
+	defer p.Close()
+
+	p.Add(PlaylistItem{URI: "file:///0"}, PlaylistItem{URI: "file:///1"}, PlaylistItem{URI: "file:///2"})
+
+	if err := p.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.preload()
+
+	if uri := pipeline.ObjectProperty("uri"); uri != "file:///1" {
+		t.Fatalf("unexpected preloaded uri %v", uri)
+	}
+
+	// none of these may change the item the pipeline switches to
+	p.SetShuffle(true)
+	for range 10 {
+		p.Add(PlaylistItem{URI: "file:///added"})
+	}
+	p.SetRepeat(PlaylistRepeatOne)
+
+	p.takePending()
+	p.streamStarted()
+
+	if index, ok := p.Current(); !ok || index != 1 {
+		t.Fatalf("expected item 1 to be current, got %d", index)
+	}
+
+	events := p.takePending()
+	if len(events) != 1 || events[0] != (PlaylistTrackChangedEvent{Index: 1, Item: PlaylistItem{URI: "file:///1"}, Gapless: true}) {
+		t.Fatalf("unexpected events %v", events)
+	}
+
+	if p.pos != 1 || p.order[0] != 0 {
+		t.Fatalf("the added items must be played after the preloaded item, order %v at %d", p.order, p.pos)
+	}
+}
@@ -0,0 +1,69 @@
+package gstplay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstplay"
+)
+
+func TestPlaylist(t *testing.T) {
+	gst.Init()
+
+	// the testbin:// uri handler is provided by testsrcbin
+	if gst.ElementFactoryFind("testsrcbin") == nil {
+		t.Skip("testsrcbin is not available")
+	}
+
+	play := gstplay.NewPlay(nil)
+
+	pipeline := play.GetPipeline()
+	pipeline.SetObjectProperty("audio-sink", gst.ElementFactoryMake("fakesink", ""))
+	pipeline.SetObjectProperty("video-sink", gst.ElementFactoryMake("fakesink", ""))
+
+	playlist := gstplay.NewPlaylist(play)
+	defer playlist.Close()
+
+	if err := playlist.Play(); err != gstplay.ErrPlaylistEmpty {
+		t.Fatalf("expected ErrPlaylistEmpty, got %v", err)
+	}
+
+	playlist.Add(
+		gstplay.PlaylistItem{URI: "testbin://audio,num-buffers=20"},
+		gstplay.PlaylistItem{URI: "testbin://audio,num-buffers=20"},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := playlist.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []int
+
+	for event := range playlist.Events(ctx) {
+		switch event := event.(type) {
+		case gstplay.PlaylistTrackChangedEvent:
+			changes = append(changes, event.Index)
+		case gstplay.PlayErrorEvent:
+			t.Fatalf("playback error: %v", event.Err)
+		case gstplay.PlaylistFinishedEvent:
+			cancel()
+		}
+	}
+
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("the playlist did not finish, track changes: %v", changes)
+	}
+
+	if len(changes) != 2 || changes[0] != 0 || changes[1] != 1 {
+		t.Fatalf("unexpected track changes %v", changes)
+	}
+
+	if err := playlist.Next(); err != gstplay.ErrPlaylistEnd {
+		t.Fatalf("expected ErrPlaylistEnd, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package gstplay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstvideo"
+)
+
+var (
+	// ErrNoSnapshot is returned by [PlayInstance.Snapshot] if no video frame is available.
+	ErrNoSnapshot = errors.New("no video snapshot available")
+	// ErrUnsupportedSnapshotFormat is returned by [PlayInstance.Snapshot] if the frame can not
+	// be converted to an image.
+	ErrUnsupportedSnapshotFormat = errors.New("unsupported snapshot format")
+)
+
+// Snapshot returns the current video frame in the given format as an image. Raw frames are
+// converted to an *image.RGBA, jpeg and png frames are decoded. [PlayThumbnailRawNative] is
+// only supported for packed RGB formats.
+func (play *PlayInstance) Snapshot(format PlaySnapshotFormat) (image.Image, error) {
+	sample := play.GetVideoSnapshot(format, nil)
+	if sample == nil {
+		return nil, ErrNoSnapshot
+	}
+
+	buffer := sample.GetBuffer()
+	if buffer == nil {
+		return nil, ErrNoSnapshot
+	}
+
+	mapInfo, ok := buffer.Map(gst.MapRead)
+	if !ok {
+		return nil, fmt.Errorf("%w: could not map the buffer", ErrNoSnapshot)
+	}
+	defer mapInfo.Unmap()
+
+	data := mapInfo.Data()
+
+	switch format {
+	case PlayThumbnailJpg:
+		return jpeg.Decode(bytes.NewReader(data))
+	case PlayThumbnailPng:
+		return png.Decode(bytes.NewReader(data))
+	}
+
+	caps := sample.GetCaps()
+	if caps == nil {
+		return nil, fmt.Errorf("%w: the snapshot has no caps", ErrUnsupportedSnapshotFormat)
+	}
+
+	info := gstvideo.NewVideoInfoFromCaps(caps)
+	if info == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSnapshotFormat, caps.String())
+	}
+
+	return rawImage(data, info.GetFormat(), info.GetWidth(), info.GetHeight(), info.GetStride(0), info.GetOffset(0))
+}
+
+// rgbLayout is the position of the channels of a packed RGB format, a is -1 if there is no
+// alpha channel
+type rgbLayout struct {
+	r, g, b, a int
+	size       int
+}
+
+var rgbLayouts = map[gstvideo.VideoFormat]rgbLayout{
+	gstvideo.VideoFormatRgbx: {0, 1, 2, -1, 4},
+	gstvideo.VideoFormatBgrx: {2, 1, 0, -1, 4},
+	gstvideo.VideoFormatXrgb: {1, 2, 3, -1, 4},
+	gstvideo.VideoFormatXbgr: {3, 2, 1, -1, 4},
+	gstvideo.VideoFormatRgba: {0, 1, 2, 3, 4},
+	gstvideo.VideoFormatBgra: {2, 1, 0, 3, 4},
+	gstvideo.VideoFormatArgb: {1, 2, 3, 0, 4},
+	gstvideo.VideoFormatAbgr: {3, 2, 1, 0, 4},
+	gstvideo.VideoFormatRgb:  {0, 1, 2, -1, 3},
+	gstvideo.VideoFormatBgr:  {2, 1, 0, -1, 3},
+}
+
+// rawImage converts a frame of a packed RGB format to an RGBA image, alpha is premultiplied
+func rawImage(data []byte, format gstvideo.VideoFormat, width, height, stride, offset int) (*image.RGBA, error) {
+	layout, ok := rgbLayouts[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSnapshotFormat, format)
+	}
+
+	if width <= 0 || height <= 0 || stride < width*layout.size || len(data) < offset+(height-1)*stride+width*layout.size {
+		return nil, fmt.Errorf("%w: invalid %dx%d frame of %d bytes", ErrUnsupportedSnapshotFormat, width, height, len(data))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		row := data[offset+y*stride:]
+		out := img.Pix[y*img.Stride:]
+
+		for x := range width {
+			px := row[x*layout.size : (x+1)*layout.size]
+			r, g, b, a := uint32(px[layout.r]), uint32(px[layout.g]), uint32(px[layout.b]), uint32(0xff)
+
+			if layout.a >= 0 {
+				a = uint32(px[layout.a])
+				r, g, b = r*a/0xff, g*a/0xff, b*a/0xff
+			}
+
+			out[x*4+0] = uint8(r)
+			out[x*4+1] = uint8(g)
+			out[x*4+2] = uint8(b)
+			out[x*4+3] = uint8(a)
+		}
+	}
+
+	return img, nil
+}
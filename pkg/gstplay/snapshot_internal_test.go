@@ -0,0 +1,62 @@
+package gstplay
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gstvideo"
+)
+
+func TestRawImage(t *testing.T) {
+	const width, height, offset = 3, 2, 5
+
+	for format, layout := range rgbLayouts {
+		t.Run(format.String(), func(t *testing.T) {
+			// every row is padded, the padding must not be read as pixels
+			stride := width*layout.size + 3
+			data := bytes.Repeat([]byte{0xee}, offset+height*stride)
+
+			for y := range height {
+				for x := range width {
+					px := data[offset+y*stride+x*layout.size:]
+					px[layout.r], px[layout.g], px[layout.b] = byte(10*x), byte(100+10*y), 0xff
+
+					if layout.a >= 0 {
+						px[layout.a] = 0x80
+					} else if layout.size == 4 {
+						// the padding byte of the x formats is ignored
+						px[6-layout.r-layout.g-layout.b] = 0x12
+					}
+				}
+			}
+
+			img, err := rawImage(data, format, width, height, stride, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for y := range height {
+				for x := range width {
+					want := color.RGBA{uint8(10 * x), uint8(100 + 10*y), 0xff, 0xff}
+					if layout.a >= 0 {
+						want = color.RGBA{uint8(10 * x * 0x80 / 0xff), uint8((100 + 10*y) * 0x80 / 0xff), 0x80, 0x80}
+					}
+
+					if got := img.RGBAAt(x, y); got != want {
+						t.Errorf("pixel %d,%d: expected %v, got %v", x, y, want, got)
+					}
+				}
+			}
+
+			if _, err := rawImage(data[:len(data)-4], format, width, height, stride, offset); !errors.Is(err, ErrUnsupportedSnapshotFormat) {
+				t.Errorf("expected an error for a truncated frame, got %v", err)
+			}
+		})
+	}
+
+	if _, err := rawImage(make([]byte, 64), gstvideo.VideoFormatI420, 4, 4, 4, 0); !errors.Is(err, ErrUnsupportedSnapshotFormat) {
+		t.Errorf("expected an error for a planar format, got %v", err)
+	}
+}
@@ -0,0 +1,444 @@
+package gstrtp
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+//
+// extern gboolean _gogst_gstrtp1_RTPHeaderExtensionRead(GstRTPHeaderExtension*, GstRTPHeaderExtensionFlags, guint8*, gsize, GstBuffer*);
+// extern gssize _gogst_gstrtp1_RTPHeaderExtensionWrite(GstRTPHeaderExtension*, GstBuffer*, GstRTPHeaderExtensionFlags, GstBuffer*, guint8*, gsize);
+//
+// static gboolean _gogst_gstrtp1_rtp_header_extension_read(GstRTPHeaderExtension *ext, GstRTPHeaderExtensionFlags flags, const guint8 *data, gsize size, GstBuffer *buffer) {
+//   return _gogst_gstrtp1_RTPHeaderExtensionRead(ext, flags, (guint8 *) data, size, buffer);
+// }
+//
+// static gssize _gogst_gstrtp1_rtp_header_extension_write(GstRTPHeaderExtension *ext, const GstBuffer *input_meta, GstRTPHeaderExtensionFlags flags, GstBuffer *output, guint8 *data, gsize size) {
+//   return _gogst_gstrtp1_RTPHeaderExtensionWrite(ext, (GstBuffer *) input_meta, flags, output, data, size);
+// }
+//
+// static void _gogst_gstrtp1_rtp_header_extension_class_init(GstRTPHeaderExtensionClass *klass) {
+//   klass->read = _gogst_gstrtp1_rtp_header_extension_read;
+//   klass->write = _gogst_gstrtp1_rtp_header_extension_write;
+// }
+import "C"
+
+// URIs of the Go header extensions registered with [RegisterRTPHeaderExtensions].
+const (
+	RTPHeaderExtensionURIAbsSendTime      = "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"
+	RTPHeaderExtensionURITransportWideCC  = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+	RTPHeaderExtensionURIAudioLevel       = "urn:ietf:params:rtp-hdrext:ssrc-audio-level"
+	RTPHeaderExtensionURIVideoOrientation = "urn:3gpp:video-orientation"
+)
+
+// rtpHeaderExtensionKlass is GST_RTP_HDREXT_ELEMENT_CLASS, the element klass that is used by
+// gst_rtp_get_header_extension_list to find header extensions
+const rtpHeaderExtensionKlass = "Network/Extension/RTPHeader"
+
+// RTPHeaderExtensionCodec implements the wire format of a header extension registered with
+// [RegisterRTPHeaderExtension]. Every element instance gets its own codec. The buffers passed
+// to the methods are only valid during the call.
+type RTPHeaderExtensionCodec interface {
+	// MaxSize returns the maximum size of the data written for the input buffer.
+	MaxSize(inputMeta *gst.Buffer) int
+
+	// Write writes the extension data for the input buffer into data and returns the number of
+	// written bytes, 0 to not add the extension to this packet, or -1 on error.
+	Write(inputMeta *gst.Buffer, flags RTPHeaderExtensionFlags, output *gst.Buffer, data []byte) int
+
+	// Read parses the extension data of a received packet and applies it to the output buffer of
+	// the depayloader.
+	Read(flags RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool
+}
+
+// RTPHeaderExtensionAttributer can be implemented by an [RTPHeaderExtensionCodec] that supports
+// extmap attributes in the caps and the SDP.
+type RTPHeaderExtensionAttributer interface {
+	// SetAttributes is called with the attributes of the extmap.
+	SetAttributes(attributes string) bool
+
+	// Attributes returns the attributes that are written into the caps.
+	Attributes() string
+}
+
+// RTPHeaderExtensionInfo describes a header extension registered with [RegisterRTPHeaderExtension].
+type RTPHeaderExtensionInfo struct {
+	// URI identifies the extension in the extmap of the caps and the SDP.
+	URI string
+
+	// LongName, Description and Author are the element metadata. The klass is always
+	// "Network/Extension/RTPHeader", so the extension can be found by URI.
+	LongName    string
+	Description string
+	Author      string
+
+	// Flags are the supported header formats.
+	Flags RTPHeaderExtensionFlags
+}
+
+type goRTPHeaderExtension struct {
+	RTPHeaderExtensionInstance
+
+	info  RTPHeaderExtensionInfo
+	codec RTPHeaderExtensionCodec
+}
+
+// RegisterRTPHeaderExtension registers a new header extension type that reads and writes the
+// extension data with the codec created by newCodec.
+//
+// The returned type must be registered as an element with [gst.ElementRegister], so payloaders,
+// depayloaders, rtpbin and webrtcbin can create it from the URI with
+// [RTPHeaderExtensionCreateFromURI]. If multiple extensions have the same URI, then the one with
+// the highest rank is used.
+func RegisterRTPHeaderExtension(name string, info RTPHeaderExtensionInfo, newCodec func() RTPHeaderExtensionCodec) gobject.Type {
+	return RegisterRTPHeaderExtensionSubClass(
+		name,
+		func(class *RTPHeaderExtensionClass) {
+			class.ParentClass().SetStaticMetadata(info.LongName, rtpHeaderExtensionKlass, info.Description, info.Author)
+			class.SetURI(info.URI)
+
+			// read and write take byte arrays, so they are not overridable with the generated bindings
+			C._gogst_gstrtp1_rtp_header_extension_class_init((*C.GstRTPHeaderExtensionClass)(UnsafeRTPHeaderExtensionClassToGlibNone(class)))
+		},
+		func() *goRTPHeaderExtension {
+			return &goRTPHeaderExtension{
+				info:  info,
+				codec: newCodec(),
+			}
+		},
+		RTPHeaderExtensionOverrides[*goRTPHeaderExtension]{
+			GetMaxSize: func(ext *goRTPHeaderExtension, inputMeta *gst.Buffer) uint {
+				return uint(max(ext.codec.MaxSize(inputMeta), 0))
+			},
+			GetSupportedFlags: func(ext *goRTPHeaderExtension) RTPHeaderExtensionFlags {
+				return ext.info.Flags
+			},
+			SetAttributes: func(ext *goRTPHeaderExtension, _ RTPHeaderExtensionDirection, attributes string) bool {
+				a, ok := ext.codec.(RTPHeaderExtensionAttributer)
+				if !ok {
+					return attributes == ""
+				}
+
+				return a.SetAttributes(attributes)
+			},
+			SetCapsFromAttributes: func(ext *goRTPHeaderExtension, caps *gst.Caps) bool {
+				a, ok := ext.codec.(RTPHeaderExtensionAttributer)
+				if !ok {
+					return ext.SetCapsFromAttributesHelper(caps, "")
+				}
+
+				return ext.SetCapsFromAttributesHelper(caps, a.Attributes())
+			},
+		},
+		nil,
+	)
+}
+
+// builtin Go header extensions, the types can only be registered once
+var (
+	absSendTimeExtensionType = sync.OnceValue(func() gobject.Type {
+		return RegisterRTPHeaderExtension("GoRTPHeaderExtensionAbsSendTime", RTPHeaderExtensionInfo{
+			URI:         RTPHeaderExtensionURIAbsSendTime,
+			LongName:    "Absolute Send Time RTP Header Extension",
+			Description: "Writes the absolute send time of the packets",
+			Author:      "go-gst",
+			Flags:       RtpHeaderExtensionOneByte | RtpHeaderExtensionTwoByte,
+		}, func() RTPHeaderExtensionCodec {
+			return &absSendTimeCodec{now: time.Now}
+		})
+	})
+
+	twccExtensionType = sync.OnceValue(func() gobject.Type {
+		return RegisterRTPHeaderExtension("GoRTPHeaderExtensionTWCC", RTPHeaderExtensionInfo{
+			URI:         RTPHeaderExtensionURITransportWideCC,
+			LongName:    "Transport Wide Congestion Control RTP Header Extension",
+			Description: "Writes transport-wide sequence numbers",
+			Author:      "go-gst",
+			Flags:       RtpHeaderExtensionOneByte | RtpHeaderExtensionTwoByte,
+		}, func() RTPHeaderExtensionCodec {
+			return &twccCodec{}
+		})
+	})
+
+	audioLevelExtensionType = sync.OnceValue(func() gobject.Type {
+		return RegisterRTPHeaderExtension("GoRTPHeaderExtensionAudioLevel", RTPHeaderExtensionInfo{
+			URI:         RTPHeaderExtensionURIAudioLevel,
+			LongName:    "Client-to-Mixer Audio Level RTP Header Extension",
+			Description: "Writes the audio level stored with SetRTPAudioLevel",
+			Author:      "go-gst",
+			Flags:       RtpHeaderExtensionOneByte | RtpHeaderExtensionTwoByte,
+		}, func() RTPHeaderExtensionCodec {
+			return &audioLevelCodec{vad: true}
+		})
+	})
+
+	videoOrientationExtensionType = sync.OnceValue(func() gobject.Type {
+		return RegisterRTPHeaderExtension("GoRTPHeaderExtensionVideoOrientation", RTPHeaderExtensionInfo{
+			URI:         RTPHeaderExtensionURIVideoOrientation,
+			LongName:    "Video Orientation RTP Header Extension",
+			Description: "Writes the video orientation stored with SetRTPVideoOrientation",
+			Author:      "go-gst",
+			Flags:       RtpHeaderExtensionOneByte | RtpHeaderExtensionTwoByte,
+		}, func() RTPHeaderExtensionCodec {
+			return videoOrientationCodec{}
+		})
+	})
+)
+
+// RegisterRTPHeaderExtensions registers the Go implementations of the abs-send-time,
+// transport-wide-cc, audio level and video orientation header extensions as elements with the
+// given rank. GStreamer ships own implementations for some of the URIs with [gst.RankMarginal],
+// a higher rank prefers the Go implementations.
+//
+// The values are exchanged with the buffers through a custom meta, see [SetRTPAudioLevel],
+// [GetRTPAudioLevel], [SetRTPVideoOrientation], [GetRTPVideoOrientation], [GetRTPAbsSendTime]
+// and [GetRTPTransportWideSeqnum].
+func RegisterRTPHeaderExtensions(plugin gst.Plugin, rank gst.Rank) bool {
+	registerRTPHeaderExtensionMeta()
+
+	return gst.ElementRegister(plugin, "gortphdrextabssendtime", uint(rank), absSendTimeExtensionType()) &&
+		gst.ElementRegister(plugin, "gortphdrexttwcc", uint(rank), twccExtensionType()) &&
+		gst.ElementRegister(plugin, "gortphdrextaudiolevel", uint(rank), audioLevelExtensionType()) &&
+		gst.ElementRegister(plugin, "gortphdrextvideoorientation", uint(rank), videoOrientationExtensionType())
+}
+
+// RegisterRTPOpaqueHeaderExtension registers an element for a header extension with the given
+// URI that carries opaque bytes. Payloaders write the bytes stored with
+// [SetRTPHeaderExtensionBytes] on the input buffer, depayloaders store the received bytes on the
+// output buffer, where they can be read with [GetRTPHeaderExtensionBytes].
+func RegisterRTPOpaqueHeaderExtension(plugin gst.Plugin, name string, uri string, rank gst.Rank) bool {
+	registerRTPHeaderExtensionMeta()
+
+	typ := RegisterRTPHeaderExtension("GoRTPOpaqueHeaderExtension-"+name, RTPHeaderExtensionInfo{
+		URI:         uri,
+		LongName:    "Opaque RTP Header Extension",
+		Description: "Writes the bytes stored with SetRTPHeaderExtensionBytes",
+		Author:      "go-gst",
+		Flags:       RtpHeaderExtensionOneByte | RtpHeaderExtensionTwoByte,
+	}, func() RTPHeaderExtensionCodec {
+		return opaqueCodec{uri: uri}
+	})
+
+	return gst.ElementRegister(plugin, name, uint(rank), typ)
+}
+
+// ntpEpochOffset is the offset between the NTP epoch (1900) and the unix epoch (1970)
+const ntpEpochOffset = 2208988800 * time.Second
+
+// durationToAbsSendTime converts the time since the NTP epoch to the 6.18 fixed point format
+// of the abs-send-time extension
+func durationToAbsSendTime(d time.Duration) uint32 {
+	seconds := uint64(d/time.Second) & 0x3f
+	fraction := uint64(d%time.Second) << 18 / uint64(time.Second)
+
+	return uint32(seconds<<18 | fraction)
+}
+
+// absSendTimeToDuration converts the 6.18 fixed point format of the abs-send-time extension
+func absSendTimeToDuration(v uint32) time.Duration {
+	seconds := time.Duration(v>>18&0x3f) * time.Second
+	fraction := time.Duration(uint64(v&0x3ffff) * uint64(time.Second) >> 18)
+
+	return seconds + fraction
+}
+
+type absSendTimeCodec struct {
+	now func() time.Time
+}
+
+func (c *absSendTimeCodec) MaxSize(*gst.Buffer) int { return 3 }
+
+func (c *absSendTimeCodec) Write(_ *gst.Buffer, _ RTPHeaderExtensionFlags, _ *gst.Buffer, data []byte) int {
+	if len(data) < 3 {
+		return -1
+	}
+
+	v := durationToAbsSendTime(time.Duration(c.now().UnixNano()) + ntpEpochOffset)
+
+	data[0] = byte(v >> 16)
+	data[1] = byte(v >> 8)
+	data[2] = byte(v)
+
+	return 3
+}
+
+func (c *absSendTimeCodec) Read(_ RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool {
+	if len(data) < 3 {
+		return false
+	}
+
+	v := uint(data[0])<<16 | uint(data[1])<<8 | uint(data[2])
+
+	return setRTPHeaderExtensionUint(buffer, rtpMetaFieldAbsSendTime, v)
+}
+
+type twccCodec struct {
+	seqnum atomic.Uint32
+}
+
+func (c *twccCodec) MaxSize(*gst.Buffer) int { return 2 }
+
+func (c *twccCodec) Write(_ *gst.Buffer, _ RTPHeaderExtensionFlags, _ *gst.Buffer, data []byte) int {
+	if len(data) < 2 {
+		return -1
+	}
+
+	seqnum := uint16(c.seqnum.Add(1) - 1)
+
+	data[0] = byte(seqnum >> 8)
+	data[1] = byte(seqnum)
+
+	return 2
+}
+
+func (c *twccCodec) Read(_ RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool {
+	if len(data) < 2 {
+		return false
+	}
+
+	return setRTPHeaderExtensionUint(buffer, rtpMetaFieldTWCCSeqnum, uint(data[0])<<8|uint(data[1]))
+}
+
+type audioLevelCodec struct {
+	mu  sync.Mutex
+	vad bool
+}
+
+func (c *audioLevelCodec) MaxSize(*gst.Buffer) int { return 1 }
+
+func (c *audioLevelCodec) Write(inputMeta *gst.Buffer, _ RTPHeaderExtensionFlags, _ *gst.Buffer, data []byte) int {
+	level, ok := GetRTPAudioLevel(inputMeta)
+	if !ok {
+		return 0
+	}
+
+	if len(data) < 1 {
+		return -1
+	}
+
+	data[0] = level.Level & 0x7f
+
+	if level.Voice && c.voiceActivity() {
+		data[0] |= 0x80
+	}
+
+	return 1
+}
+
+func (c *audioLevelCodec) Read(_ RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool {
+	if len(data) < 1 {
+		return false
+	}
+
+	return SetRTPAudioLevel(buffer, RTPAudioLevel{
+		Level: data[0] & 0x7f,
+		Voice: data[0]&0x80 != 0 && c.voiceActivity(),
+	})
+}
+
+func (c *audioLevelCodec) voiceActivity() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.vad
+}
+
+// SetAttributes implements [RTPHeaderExtensionAttributer], the only attribute is "vad=on|off".
+func (c *audioLevelCodec) SetAttributes(attributes string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch strings.TrimSpace(attributes) {
+	case "", "vad=on":
+		c.vad = true
+	case "vad=off":
+		c.vad = false
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Attributes implements [RTPHeaderExtensionAttributer].
+func (c *audioLevelCodec) Attributes() string {
+	if c.voiceActivity() {
+		return "vad=on"
+	}
+
+	return "vad=off"
+}
+
+type videoOrientationCodec struct{}
+
+func (videoOrientationCodec) MaxSize(*gst.Buffer) int { return 1 }
+
+func (videoOrientationCodec) Write(inputMeta *gst.Buffer, _ RTPHeaderExtensionFlags, _ *gst.Buffer, data []byte) int {
+	orientation, ok := GetRTPVideoOrientation(inputMeta)
+	if !ok {
+		return 0
+	}
+
+	if len(data) < 1 {
+		return -1
+	}
+
+	data[0] = byte(orientation.Rotation/90) & 0x03
+
+	if orientation.Flip {
+		data[0] |= 0x04
+	}
+
+	if orientation.Camera {
+		data[0] |= 0x08
+	}
+
+	return 1
+}
+
+func (videoOrientationCodec) Read(_ RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool {
+	if len(data) < 1 {
+		return false
+	}
+
+	return SetRTPVideoOrientation(buffer, RTPVideoOrientation{
+		Rotation: uint16(data[0]&0x03) * 90,
+		Flip:     data[0]&0x04 != 0,
+		Camera:   data[0]&0x08 != 0,
+	})
+}
+
+type opaqueCodec struct {
+	uri string
+}
+
+func (c opaqueCodec) MaxSize(inputMeta *gst.Buffer) int {
+	data, _ := GetRTPHeaderExtensionBytes(inputMeta, c.uri)
+
+	return min(len(data), 255)
+}
+
+func (c opaqueCodec) Write(inputMeta *gst.Buffer, _ RTPHeaderExtensionFlags, _ *gst.Buffer, data []byte) int {
+	value, ok := GetRTPHeaderExtensionBytes(inputMeta, c.uri)
+	if !ok || len(value) == 0 {
+		return 0
+	}
+
+	if len(value) > len(data) {
+		return -1
+	}
+
+	return copy(data, value)
+}
+
+func (c opaqueCodec) Read(_ RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool {
+	return SetRTPHeaderExtensionBytes(buffer, c.uri, data)
+}
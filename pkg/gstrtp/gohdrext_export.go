@@ -0,0 +1,46 @@
+package gstrtp
+
+import (
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+import "C"
+
+//export _gogst_gstrtp1_RTPHeaderExtensionRead
+func _gogst_gstrtp1_RTPHeaderExtensionRead(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionFlags, carg2 *C.guint8, carg3 C.gsize, carg4 *C.GstBuffer) (cret C.gboolean) {
+	ext := UnsafeRTPHeaderExtensionFromGlibBorrow(unsafe.Pointer(carg0)).UnsafeLoadInstanceFromPrivateData().(*goRTPHeaderExtension)
+
+	var data []byte
+	if carg2 != nil && carg3 > 0 {
+		data = unsafe.Slice((*byte)(carg2), int(carg3))
+	}
+
+	// borrowed, so the output buffer of the depayloader stays writable
+	buffer := gst.UnsafeBufferFromGlibBorrow(unsafe.Pointer(carg4))
+
+	if ext.codec.Read(RTPHeaderExtensionFlags(carg1), data, buffer) {
+		cret = C.TRUE
+	}
+
+	return cret
+}
+
+//export _gogst_gstrtp1_RTPHeaderExtensionWrite
+func _gogst_gstrtp1_RTPHeaderExtensionWrite(carg0 *C.GstRTPHeaderExtension, carg1 *C.GstBuffer, carg2 C.GstRTPHeaderExtensionFlags, carg3 *C.GstBuffer, carg4 *C.guint8, carg5 C.gsize) (cret C.gssize) {
+	ext := UnsafeRTPHeaderExtensionFromGlibBorrow(unsafe.Pointer(carg0)).UnsafeLoadInstanceFromPrivateData().(*goRTPHeaderExtension)
+
+	var data []byte
+	if carg4 != nil && carg5 > 0 {
+		data = unsafe.Slice((*byte)(carg4), int(carg5))
+	}
+
+	inputMeta := gst.UnsafeBufferFromGlibBorrow(unsafe.Pointer(carg1))
+	output := gst.UnsafeBufferFromGlibBorrow(unsafe.Pointer(carg3))
+
+	return C.gssize(ext.codec.Write(inputMeta, RTPHeaderExtensionFlags(carg2), output, data))
+}
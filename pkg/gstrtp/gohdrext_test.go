@@ -0,0 +1,170 @@
+package gstrtp_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstrtp"
+)
+
+func TestGoRTPHeaderExtensions(t *testing.T) {
+	gst.Init()
+
+	// prefer the Go implementations over the ones shipped with GStreamer
+	if !gstrtp.RegisterRTPHeaderExtensions(nil, gst.RankPrimary) {
+		t.Fatal("could not register header extensions")
+	}
+
+	const opaqueURI = "urn:example:opaque"
+
+	if !gstrtp.RegisterRTPOpaqueHeaderExtension(nil, "gotestopaquehdrext", opaqueURI, gst.RankPrimary) {
+		t.Fatal("could not register opaque header extension")
+	}
+
+	// roundtrip writes the extension for the input buffer and reads it back into a new buffer
+	roundtrip := func(t *testing.T, uri string, input *gst.Buffer) ([]byte, *gst.Buffer) {
+		t.Helper()
+
+		ext := gstrtp.RTPHeaderExtensionCreateFromURI(uri)
+		if ext == nil {
+			t.Fatalf("no header extension for %s", uri)
+		}
+
+		ext.SetID(1)
+
+		data := make([]byte, ext.GetMaxSize(input))
+
+		n := ext.Write(input, gstrtp.RtpHeaderExtensionOneByte, gst.NewBuffer(), data)
+		if n < 0 {
+			t.Fatalf("%s: write failed", uri)
+		}
+
+		output := gst.NewBuffer()
+
+		if !ext.Read(gstrtp.RtpHeaderExtensionOneByte, data[:n], output) {
+			t.Fatalf("%s: read failed", uri)
+		}
+
+		return data[:n], output
+	}
+
+	t.Run("video orientation", func(t *testing.T) {
+		input := gst.NewBuffer()
+		gstrtp.SetRTPVideoOrientation(input, gstrtp.RTPVideoOrientation{Rotation: 270, Flip: true})
+
+		data, output := roundtrip(t, gstrtp.RTPHeaderExtensionURIVideoOrientation, input)
+		if !bytes.Equal(data, []byte{0x07}) {
+			t.Errorf("unexpected data %x", data)
+		}
+
+		orientation, ok := gstrtp.GetRTPVideoOrientation(output)
+		if !ok || orientation != (gstrtp.RTPVideoOrientation{Rotation: 270, Flip: true}) {
+			t.Errorf("unexpected orientation %+v", orientation)
+		}
+	})
+
+	t.Run("audio level", func(t *testing.T) {
+		input := gst.NewBuffer()
+		gstrtp.SetRTPAudioLevel(input, gstrtp.RTPAudioLevel{Level: 42, Voice: true})
+
+		data, output := roundtrip(t, gstrtp.RTPHeaderExtensionURIAudioLevel, input)
+		if !bytes.Equal(data, []byte{0x80 | 42}) {
+			t.Errorf("unexpected data %x", data)
+		}
+
+		level, ok := gstrtp.GetRTPAudioLevel(output)
+		if !ok || level != (gstrtp.RTPAudioLevel{Level: 42, Voice: true}) {
+			t.Errorf("unexpected level %+v", level)
+		}
+	})
+
+	t.Run("transport wide cc", func(t *testing.T) {
+		ext := gstrtp.RTPHeaderExtensionCreateFromURI(gstrtp.RTPHeaderExtensionURITransportWideCC)
+		ext.SetID(1)
+
+		for i := range 3 {
+			data := make([]byte, 2)
+
+			if n := ext.Write(gst.NewBuffer(), gstrtp.RtpHeaderExtensionOneByte, gst.NewBuffer(), data); n != 2 {
+				t.Fatalf("unexpected size %d", n)
+			}
+
+			output := gst.NewBuffer()
+			ext.Read(gstrtp.RtpHeaderExtensionOneByte, data, output)
+
+			if seqnum, _ := gstrtp.GetRTPTransportWideSeqnum(output); seqnum != uint16(i) {
+				t.Errorf("unexpected seqnum %d, expected %d", seqnum, i)
+			}
+		}
+	})
+
+	t.Run("abs send time", func(t *testing.T) {
+		data, output := roundtrip(t, gstrtp.RTPHeaderExtensionURIAbsSendTime, gst.NewBuffer())
+		if len(data) != 3 {
+			t.Fatalf("unexpected data %x", data)
+		}
+
+		if _, ok := gstrtp.GetRTPAbsSendTime(output); !ok {
+			t.Error("abs send time missing")
+		}
+	})
+
+	t.Run("opaque", func(t *testing.T) {
+		input := gst.NewBuffer()
+		gstrtp.SetRTPHeaderExtensionBytes(input, opaqueURI, []byte("hello"))
+
+		_, output := roundtrip(t, opaqueURI, input)
+
+		if value, ok := gstrtp.GetRTPHeaderExtensionBytes(output, opaqueURI); !ok || string(value) != "hello" {
+			t.Errorf("unexpected value %q", value)
+		}
+	})
+}
+
+type constCodec struct{}
+
+func (constCodec) MaxSize(*gst.Buffer) int { return 1 }
+
+func (constCodec) Write(_ *gst.Buffer, _ gstrtp.RTPHeaderExtensionFlags, _ *gst.Buffer, data []byte) int {
+	data[0] = 0x2a
+
+	return 1
+}
+
+func (constCodec) Read(gstrtp.RTPHeaderExtensionFlags, []byte, *gst.Buffer) bool { return true }
+
+func TestRTPHeaderExtensionCreateFromURI(t *testing.T) {
+	gst.Init()
+
+	const uri = "urn:example:const"
+
+	typ := gstrtp.RegisterRTPHeaderExtension("GoTestConstHeaderExtension", gstrtp.RTPHeaderExtensionInfo{
+		URI:         uri,
+		LongName:    "Constant RTP Header Extension",
+		Description: "Writes a constant byte",
+		Author:      "go-gst",
+		Flags:       gstrtp.RtpHeaderExtensionOneByte,
+	}, func() gstrtp.RTPHeaderExtensionCodec {
+		return constCodec{}
+	})
+
+	if !gst.ElementRegister(nil, "gotestconsthdrext", uint(gst.RankNone), typ) {
+		t.Fatal("could not register header extension")
+	}
+
+	ext := gstrtp.RTPHeaderExtensionCreateFromURI(uri)
+	if ext == nil {
+		t.Fatal("header extension not found by uri")
+	}
+
+	if got := ext.GetURI(); got != uri {
+		t.Errorf("unexpected uri %q", got)
+	}
+
+	data := make([]byte, ext.GetMaxSize(gst.NewBuffer()))
+
+	if n := ext.Write(gst.NewBuffer(), gstrtp.RtpHeaderExtensionOneByte, gst.NewBuffer(), data); n != 1 || data[0] != 0x2a {
+		t.Errorf("unexpected data %x", data)
+	}
+}
@@ -0,0 +1,221 @@
+package gstrtp
+
+import (
+	"fmt"
+
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// RTPPacketizer splits encoded frames into RTP payloads for payloaders registered with
+// [RegisterRTPPayloader]. Every element instance gets its own packetizer.
+type RTPPacketizer interface {
+	// Packetize splits the frame into payloads of at most maxPayloadSize bytes. The payloads
+	// are sent in order and the marker bit is set on the packet of the last payload. The frame
+	// must not be retained after Packetize returned.
+	Packetize(frame []byte, pts gst.ClockTime, maxPayloadSize int) [][]byte
+}
+
+// RTPPacketizerFunc adapts a function to an [RTPPacketizer].
+type RTPPacketizerFunc func(frame []byte, pts gst.ClockTime, maxPayloadSize int) [][]byte
+
+// Packetize implements [RTPPacketizer].
+func (f RTPPacketizerFunc) Packetize(frame []byte, pts gst.ClockTime, maxPayloadSize int) [][]byte {
+	return f(frame, pts, maxPayloadSize)
+}
+
+// RTPDepacketizer reassembles frames from RTP payloads for depayloaders registered with
+// [RegisterRTPDepayloader]. Every element instance gets its own depacketizer.
+type RTPDepacketizer interface {
+	// Depacketize is called for every received packet in sequence number order. It returns the
+	// frame that is completed by the packet, or nil if the frame is not complete yet. The payload
+	// references the mapped packet, so it must be copied if it is retained.
+	Depacketize(header *RTPHeader, payload []byte) []byte
+
+	// Reset drops partially received frames. It is called when packets were lost.
+	Reset()
+}
+
+// RTPPayloaderInfo describes a payloader registered with [RegisterRTPPayloader].
+type RTPPayloaderInfo struct {
+	// LongName, Klass, Description and Author are the element metadata.
+	LongName    string
+	Klass       string
+	Description string
+	Author      string
+
+	// SinkCaps are the caps of the encoded frames that are accepted by the payloader.
+	SinkCaps *gst.Caps
+
+	// Media, EncodingName and ClockRate are used for the application/x-rtp caps of the
+	// src pad. The payloader always uses a dynamic payload type.
+	Media        string
+	EncodingName string
+	ClockRate    uint32
+}
+
+// RTPDepayloaderInfo describes a depayloader registered with [RegisterRTPDepayloader].
+type RTPDepayloaderInfo struct {
+	// LongName, Klass, Description and Author are the element metadata.
+	LongName    string
+	Klass       string
+	Description string
+	Author      string
+
+	// Media, EncodingName and ClockRate are used for the application/x-rtp caps of the sink pad.
+	Media        string
+	EncodingName string
+	ClockRate    uint32
+
+	// SrcCaps are the fixed caps of the reassembled frames.
+	SrcCaps *gst.Caps
+}
+
+// rtpCaps returns the application/x-rtp caps with a dynamic payload type
+func rtpCaps(media, encodingName string, clockRate uint32) *gst.Caps {
+	return gst.CapsFromString(fmt.Sprintf(
+		"application/x-rtp, media=(string)%s, payload=(int)[ 96, 127 ], clock-rate=(int)%d, encoding-name=(string)%s",
+		media, clockRate, encodingName,
+	))
+}
+
+type goRTPPayloader struct {
+	RTPBasePayloadInstance
+
+	info       RTPPayloaderInfo
+	packetizer RTPPacketizer
+}
+
+// RegisterRTPPayloader registers a new payloader type that splits every input buffer with
+// the packetizer created by newPacketizer. The RTP header, including the timestamp derived
+// from the PTS of the input buffer, is set by the [RTPBasePayload] base class.
+//
+// The returned type can be registered as an element with [gst.ElementRegister].
+func RegisterRTPPayloader(name string, info RTPPayloaderInfo, newPacketizer func() RTPPacketizer) gobject.Type {
+	return RegisterRTPBasePayloadSubClass(
+		name,
+		func(class *RTPBasePayloadClass) {
+			class.ParentClass().SetStaticMetadata(info.LongName, info.Klass, info.Description, info.Author)
+			class.ParentClass().AddPadTemplate(gst.NewPadTemplate("sink", gst.PadSink, gst.PadAlways, info.SinkCaps))
+			class.ParentClass().AddPadTemplate(gst.NewPadTemplate("src", gst.PadSrc, gst.PadAlways, rtpCaps(info.Media, info.EncodingName, info.ClockRate)))
+		},
+		func() *goRTPPayloader {
+			return &goRTPPayloader{
+				info:       info,
+				packetizer: newPacketizer(),
+			}
+		},
+		RTPBasePayloadOverrides[*goRTPPayloader]{
+			SetCaps: func(p *goRTPPayloader, _ *gst.Caps) bool {
+				p.SetOptions(p.info.Media, true, p.info.EncodingName, p.info.ClockRate)
+
+				return p.SetOutcapsStructure(nil)
+			},
+			HandleBuffer: func(p *goRTPPayloader, buffer *gst.Buffer) gst.FlowReturn {
+				return p.handleBuffer(buffer)
+			},
+		},
+		nil,
+	)
+}
+
+// maxPayloadSize returns the payload size that fits into the configured MTU
+func (p *goRTPPayloader) maxPayloadSize() int {
+	var mtu uint
+
+	switch v := p.ObjectProperty("mtu").(type) {
+	case uint:
+		mtu = v
+	case uint32:
+		mtu = uint(v)
+	}
+
+	return max(int(mtu)-int(RTPBufferCalcHeaderLen(0)), 1)
+}
+
+func (p *goRTPPayloader) handleBuffer(buffer *gst.Buffer) gst.FlowReturn {
+	info, ok := buffer.Map(gst.MapRead)
+	if !ok {
+		return gst.FlowError
+	}
+
+	payloads := p.packetizer.Packetize(info.Data(), buffer.PTS(), p.maxPayloadSize())
+	info.Unmap()
+
+	if len(payloads) == 0 {
+		return gst.FlowOK
+	}
+
+	list := gst.NewBufferListSized(uint(len(payloads)))
+
+	for i, payload := range payloads {
+		out := p.AllocateOutputBuffer(uint(len(payload)), 0, 0)
+
+		err := WithRTPBuffer(out, gst.MapWrite, func(rtp *RTPBuffer) error {
+			copy(rtp.Payload(), payload)
+			rtp.SetMarker(i == len(payloads)-1)
+
+			return nil
+		})
+		if err != nil {
+			return gst.FlowError
+		}
+
+		out.SetPTS(buffer.PTS())
+		out.SetDTS(buffer.DTS())
+
+		list.Insert(-1, out)
+	}
+
+	return p.PushList(list)
+}
+
+type goRTPDepayloader struct {
+	RTPBaseDepayloadInstance
+
+	info         RTPDepayloaderInfo
+	depacketizer RTPDepacketizer
+}
+
+// RegisterRTPDepayloader registers a new depayloader type that reassembles frames with the
+// depacketizer created by newDepacketizer. The timestamps of the output buffers are set by
+// the [RTPBaseDepayload] base class.
+//
+// The returned type can be registered as an element with [gst.ElementRegister].
+func RegisterRTPDepayloader(name string, info RTPDepayloaderInfo, newDepacketizer func() RTPDepacketizer) gobject.Type {
+	return RegisterRTPBaseDepayloadSubClass(
+		name,
+		func(class *RTPBaseDepayloadClass) {
+			class.ParentClass().SetStaticMetadata(info.LongName, info.Klass, info.Description, info.Author)
+			class.ParentClass().AddPadTemplate(gst.NewPadTemplate("sink", gst.PadSink, gst.PadAlways, rtpCaps(info.Media, info.EncodingName, info.ClockRate)))
+			class.ParentClass().AddPadTemplate(gst.NewPadTemplate("src", gst.PadSrc, gst.PadAlways, info.SrcCaps))
+		},
+		func() *goRTPDepayloader {
+			return &goRTPDepayloader{
+				info:         info,
+				depacketizer: newDepacketizer(),
+			}
+		},
+		RTPBaseDepayloadOverrides[*goRTPDepayloader]{
+			SetCaps: func(d *goRTPDepayloader, _ *gst.Caps) bool {
+				return d.GetStaticPad("src").PushEvent(gst.NewEventCaps(d.info.SrcCaps))
+			},
+			ProcessRtpPacket: func(d *goRTPDepayloader, rtp *RTPBuffer) *gst.Buffer {
+				header := rtp.Header()
+
+				frame := d.depacketizer.Depacketize(&header, rtp.Payload())
+				if frame == nil {
+					return nil
+				}
+
+				return bufferFromBytes(frame)
+			},
+			PacketLost: func(d *goRTPDepayloader, event *gst.Event) bool {
+				d.depacketizer.Reset()
+
+				return d.ParentPacketLost(event)
+			},
+		},
+		nil,
+	)
+}
@@ -0,0 +1,168 @@
+package gstrtp_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstcheck"
+	"github.com/go-gst/go-gst/pkg/gstrtp"
+)
+
+// chunkPacketizer splits frames into chunks of the maximum payload size
+type chunkPacketizer struct{}
+
+func (chunkPacketizer) Packetize(frame []byte, _ gst.ClockTime, maxPayloadSize int) [][]byte {
+	var payloads [][]byte
+
+	for len(frame) > 0 {
+		n := min(len(frame), maxPayloadSize)
+		payloads = append(payloads, bytes.Clone(frame[:n]))
+		frame = frame[n:]
+	}
+
+	return payloads
+}
+
+// chunkDepacketizer concatenates payloads until the marker bit is set
+type chunkDepacketizer struct {
+	frame []byte
+}
+
+func (d *chunkDepacketizer) Depacketize(header *gstrtp.RTPHeader, payload []byte) []byte {
+	d.frame = append(d.frame, payload...)
+
+	if !header.Marker {
+		return nil
+	}
+
+	frame := d.frame
+	d.frame = nil
+
+	return frame
+}
+
+func (d *chunkDepacketizer) Reset() {
+	d.frame = nil
+}
+
+func registerChunkElements(t *testing.T) {
+	t.Helper()
+
+	payType := gstrtp.RegisterRTPPayloader("GoTestChunkPay", gstrtp.RTPPayloaderInfo{
+		LongName:     "chunk payloader",
+		Klass:        "Codec/Payloader/Network/RTP",
+		Description:  "Splits frames into chunks",
+		Author:       "go-gst",
+		SinkCaps:     gst.CapsFromString("application/x-go-chunks"),
+		Media:        "application",
+		EncodingName: "X-GO-CHUNKS",
+		ClockRate:    90000,
+	}, func() gstrtp.RTPPacketizer {
+		return chunkPacketizer{}
+	})
+
+	depayType := gstrtp.RegisterRTPDepayloader("GoTestChunkDepay", gstrtp.RTPDepayloaderInfo{
+		LongName:     "chunk depayloader",
+		Klass:        "Codec/Depayloader/Network/RTP",
+		Description:  "Reassembles frames from chunks",
+		Author:       "go-gst",
+		Media:        "application",
+		EncodingName: "X-GO-CHUNKS",
+		ClockRate:    90000,
+		SrcCaps:      gst.CapsFromString("application/x-go-chunks"),
+	}, func() gstrtp.RTPDepacketizer {
+		return &chunkDepacketizer{}
+	})
+
+	if !gst.ElementRegister(nil, "gochunkpay", uint(gst.RankNone), payType) {
+		t.Fatal("could not register payloader")
+	}
+
+	if !gst.ElementRegister(nil, "gochunkdepay", uint(gst.RankNone), depayType) {
+		t.Fatal("could not register depayloader")
+	}
+}
+
+func TestGoRTPPayloader(t *testing.T) {
+	gst.Init()
+
+	registerChunkElements(t)
+
+	pay := gst.ElementFactoryMake("gochunkpay", "")
+	// 12 bytes of RTP header leave 16 bytes for the payload
+	pay.SetObjectProperty("mtu", uint(28))
+
+	payHarness := gstcheck.NewHarnessWithElement(pay, "sink", "src")
+	defer payHarness.Teardown()
+
+	payHarness.SetSrcCapsStr("application/x-go-chunks")
+
+	frame := make([]byte, 40)
+	for i := range frame {
+		frame[i] = byte(i)
+	}
+
+	in := gst.NewBufferAllocate(nil, uint(len(frame)), nil)
+
+	inInfo, ok := in.Map(gst.MapWrite)
+	if !ok {
+		t.Fatal("could not map input buffer")
+	}
+
+	copy(inInfo.Data(), frame)
+	inInfo.Unmap()
+	in.SetPTS(0)
+
+	if ret := payHarness.Push(in); ret != gst.FlowOK {
+		t.Fatalf("push to payloader failed: %v", ret)
+	}
+
+	depayHarness := gstcheck.NewHarness("gochunkdepay")
+	defer depayHarness.Teardown()
+
+	depayHarness.SetSrcCapsStr("application/x-rtp, media=(string)application, payload=(int)96, clock-rate=(int)90000, encoding-name=(string)X-GO-CHUNKS")
+
+	expectedSizes := []int{16, 16, 8}
+
+	for i, size := range expectedSizes {
+		packet := payHarness.Pull()
+		if packet == nil {
+			t.Fatalf("missing packet %d", i)
+		}
+
+		err := gstrtp.WithRTPBuffer(packet, gst.MapRead, func(rtp *gstrtp.RTPBuffer) error {
+			if len(rtp.Payload()) != size {
+				t.Errorf("packet %d: unexpected payload size %d", i, len(rtp.Payload()))
+			}
+
+			if marker := rtp.Header().Marker; marker != (i == len(expectedSizes)-1) {
+				t.Errorf("packet %d: unexpected marker %v", i, marker)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ret := depayHarness.Push(packet); ret != gst.FlowOK {
+			t.Fatalf("push to depayloader failed: %v", ret)
+		}
+	}
+
+	out := depayHarness.Pull()
+	if out == nil {
+		t.Fatal("no frame from depayloader")
+	}
+
+	info, ok := out.Map(gst.MapRead)
+	if !ok {
+		t.Fatal("could not map frame")
+	}
+	defer info.Unmap()
+
+	if !bytes.Equal(info.Data(), frame) {
+		t.Errorf("reassembled frame differs: %v", info.Data())
+	}
+}
@@ -66,21 +66,16 @@ import (
 // }
 // extern gsize _goglib_gstrtp1_RTPHeaderExtension_get_max_size(GstRTPHeaderExtension*, const GstBuffer*);
 // extern GstRTPHeaderExtensionFlags _goglib_gstrtp1_RTPHeaderExtension_get_supported_flags(GstRTPHeaderExtension*);
-// extern gboolean _goglib_gstrtp1_RTPHeaderExtension_read(GstRTPHeaderExtension*, GstRTPHeaderExtensionFlags, const guint8*, gsize, GstBuffer*);
 // extern gboolean _goglib_gstrtp1_RTPHeaderExtension_set_attributes(GstRTPHeaderExtension*, GstRTPHeaderExtensionDirection, const gchar*);
 // extern gboolean _goglib_gstrtp1_RTPHeaderExtension_set_caps_from_attributes(GstRTPHeaderExtension*, GstCaps*);
 // extern gboolean _goglib_gstrtp1_RTPHeaderExtension_set_non_rtp_sink_caps(GstRTPHeaderExtension*, const GstCaps*);
 // extern gboolean _goglib_gstrtp1_RTPHeaderExtension_update_non_rtp_src_caps(GstRTPHeaderExtension*, GstCaps*);
-// extern gssize _goglib_gstrtp1_RTPHeaderExtension_write(GstRTPHeaderExtension*, const GstBuffer*, GstRTPHeaderExtensionFlags, GstBuffer*, guint8*, gsize);
 // gsize _goglib_gstrtp1_RTPHeaderExtension_virtual_get_max_size(void* fnptr, GstRTPHeaderExtension* carg0, const GstBuffer* carg1) {
 // 	return ((gsize (*) (GstRTPHeaderExtension*, const GstBuffer*))(fnptr))(carg0, carg1);
 // }
 // GstRTPHeaderExtensionFlags _goglib_gstrtp1_RTPHeaderExtension_virtual_get_supported_flags(void* fnptr, GstRTPHeaderExtension* carg0) {
 // 	return ((GstRTPHeaderExtensionFlags (*) (GstRTPHeaderExtension*))(fnptr))(carg0);
 // }
-// gboolean _goglib_gstrtp1_RTPHeaderExtension_virtual_read(void* fnptr, GstRTPHeaderExtension* carg0, GstRTPHeaderExtensionFlags carg1, const guint8* carg2, gsize carg3, GstBuffer* carg4) {
-// 	return ((gboolean (*) (GstRTPHeaderExtension*, GstRTPHeaderExtensionFlags, const guint8*, gsize, GstBuffer*))(fnptr))(carg0, carg1, carg2, carg3, carg4);
-// }
 // gboolean _goglib_gstrtp1_RTPHeaderExtension_virtual_set_attributes(void* fnptr, GstRTPHeaderExtension* carg0, GstRTPHeaderExtensionDirection carg1, const gchar* carg2) {
 // 	return ((gboolean (*) (GstRTPHeaderExtension*, GstRTPHeaderExtensionDirection, const gchar*))(fnptr))(carg0, carg1, carg2);
 // }
@@ -93,9 +88,6 @@ import (
 // gboolean _goglib_gstrtp1_RTPHeaderExtension_virtual_update_non_rtp_src_caps(void* fnptr, GstRTPHeaderExtension* carg0, GstCaps* carg1) {
 // 	return ((gboolean (*) (GstRTPHeaderExtension*, GstCaps*))(fnptr))(carg0, carg1);
 // }
-// gssize _goglib_gstrtp1_RTPHeaderExtension_virtual_write(void* fnptr, GstRTPHeaderExtension* carg0, const GstBuffer* carg1, GstRTPHeaderExtensionFlags carg2, GstBuffer* carg3, guint8* carg4, gsize carg5) {
-// 	return ((gssize (*) (GstRTPHeaderExtension*, const GstBuffer*, GstRTPHeaderExtensionFlags, GstBuffer*, guint8*, gsize))(fnptr))(carg0, carg1, carg2, carg3, carg4, carg5);
-// }
 import "C"
 
 // GType values.
@@ -2559,6 +2551,7 @@ var _ RTPHeaderExtension = (*RTPHeaderExtensionInstance)(nil)
 // 
 // see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#GstRTPHeaderExtension
 type RTPHeaderExtension interface {
+	RTPHeaderExtensionExtManual // handwritten functions
 	gst.Element
 	upcastToGstRTPHeaderExtension() *RTPHeaderExtensionInstance
 
@@ -2586,10 +2579,6 @@ type RTPHeaderExtension interface {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_get_uri
 	GetURI() string
-	// Read wraps gst_rtp_header_extension_read
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_read
-	Read(RTPHeaderExtensionFlags, []uint8, *gst.Buffer) bool
 	// SetAttributesFromCaps wraps gst_rtp_header_extension_set_attributes_from_caps
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_set_attributes_from_caps
@@ -2626,10 +2615,6 @@ type RTPHeaderExtension interface {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_wants_update_non_rtp_src_caps
 	WantsUpdateNonRtpSrcCaps() bool
-	// Write wraps gst_rtp_header_extension_write
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_write
-	Write(*gst.Buffer, RTPHeaderExtensionFlags, *gst.Buffer, []uint8) int
 
 	// chain up virtual methods:
 
@@ -2643,11 +2628,6 @@ type RTPHeaderExtension interface {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#get_supported_flags
 	ParentGetSupportedFlags() RTPHeaderExtensionFlags
-	// ParentRead calls the default implementations of the `GstRTPHeaderExtension.read` virtual method.
-	// This function's behavior is not defined when the parent does not implement the virtual method.
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#read
-	ParentRead(readFlags RTPHeaderExtensionFlags, data []uint8, buffer *gst.Buffer) bool
 	// ParentSetAttributes calls the default implementations of the `GstRTPHeaderExtension.set_attributes` virtual method.
 	// This function's behavior is not defined when the parent does not implement the virtual method.
 	// 
@@ -2668,11 +2648,6 @@ type RTPHeaderExtension interface {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#update_non_rtp_src_caps
 	ParentUpdateNonRtpSrcCaps(caps *gst.Caps) bool
-	// ParentWrite calls the default implementations of the `GstRTPHeaderExtension.write` virtual method.
-	// This function's behavior is not defined when the parent does not implement the virtual method.
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#write
-	ParentWrite(inputMeta *gst.Buffer, writeFlags RTPHeaderExtensionFlags, output *gst.Buffer, data []uint8) int
 }
 
 func unsafeWrapRTPHeaderExtension(base *gobject.ObjectInstance) *RTPHeaderExtensionInstance {
@@ -2871,40 +2846,6 @@ func (ext *RTPHeaderExtensionInstance) GetURI() string {
 	return goret
 }
 
-// Read wraps gst_rtp_header_extension_read
-// 
-// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_read
-func (ext *RTPHeaderExtensionInstance) Read(readFlags RTPHeaderExtensionFlags, data []uint8, buffer *gst.Buffer) bool {
-	var carg0 *C.GstRTPHeaderExtension     // in, none, converted
-	var carg1 C.GstRTPHeaderExtensionFlags // in, none, casted
-	var carg2 *C.guint8                    // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg3)
-	var carg3 C.gsize                      // implicit
-	var carg4 *C.GstBuffer                 // in, none, converted
-	var cret  C.gboolean                   // return
-
-	carg0 = (*C.GstRTPHeaderExtension)(UnsafeRTPHeaderExtensionToGlibNone(ext))
-	carg1 = C.GstRTPHeaderExtensionFlags(readFlags)
-	_ = data
-	_ = carg2
-	_ = carg3
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-	carg4 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer))
-
-	cret = C.gst_rtp_header_extension_read(carg0, carg1, carg2, carg3, carg4)
-	runtime.KeepAlive(ext)
-	runtime.KeepAlive(readFlags)
-	runtime.KeepAlive(data)
-	runtime.KeepAlive(buffer)
-
-	var goret bool
-
-	if cret != 0 {
-		goret = true
-	}
-
-	return goret
-}
-
 // SetAttributesFromCaps wraps gst_rtp_header_extension_set_attributes_from_caps
 // 
 // see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_set_attributes_from_caps
@@ -3097,41 +3038,6 @@ func (ext *RTPHeaderExtensionInstance) WantsUpdateNonRtpSrcCaps() bool {
 	return goret
 }
 
-// Write wraps gst_rtp_header_extension_write
-// 
-// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#gst_rtp_header_extension_write
-func (ext *RTPHeaderExtensionInstance) Write(inputMeta *gst.Buffer, writeFlags RTPHeaderExtensionFlags, output *gst.Buffer, data []uint8) int {
-	var carg0 *C.GstRTPHeaderExtension     // in, none, converted
-	var carg1 *C.GstBuffer                 // in, none, converted
-	var carg2 C.GstRTPHeaderExtensionFlags // in, none, casted
-	var carg3 *C.GstBuffer                 // in, none, converted
-	var carg4 *C.guint8                    // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg5)
-	var carg5 C.gsize                      // implicit
-	var cret  C.gssize                     // return, none, casted
-
-	carg0 = (*C.GstRTPHeaderExtension)(UnsafeRTPHeaderExtensionToGlibNone(ext))
-	carg1 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(inputMeta))
-	carg2 = C.GstRTPHeaderExtensionFlags(writeFlags)
-	carg3 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(output))
-	_ = data
-	_ = carg4
-	_ = carg5
-	panic("unimplemented conversion of []uint8 (guint8*) because of unimplemented: non-fixed size array")
-
-	cret = C.gst_rtp_header_extension_write(carg0, carg1, carg2, carg3, carg4, carg5)
-	runtime.KeepAlive(ext)
-	runtime.KeepAlive(inputMeta)
-	runtime.KeepAlive(writeFlags)
-	runtime.KeepAlive(output)
-	runtime.KeepAlive(data)
-
-	var goret int
-
-	goret = int(cret)
-
-	return goret
-}
-
 // RTPHeaderExtensionOverrides is the struct used to override the default implementation of virtual methods.
 // it is generic over the extending instance type.
 type RTPHeaderExtensionOverrides[Instance RTPHeaderExtension] struct {
@@ -3146,10 +3052,6 @@ type RTPHeaderExtensionOverrides[Instance RTPHeaderExtension] struct {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#get_supported_flags
 	GetSupportedFlags func(Instance) RTPHeaderExtensionFlags
-	// // Read allows you to override the implementation of the virtual method read.
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#read
-	Read func(Instance, RTPHeaderExtensionFlags, []uint8, *gst.Buffer) bool
 	// // SetAttributes allows you to override the implementation of the virtual method set_attributes.
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#set_attributes
@@ -3166,10 +3068,6 @@ type RTPHeaderExtensionOverrides[Instance RTPHeaderExtension] struct {
 	// 
 	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#update_non_rtp_src_caps
 	UpdateNonRtpSrcCaps func(Instance, *gst.Caps) bool
-	// // Write allows you to override the implementation of the virtual method write.
-	// 
-	// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#write
-	Write func(Instance, *gst.Buffer, RTPHeaderExtensionFlags, *gst.Buffer, []uint8) int
 }
 
 // UnsafeApplyRTPHeaderExtensionOverrides applies the overrides to init the gclass by setting the trampoline functions.
@@ -3221,37 +3119,6 @@ func UnsafeApplyRTPHeaderExtensionOverrides[Instance RTPHeaderExtension](gclass
 		)
 	}
 
-	if overrides.Read != nil {
-		pclass.read = (*[0]byte)(C._goglib_gstrtp1_RTPHeaderExtension_read)
-		classdata.StoreVirtualMethod(
-			unsafe.Pointer(pclass),
-			"_goglib_gstrtp1_RTPHeaderExtension_read",
-			func(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionFlags, carg2 *C.guint8, carg3 C.gsize, carg4 *C.GstBuffer) (cret C.gboolean) {
-				var ext       Instance                // go GstRTPHeaderExtension subclass
-				var readFlags RTPHeaderExtensionFlags // in, none, casted
-				var data      []uint8                 // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg3)
-				var buffer    *gst.Buffer             // in, none, converted
-				var goret     bool                    // return
-
-				ext = UnsafeRTPHeaderExtensionFromGlibBorrow(unsafe.Pointer(carg0)).UnsafeLoadInstanceFromPrivateData().(Instance)
-				readFlags = RTPHeaderExtensionFlags(carg1)
-				_ = data
-				_ = carg2
-				_ = carg3
-				panic("unimplemented conversion of []uint8 (const guint8*) because of unknown reason")
-				buffer = gst.UnsafeBufferFromGlibNone(unsafe.Pointer(carg4))
-
-				goret = overrides.Read(ext, readFlags, data, buffer)
-
-				if goret {
-					cret = C.TRUE
-				}
-
-				return cret
-			},
-		)
-	}
-
 	if overrides.SetAttributes != nil {
 		pclass.set_attributes = (*[0]byte)(C._goglib_gstrtp1_RTPHeaderExtension_set_attributes)
 		classdata.StoreVirtualMethod(
@@ -3349,37 +3216,6 @@ func UnsafeApplyRTPHeaderExtensionOverrides[Instance RTPHeaderExtension](gclass
 			},
 		)
 	}
-
-	if overrides.Write != nil {
-		pclass.write = (*[0]byte)(C._goglib_gstrtp1_RTPHeaderExtension_write)
-		classdata.StoreVirtualMethod(
-			unsafe.Pointer(pclass),
-			"_goglib_gstrtp1_RTPHeaderExtension_write",
-			func(carg0 *C.GstRTPHeaderExtension, carg1 *C.GstBuffer, carg2 C.GstRTPHeaderExtensionFlags, carg3 *C.GstBuffer, carg4 *C.guint8, carg5 C.gsize) (cret C.gssize) {
-				var ext        Instance                // go GstRTPHeaderExtension subclass
-				var inputMeta  *gst.Buffer             // in, none, converted
-				var writeFlags RTPHeaderExtensionFlags // in, none, casted
-				var output     *gst.Buffer             // in, none, converted
-				var data       []uint8                 // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg5)
-				var goret      int                     // return, none, casted
-
-				ext = UnsafeRTPHeaderExtensionFromGlibBorrow(unsafe.Pointer(carg0)).UnsafeLoadInstanceFromPrivateData().(Instance)
-				inputMeta = gst.UnsafeBufferFromGlibNone(unsafe.Pointer(carg1))
-				writeFlags = RTPHeaderExtensionFlags(carg2)
-				output = gst.UnsafeBufferFromGlibNone(unsafe.Pointer(carg3))
-				_ = data
-				_ = carg4
-				_ = carg5
-				panic("unimplemented conversion of []uint8 (guint8*) because of unknown reason")
-
-				goret = overrides.Write(ext, inputMeta, writeFlags, output, data)
-
-				cret = C.gssize(goret)
-
-				return cret
-			},
-		)
-	}
 }
 
 // ParentGetMaxSize calls the default implementations of the `GstRTPHeaderExtension.get_max_size` virtual method.
@@ -3429,43 +3265,6 @@ func (ext *RTPHeaderExtensionInstance) ParentGetSupportedFlags() RTPHeaderExtens
 	return goret
 }
 
-// ParentRead calls the default implementations of the `GstRTPHeaderExtension.read` virtual method.
-// This function's behavior is not defined when the parent does not implement the virtual method.
-// 
-// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#read
-func (ext *RTPHeaderExtensionInstance) ParentRead(readFlags RTPHeaderExtensionFlags, data []uint8, buffer *gst.Buffer) bool {
-	var carg0 *C.GstRTPHeaderExtension
-	var carg1 C.GstRTPHeaderExtensionFlags // in, none, converted
-	var carg2 *C.guint8                    // in, none, casted
-	var carg3 C.gsize                      // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg3)
-	var carg4 *C.GstBuffer                 // implicit
-	var cret  C.gboolean                   // return
-
-	parentclass := (*C.GstRTPHeaderExtensionClass)(classdata.PeekParentClass(UnsafeRTPHeaderExtensionToGlibNone(ext)))
-
-	carg0 = (*C.GstRTPHeaderExtension)(UnsafeRTPHeaderExtensionToGlibNone(ext))
-	carg1 = C.GstRTPHeaderExtensionFlags(readFlags)
-	_ = data
-	_ = carg2
-	_ = carg3
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-	carg4 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer))
-
-	cret = C._goglib_gstrtp1_RTPHeaderExtension_virtual_read(unsafe.Pointer(parentclass.read), carg0, carg1, carg2, carg3, carg4)
-	runtime.KeepAlive(ext)
-	runtime.KeepAlive(readFlags)
-	runtime.KeepAlive(data)
-	runtime.KeepAlive(buffer)
-
-	var goret bool
-
-	if cret != 0 {
-		goret = true
-	}
-
-	return goret
-}
-
 // ParentSetAttributes calls the default implementations of the `GstRTPHeaderExtension.set_attributes` virtual method.
 // This function's behavior is not defined when the parent does not implement the virtual method.
 // 
@@ -3578,44 +3377,6 @@ func (ext *RTPHeaderExtensionInstance) ParentUpdateNonRtpSrcCaps(caps *gst.Caps)
 	return goret
 }
 
-// ParentWrite calls the default implementations of the `GstRTPHeaderExtension.write` virtual method.
-// This function's behavior is not defined when the parent does not implement the virtual method.
-// 
-// see also https://gstreamer.freedesktop.org/documentation/rtp/gstrtphdrext.html#write
-func (ext *RTPHeaderExtensionInstance) ParentWrite(inputMeta *gst.Buffer, writeFlags RTPHeaderExtensionFlags, output *gst.Buffer, data []uint8) int {
-	var carg0 *C.GstRTPHeaderExtension
-	var carg1 *C.GstBuffer                 // in, none, converted
-	var carg2 C.GstRTPHeaderExtensionFlags // in, none, converted
-	var carg3 *C.GstBuffer                 // in, none, casted
-	var carg4 *C.guint8                    // in, none, converted
-	var carg5 C.gsize                      // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg5)
-	var cret  C.gssize                     // return, none, casted
-
-	parentclass := (*C.GstRTPHeaderExtensionClass)(classdata.PeekParentClass(UnsafeRTPHeaderExtensionToGlibNone(ext)))
-
-	carg0 = (*C.GstRTPHeaderExtension)(UnsafeRTPHeaderExtensionToGlibNone(ext))
-	carg1 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(inputMeta))
-	carg2 = C.GstRTPHeaderExtensionFlags(writeFlags)
-	carg3 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(output))
-	_ = data
-	_ = carg4
-	_ = carg5
-	panic("unimplemented conversion of []uint8 (guint8*) because of unimplemented: non-fixed size array")
-
-	cret = C._goglib_gstrtp1_RTPHeaderExtension_virtual_write(unsafe.Pointer(parentclass.write), carg0, carg1, carg2, carg3, carg4, carg5)
-	runtime.KeepAlive(ext)
-	runtime.KeepAlive(inputMeta)
-	runtime.KeepAlive(writeFlags)
-	runtime.KeepAlive(output)
-	runtime.KeepAlive(data)
-
-	var goret int
-
-	goret = int(cret)
-
-	return goret
-}
-
 // RegisterRTPHeaderExtensionSubClass is used to register a go subclass of GstRTPHeaderExtension. For this to work safely please implement the
 // virtual methods required by the implementation.
 func RegisterRTPHeaderExtensionSubClass[InstanceT RTPHeaderExtension](
@@ -167,18 +167,6 @@ func _goglib_gstrtp1_RTPHeaderExtension_get_supported_flags(carg0 *C.GstRTPHeade
 	return fn(carg0)
 }
 
-//export _goglib_gstrtp1_RTPHeaderExtension_read
-func _goglib_gstrtp1_RTPHeaderExtension_read(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionFlags, carg2 *C.guint8, carg3 C.gsize, carg4 *C.GstBuffer) (cret C.gboolean) {
-	var fn func(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionFlags, carg2 *C.guint8, carg3 C.gsize, carg4 *C.GstBuffer) (cret C.gboolean)
-	{
-		fn = classdata.LoadVirtualMethodFromInstance(unsafe.Pointer(carg0), "_goglib_gstrtp1_RTPHeaderExtension_read").(func(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionFlags, carg2 *C.guint8, carg3 C.gsize, carg4 *C.GstBuffer) (cret C.gboolean))
-		if fn == nil {
-			panic("_goglib_gstrtp1_RTPHeaderExtension_read: no function pointer found")
-		}
-	}
-	return fn(carg0, carg1, carg2, carg3, carg4)
-}
-
 //export _goglib_gstrtp1_RTPHeaderExtension_set_attributes
 func _goglib_gstrtp1_RTPHeaderExtension_set_attributes(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionDirection, carg2 *C.gchar) (cret C.gboolean) {
 	var fn func(carg0 *C.GstRTPHeaderExtension, carg1 C.GstRTPHeaderExtensionDirection, carg2 *C.gchar) (cret C.gboolean)
@@ -227,15 +215,3 @@ func _goglib_gstrtp1_RTPHeaderExtension_update_non_rtp_src_caps(carg0 *C.GstRTPH
 	return fn(carg0, carg1)
 }
 
-//export _goglib_gstrtp1_RTPHeaderExtension_write
-func _goglib_gstrtp1_RTPHeaderExtension_write(carg0 *C.GstRTPHeaderExtension, carg1 *C.GstBuffer, carg2 C.GstRTPHeaderExtensionFlags, carg3 *C.GstBuffer, carg4 *C.guint8, carg5 C.gsize) (cret C.gssize) {
-	var fn func(carg0 *C.GstRTPHeaderExtension, carg1 *C.GstBuffer, carg2 C.GstRTPHeaderExtensionFlags, carg3 *C.GstBuffer, carg4 *C.guint8, carg5 C.gsize) (cret C.gssize)
-	{
-		fn = classdata.LoadVirtualMethodFromInstance(unsafe.Pointer(carg0), "_goglib_gstrtp1_RTPHeaderExtension_write").(func(carg0 *C.GstRTPHeaderExtension, carg1 *C.GstBuffer, carg2 C.GstRTPHeaderExtensionFlags, carg3 *C.GstBuffer, carg4 *C.guint8, carg5 C.gsize) (cret C.gssize))
-		if fn == nil {
-			panic("_goglib_gstrtp1_RTPHeaderExtension_write: no function pointer found")
-		}
-	}
-	return fn(carg0, carg1, carg2, carg3, carg4, carg5)
-}
-
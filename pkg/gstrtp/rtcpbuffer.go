@@ -0,0 +1,154 @@
+package gstrtp
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+import "C"
+
+// ErrRTCPMap is returned when a buffer cannot be mapped as RTCP buffer.
+var ErrRTCPMap = errors.New("could not map RTCP buffer")
+
+// WithRTCPBuffer maps the buffer as RTCP buffer, calls fn and unmaps the buffer afterwards.
+// The [RTCPBuffer] and the packets obtained from it must not be used after fn returned.
+func WithRTCPBuffer(buffer *gst.Buffer, flags gst.MapFlags, fn func(*RTCPBuffer) error) error {
+	rtcp := UnsafeRTCPBufferFromGlibBorrow(unsafe.Pointer(C.g_malloc0(C.sizeof_GstRTCPBuffer)))
+	defer C.g_free(C.gpointer(UnsafeRTCPBufferToGlibNone(rtcp)))
+
+	if !RTCPBufferMap(buffer, flags, rtcp) {
+		return ErrRTCPMap
+	}
+	defer rtcp.Unmap()
+
+	return fn(rtcp)
+}
+
+// maxRTCPCompoundSize is the largest buffer [BuildRTCPCompound] allocates
+const maxRTCPCompoundSize = 1 << 20
+
+// withRTCPPacket allocates a packet cursor, calls fn and frees the cursor afterwards
+func withRTCPPacket(fn func(*RTCPPacket) error) error {
+	packet := UnsafeRTCPPacketFromGlibBorrow(unsafe.Pointer(C.g_malloc0(C.sizeof_GstRTCPPacket)))
+	defer C.g_free(C.gpointer(UnsafeRTCPPacketToGlibNone(packet)))
+
+	return fn(packet)
+}
+
+// Packets returns an iterator over the decoded packets of the mapped compound packet. The
+// decoded values are copies and stay valid after the buffer was unmapped. A packet that cannot
+// be decoded is yielded with an error and ends the iteration, this includes trailing data that
+// is not a valid packet.
+//
+//	for packet, err := range rtcp.Packets() {
+//		...
+//	}
+func (rtcp *RTCPBuffer) Packets() iter.Seq2[RTCPPacketValue, error] {
+	return func(yield func(RTCPPacketValue, error) bool) {
+		_ = withRTCPPacket(func(packet *RTCPPacket) error {
+			size := int(rtcp.native._map.size)
+			next := 0
+
+			// the cursor stops both at the end of the buffer and at a malformed header, the
+			// offset of the next packet tells them apart
+			for ok := rtcp.GetFirstPacket(packet); ok; ok = packet.MoveToNext() {
+				next = int(packet.native.offset) + int(packet.GetLength())*4 + 4
+				if next > size {
+					yield(nil, fmt.Errorf("%w: packet at offset %d exceeds the buffer", ErrInvalidRTCP, packet.native.offset))
+
+					return nil
+				}
+
+				value, err := decodeRTCPPacket(packet)
+				if !yield(value, err) || err != nil {
+					return nil
+				}
+			}
+
+			if next < size {
+				yield(nil, fmt.Errorf("%w: malformed packet at offset %d", ErrInvalidRTCP, next))
+			}
+
+			return nil
+		})
+	}
+}
+
+// DecodeRTCPBuffer decodes all packets of the compound RTCP packet in the buffer.
+func DecodeRTCPBuffer(buffer *gst.Buffer) ([]RTCPPacketValue, error) {
+	var packets []RTCPPacketValue
+
+	err := WithRTCPBuffer(buffer, gst.MapRead, func(rtcp *RTCPBuffer) error {
+		for packet, err := range rtcp.Packets() {
+			if err != nil {
+				return err
+			}
+
+			packets = append(packets, packet)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return packets, nil
+}
+
+// BuildRTCPCompound adds the packets to a new buffer containing a compound RTCP packet.
+// [RTCPUnknownPacket] values cannot be added.
+func BuildRTCPCompound(packets ...RTCPPacketValue) (*gst.Buffer, error) {
+	// the buffer is allocated with a maximum size, retry with a larger one if the packets do not fit
+	for mtu := 1500; ; mtu *= 2 {
+		buffer, err := buildRTCPCompound(mtu, packets)
+		if errors.Is(err, errRTCPNoSpace) && mtu < maxRTCPCompoundSize {
+			continue
+		}
+
+		return buffer, err
+	}
+}
+
+func buildRTCPCompound(mtu int, packets []RTCPPacketValue) (*gst.Buffer, error) {
+	buffer := gst.UnsafeBufferFromGlibFull(unsafe.Pointer(C.gst_rtcp_buffer_new(C.guint(mtu))))
+
+	// unmapping shrinks the buffer to the added packets
+	err := WithRTCPBuffer(buffer, gst.MapWrite, func(rtcp *RTCPBuffer) error {
+		return withRTCPPacket(func(packet *RTCPPacket) error {
+			for _, p := range packets {
+				if err := p.addRTCP(rtcp, packet); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// bufferFromBytes copies data into a new buffer
+func bufferFromBytes(data []byte) *gst.Buffer {
+	var cdata C.gconstpointer
+	if len(data) > 0 {
+		cdata = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+
+	cret := C.gst_buffer_new_memdup(cdata, C.gsize(len(data)))
+	runtime.KeepAlive(data)
+
+	return gst.UnsafeBufferFromGlibFull(unsafe.Pointer(cret))
+}
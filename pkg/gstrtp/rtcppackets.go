@@ -0,0 +1,899 @@
+package gstrtp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+import "C"
+
+// ErrInvalidRTCP is returned when RTCP data cannot be decoded or a packet cannot be serialized.
+var ErrInvalidRTCP = errors.New("invalid RTCP")
+
+// errRTCPNoSpace is returned when a packet does not fit into the buffer it is added to
+var errRTCPNoSpace = fmt.Errorf("%w: packets do not fit into the buffer", ErrInvalidRTCP)
+
+// RTCPPacketValue is a single decoded RTCP packet of a compound packet. It is implemented
+// by the packet structs of this package, e.g. [*RTCPSenderReport] or [*RTCPGenericNACK].
+type RTCPPacketValue interface {
+	// PacketType returns the RTCP packet type of the packet.
+	PacketType() RTCPType
+
+	// addRTCP adds the packet to the end of the mapped buffer, packet is used as cursor
+	addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error
+}
+
+// RTCPReportBlock is a reception report block of a sender or receiver report.
+type RTCPReportBlock struct {
+	SSRC          uint32
+	FractionLost  uint8
+	PacketsLost   int32
+	ExtHighestSeq uint32
+	Jitter        uint32
+	LSR           uint32
+	DLSR          uint32
+}
+
+// RTCPSenderReport is an SR packet.
+type RTCPSenderReport struct {
+	SSRC        uint32
+	NTPTime     uint64
+	RTPTime     uint32
+	PacketCount uint32
+	OctetCount  uint32
+	Reports     []RTCPReportBlock
+	// ProfileExtension contains the profile specific extension data after the report blocks.
+	ProfileExtension []byte
+}
+
+// RTCPReceiverReport is an RR packet.
+type RTCPReceiverReport struct {
+	SSRC    uint32
+	Reports []RTCPReportBlock
+	// ProfileExtension contains the profile specific extension data after the report blocks.
+	ProfileExtension []byte
+}
+
+// RTCPSDESItem is a single item of an SDES chunk, e.g. the CNAME.
+type RTCPSDESItem struct {
+	Type  RTCPSDESType
+	Value string
+}
+
+// RTCPSDESChunk contains the SDES items of a single source.
+type RTCPSDESChunk struct {
+	SSRC  uint32
+	Items []RTCPSDESItem
+}
+
+// RTCPSourceDescription is an SDES packet.
+type RTCPSourceDescription struct {
+	Chunks []RTCPSDESChunk
+}
+
+// RTCPGoodbye is a BYE packet.
+type RTCPGoodbye struct {
+	SSRCs  []uint32
+	Reason string
+}
+
+// RTCPApp is an APP packet.
+type RTCPApp struct {
+	Subtype uint8
+	SSRC    uint32
+	// Name is the four character name of the packet.
+	Name string
+	// Data is the application dependent data, its length must be a multiple of 4.
+	Data []byte
+}
+
+// RTCPGenericNACK is a generic NACK transport layer feedback packet (RFC 4585).
+type RTCPGenericNACK struct {
+	SenderSSRC uint32
+	MediaSSRC  uint32
+	// Lost contains the sequence numbers of the lost packets in ascending order.
+	Lost []uint16
+}
+
+// RTCPPictureLossIndication is a PLI payload specific feedback packet (RFC 4585).
+type RTCPPictureLossIndication struct {
+	SenderSSRC uint32
+	MediaSSRC  uint32
+}
+
+// RTCPFIREntry is a single request of a full intra request.
+type RTCPFIREntry struct {
+	SSRC uint32
+	Seq  uint8
+}
+
+// RTCPFullIntraRequest is a FIR payload specific feedback packet (RFC 5104).
+type RTCPFullIntraRequest struct {
+	SenderSSRC uint32
+	Entries    []RTCPFIREntry
+}
+
+// RTCPReceiverEstimatedMaxBitrate is a REMB application layer feedback packet
+// (draft-alvestrand-rmcat-remb).
+type RTCPReceiverEstimatedMaxBitrate struct {
+	SenderSSRC uint32
+	// Bitrate is the estimated maximum bitrate in bits per second.
+	Bitrate uint64
+	SSRCs   []uint32
+}
+
+// RTCPTWCCPacket is the feedback of a single packet in a transport-wide congestion control packet.
+type RTCPTWCCPacket struct {
+	Seq      uint16
+	Received bool
+	// Delta is the receive time of the packet relative to the previous received packet, or to the
+	// reference time for the first received packet. It has a resolution of 250µs.
+	Delta time.Duration
+}
+
+// RTCPTransportWideCC is a transport-wide congestion control feedback packet
+// (draft-holmer-rmcat-transport-wide-cc-extensions).
+type RTCPTransportWideCC struct {
+	SenderSSRC uint32
+	MediaSSRC  uint32
+	// ReferenceTime has a resolution of 64ms.
+	ReferenceTime time.Duration
+	FeedbackCount uint8
+	// Packets contains the feedback for consecutive transport-wide sequence numbers, starting
+	// at the base sequence number.
+	Packets []RTCPTWCCPacket
+}
+
+// RTCPFeedback is a transport layer or payload specific feedback packet that is not decoded
+// into one of the specific feedback types.
+type RTCPFeedback struct {
+	// Type is either [RtcpTypeRtpfb] or [RtcpTypePsfb].
+	Type       RTCPType
+	FBType     RTCPFBType
+	SenderSSRC uint32
+	MediaSSRC  uint32
+	// FCI is the feedback control information, its length must be a multiple of 4.
+	FCI []byte
+}
+
+// RTCPUnknownPacket is a packet of a type that is not decoded, e.g. XR. Unknown packets cannot be
+// added to a buffer with [BuildRTCPCompound].
+type RTCPUnknownPacket struct {
+	Type RTCPType
+	// Count is the value of the five bit count field of the header.
+	Count uint8
+	// Data is the packet without the header, its length must be a multiple of 4.
+	Data []byte
+}
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPSenderReport) PacketType() RTCPType { return RtcpTypeSr }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPReceiverReport) PacketType() RTCPType { return RtcpTypeRr }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPSourceDescription) PacketType() RTCPType { return RtcpTypeSdes }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPGoodbye) PacketType() RTCPType { return RtcpTypeBye }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPApp) PacketType() RTCPType { return RtcpTypeApp }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPGenericNACK) PacketType() RTCPType { return RtcpTypeRtpfb }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPPictureLossIndication) PacketType() RTCPType { return RtcpTypePsfb }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPFullIntraRequest) PacketType() RTCPType { return RtcpTypePsfb }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPReceiverEstimatedMaxBitrate) PacketType() RTCPType { return RtcpTypePsfb }
+
+// PacketType implements [RTCPPacketValue].
+func (*RTCPTransportWideCC) PacketType() RTCPType { return RtcpTypeRtpfb }
+
+// PacketType implements [RTCPPacketValue].
+func (p *RTCPFeedback) PacketType() RTCPType { return p.Type }
+
+// PacketType implements [RTCPPacketValue].
+func (p *RTCPUnknownPacket) PacketType() RTCPType { return p.Type }
+
+// decodeRTCPPacket decodes the packet the cursor points to
+func decodeRTCPPacket(packet *RTCPPacket) (RTCPPacketValue, error) {
+	typ := packet.GetType()
+
+	var value RTCPPacketValue
+	var err error
+
+	switch typ {
+	case RtcpTypeSr:
+		value, err = decodeSenderReport(packet)
+	case RtcpTypeRr:
+		value, err = decodeReceiverReport(packet)
+	case RtcpTypeSdes:
+		value = decodeSourceDescription(packet)
+	case RtcpTypeBye:
+		value, err = decodeGoodbye(packet)
+	case RtcpTypeApp:
+		value, err = decodeApp(packet)
+	case RtcpTypeRtpfb, RtcpTypePsfb:
+		value, err = decodeFeedback(packet)
+	default:
+		value = &RTCPUnknownPacket{
+			Type:  typ,
+			Count: packet.GetCount(),
+			Data:  cloneBytes(packet.payload()),
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidRTCP, typ, err)
+	}
+
+	return value, nil
+}
+
+// payload returns the packet without the header, it is only valid while the buffer is mapped
+func (packet *RTCPPacket) payload() []byte {
+	p := packet.native
+	start := int(p.offset) + 4
+
+	return unsafe.Slice((*byte)(unsafe.Pointer(p.rtcp._map.data)), int(p.rtcp._map.size))[start : start+int(p.length)*4]
+}
+
+// checkLength returns an error if the packet is shorter than the given number of 32 bit words
+// after the header. The getters of GstRTCPPacket do not check the length themselves.
+func checkLength(packet *RTCPPacket, words int, what string) error {
+	if int(packet.GetLength()) < words {
+		return fmt.Errorf("short %s", what)
+	}
+
+	return nil
+}
+
+// addPacket adds an empty packet of the given type to the buffer
+func addPacket(rtcp *RTCPBuffer, typ RTCPType, packet *RTCPPacket) error {
+	if !rtcp.AddPacket(typ, packet) {
+		return errRTCPNoSpace
+	}
+
+	return nil
+}
+
+func cloneBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return append([]byte(nil), b...)
+}
+
+// cBytes copies size bytes at data, which is borrowed from the mapped buffer
+func cBytes(data *C.guint8, size int) []byte {
+	if data == nil || size == 0 {
+		return nil
+	}
+
+	return cloneBytes(unsafe.Slice((*byte)(unsafe.Pointer(data)), size))
+}
+
+// padTo4 pads b with zeros to a multiple of 4 bytes
+func padTo4(b []byte) []byte {
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+
+	return b
+}
+
+func decodeReportBlocks(packet *RTCPPacket) []RTCPReportBlock {
+	var blocks []RTCPReportBlock
+
+	for i := range packet.GetRbCount() {
+		ssrc, fractionLost, packetsLost, extHighestSeq, jitter, lsr, dlsr := packet.GetRb(i)
+
+		blocks = append(blocks, RTCPReportBlock{
+			SSRC:          ssrc,
+			FractionLost:  fractionLost,
+			PacketsLost:   packetsLost,
+			ExtHighestSeq: extHighestSeq,
+			Jitter:        jitter,
+			LSR:           lsr,
+			DLSR:          dlsr,
+		})
+	}
+
+	return blocks
+}
+
+func addReportBlocks(packet *RTCPPacket, blocks []RTCPReportBlock) error {
+	if len(blocks) > 31 {
+		return fmt.Errorf("%w: %d report blocks exceed 31", ErrInvalidRTCP, len(blocks))
+	}
+
+	for _, rb := range blocks {
+		if !packet.AddRb(rb.SSRC, rb.FractionLost, rb.PacketsLost, rb.ExtHighestSeq, rb.Jitter, rb.LSR, rb.DLSR) {
+			return errRTCPNoSpace
+		}
+	}
+
+	return nil
+}
+
+func decodeProfileSpecificExt(packet *RTCPPacket) []byte {
+	var data *C.guint8
+	var length C.guint
+
+	if C.gst_rtcp_packet_get_profile_specific_ext(packet.native, &data, &length) == 0 {
+		return nil
+	}
+
+	return cBytes(data, int(packet.GetProfileSpecificExtLength())*4)
+}
+
+func addProfileSpecificExt(packet *RTCPPacket, ext []byte) error {
+	if len(ext) == 0 {
+		return nil
+	}
+
+	if len(ext)%4 != 0 {
+		return fmt.Errorf("%w: profile extension length %d is not a multiple of 4", ErrInvalidRTCP, len(ext))
+	}
+
+	if !packet.AddProfileSpecificExt(ext) {
+		return errRTCPNoSpace
+	}
+
+	return nil
+}
+
+func decodeSenderReport(packet *RTCPPacket) (*RTCPSenderReport, error) {
+	if err := checkLength(packet, 6+int(packet.GetCount())*6, "report blocks"); err != nil {
+		return nil, err
+	}
+
+	ssrc, ntpTime, rtpTime, packetCount, octetCount := packet.SrGetSenderInfo()
+
+	return &RTCPSenderReport{
+		SSRC:             ssrc,
+		NTPTime:          ntpTime,
+		RTPTime:          rtpTime,
+		PacketCount:      packetCount,
+		OctetCount:       octetCount,
+		Reports:          decodeReportBlocks(packet),
+		ProfileExtension: decodeProfileSpecificExt(packet),
+	}, nil
+}
+
+func (p *RTCPSenderReport) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if err := addPacket(rtcp, RtcpTypeSr, packet); err != nil {
+		return err
+	}
+
+	packet.SrSetSenderInfo(p.SSRC, p.NTPTime, p.RTPTime, p.PacketCount, p.OctetCount)
+
+	if err := addReportBlocks(packet, p.Reports); err != nil {
+		return err
+	}
+
+	return addProfileSpecificExt(packet, p.ProfileExtension)
+}
+
+func decodeReceiverReport(packet *RTCPPacket) (*RTCPReceiverReport, error) {
+	if err := checkLength(packet, 1+int(packet.GetCount())*6, "report blocks"); err != nil {
+		return nil, err
+	}
+
+	return &RTCPReceiverReport{
+		SSRC:             packet.RrGetSsrc(),
+		Reports:          decodeReportBlocks(packet),
+		ProfileExtension: decodeProfileSpecificExt(packet),
+	}, nil
+}
+
+func (p *RTCPReceiverReport) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if err := addPacket(rtcp, RtcpTypeRr, packet); err != nil {
+		return err
+	}
+
+	packet.RrSetSsrc(p.SSRC)
+
+	if err := addReportBlocks(packet, p.Reports); err != nil {
+		return err
+	}
+
+	return addProfileSpecificExt(packet, p.ProfileExtension)
+}
+
+func decodeSourceDescription(packet *RTCPPacket) *RTCPSourceDescription {
+	sdes := &RTCPSourceDescription{}
+
+	for ok := packet.SdesFirstItem(); ok; ok = packet.SdesNextItem() {
+		chunk := RTCPSDESChunk{SSRC: packet.SdesGetSsrc()}
+
+		for ok := packet.SdesFirstEntry(); ok; ok = packet.SdesNextEntry() {
+			var typ C.GstRTCPSDESType
+			var length C.guint8
+			var data *C.guint8
+
+			if C.gst_rtcp_packet_sdes_get_entry(packet.native, &typ, &length, &data) == 0 {
+				break
+			}
+
+			chunk.Items = append(chunk.Items, RTCPSDESItem{
+				Type:  RTCPSDESType(typ),
+				Value: string(unsafe.Slice((*byte)(unsafe.Pointer(data)), int(length))),
+			})
+		}
+
+		sdes.Chunks = append(sdes.Chunks, chunk)
+	}
+
+	return sdes
+}
+
+func (p *RTCPSourceDescription) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if len(p.Chunks) > 31 {
+		return fmt.Errorf("%w: sdes: %d chunks exceed 31", ErrInvalidRTCP, len(p.Chunks))
+	}
+
+	if err := addPacket(rtcp, RtcpTypeSdes, packet); err != nil {
+		return err
+	}
+
+	for _, chunk := range p.Chunks {
+		if !packet.SdesAddItem(chunk.SSRC) {
+			return errRTCPNoSpace
+		}
+
+		for _, item := range chunk.Items {
+			if item.Type <= RtcpSdesEnd || item.Type > 255 {
+				return fmt.Errorf("%w: sdes: invalid item type %d", ErrInvalidRTCP, item.Type)
+			}
+
+			if len(item.Value) > 255 {
+				return fmt.Errorf("%w: sdes: item %s longer than 255 bytes", ErrInvalidRTCP, item.Type)
+			}
+
+			if !packet.SdesAddEntry(item.Type, []byte(item.Value)) {
+				return errRTCPNoSpace
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeGoodbye(packet *RTCPPacket) (*RTCPGoodbye, error) {
+	if err := checkLength(packet, int(packet.GetCount()), "ssrc list"); err != nil {
+		return nil, err
+	}
+
+	bye := &RTCPGoodbye{Reason: packet.ByeGetReason()}
+
+	for i := range packet.ByeGetSsrcCount() {
+		bye.SSRCs = append(bye.SSRCs, packet.ByeGetNthSsrc(i))
+	}
+
+	return bye, nil
+}
+
+func (p *RTCPGoodbye) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if len(p.SSRCs) > 31 {
+		return fmt.Errorf("%w: bye: %d ssrcs exceed 31", ErrInvalidRTCP, len(p.SSRCs))
+	}
+
+	if len(p.Reason) > 255 {
+		return fmt.Errorf("%w: bye: reason longer than 255 bytes", ErrInvalidRTCP)
+	}
+
+	if err := addPacket(rtcp, RtcpTypeBye, packet); err != nil {
+		return err
+	}
+
+	if len(p.SSRCs) > 0 && !packet.ByeAddSsrcs(p.SSRCs) {
+		return errRTCPNoSpace
+	}
+
+	if p.Reason != "" && !packet.ByeSetReason(p.Reason) {
+		return errRTCPNoSpace
+	}
+
+	return nil
+}
+
+func decodeApp(packet *RTCPPacket) (*RTCPApp, error) {
+	if err := checkLength(packet, 2, "app header"); err != nil {
+		return nil, err
+	}
+
+	name := C.gst_rtcp_packet_app_get_name(packet.native)
+
+	return &RTCPApp{
+		Subtype: packet.AppGetSubtype(),
+		SSRC:    packet.AppGetSsrc(),
+		Name:    C.GoStringN((*C.char)(unsafe.Pointer(name)), 4),
+		Data:    cBytes(C.gst_rtcp_packet_app_get_data(packet.native), int(packet.AppGetDataLength())*4),
+	}, nil
+}
+
+func (p *RTCPApp) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if len(p.Name) != 4 {
+		return fmt.Errorf("%w: app: name %q must have 4 characters", ErrInvalidRTCP, p.Name)
+	}
+
+	if p.Subtype > 31 {
+		return fmt.Errorf("%w: app: subtype %d exceeds 31", ErrInvalidRTCP, p.Subtype)
+	}
+
+	if len(p.Data)%4 != 0 || len(p.Data)/4 > math.MaxUint16 {
+		return fmt.Errorf("%w: app: invalid data length %d", ErrInvalidRTCP, len(p.Data))
+	}
+
+	if err := addPacket(rtcp, RtcpTypeApp, packet); err != nil {
+		return err
+	}
+
+	packet.AppSetSubtype(p.Subtype)
+	packet.AppSetSsrc(p.SSRC)
+	packet.AppSetName(p.Name)
+
+	if len(p.Data) == 0 {
+		return nil
+	}
+
+	if !packet.AppSetDataLength(uint16(len(p.Data) / 4)) {
+		return errRTCPNoSpace
+	}
+
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(C.gst_rtcp_packet_app_get_data(packet.native))), len(p.Data)), p.Data)
+
+	return nil
+}
+
+// addFeedback adds a feedback packet with the feedback control information fci
+func addFeedback(rtcp *RTCPBuffer, packet *RTCPPacket, typ RTCPType, fbType RTCPFBType, sender, media uint32, fci []byte) error {
+	if len(fci)%4 != 0 || len(fci)/4 > math.MaxUint16-2 {
+		return fmt.Errorf("%w: %s: invalid fci length %d", ErrInvalidRTCP, typ, len(fci))
+	}
+
+	if err := addPacket(rtcp, typ, packet); err != nil {
+		return err
+	}
+
+	packet.FbSetType(fbType)
+	packet.FbSetSenderSsrc(sender)
+	packet.FbSetMediaSsrc(media)
+
+	if len(fci) == 0 {
+		return nil
+	}
+
+	if !packet.FbSetFciLength(uint16(len(fci) / 4)) {
+		return errRTCPNoSpace
+	}
+
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(C.gst_rtcp_packet_fb_get_fci(packet.native))), len(fci)), fci)
+
+	return nil
+}
+
+func decodeFeedback(packet *RTCPPacket) (RTCPPacketValue, error) {
+	if err := checkLength(packet, 2, "feedback header"); err != nil {
+		return nil, err
+	}
+
+	typ := packet.GetType()
+	fbType := packet.FbGetType()
+	sender := packet.FbGetSenderSsrc()
+	media := packet.FbGetMediaSsrc()
+
+	// the fci is only valid while the buffer is mapped
+	fci := unsafe.Slice((*byte)(unsafe.Pointer(C.gst_rtcp_packet_fb_get_fci(packet.native))), int(packet.FbGetFciLength())*4)
+
+	switch {
+	case typ == RtcpTypeRtpfb && fbType == RtcpRtpfbTypeNack:
+		return decodeGenericNACK(sender, media, fci)
+	case typ == RtcpTypeRtpfb && fbType == RtcpRtpfbTypeTwcc:
+		return decodeTransportWideCC(sender, media, fci)
+	case typ == RtcpTypePsfb && fbType == RtcpPsfbTypePli:
+		return &RTCPPictureLossIndication{SenderSSRC: sender, MediaSSRC: media}, nil
+	case typ == RtcpTypePsfb && fbType == RtcpPsfbTypeFir:
+		return decodeFullIntraRequest(sender, fci)
+	case typ == RtcpTypePsfb && fbType == RtcpPsfbTypeAfb && len(fci) >= 8 && string(fci[:4]) == "REMB":
+		return decodeREMB(sender, fci)
+	}
+
+	return &RTCPFeedback{
+		Type:       typ,
+		FBType:     fbType,
+		SenderSSRC: sender,
+		MediaSSRC:  media,
+		FCI:        cloneBytes(fci),
+	}, nil
+}
+
+func (p *RTCPFeedback) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if p.Type != RtcpTypeRtpfb && p.Type != RtcpTypePsfb {
+		return fmt.Errorf("%w: %s is not a feedback packet type", ErrInvalidRTCP, p.Type)
+	}
+
+	return addFeedback(rtcp, packet, p.Type, p.FBType, p.SenderSSRC, p.MediaSSRC, p.FCI)
+}
+
+func (p *RTCPUnknownPacket) addRTCP(*RTCPBuffer, *RTCPPacket) error {
+	return fmt.Errorf("%w: cannot build %s packets", ErrInvalidRTCP, p.Type)
+}
+
+func decodeGenericNACK(sender, media uint32, fci []byte) (*RTCPGenericNACK, error) {
+	nack := &RTCPGenericNACK{SenderSSRC: sender, MediaSSRC: media}
+
+	for ; len(fci) >= 4; fci = fci[4:] {
+		pid := binary.BigEndian.Uint16(fci)
+		blp := binary.BigEndian.Uint16(fci[2:])
+
+		nack.Lost = append(nack.Lost, pid)
+
+		for i := range uint16(16) {
+			if blp&(1<<i) != 0 {
+				nack.Lost = append(nack.Lost, pid+i+1)
+			}
+		}
+	}
+
+	return nack, nil
+}
+
+func (p *RTCPGenericNACK) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	var fci []byte
+
+	for i := 0; i < len(p.Lost); {
+		pid := p.Lost[i]
+		blp := uint16(0)
+
+		for i++; i < len(p.Lost); i++ {
+			// the difference wraps around together with the sequence numbers
+			d := p.Lost[i] - pid
+			if d == 0 || d > 16 {
+				break
+			}
+
+			blp |= 1 << (d - 1)
+		}
+
+		fci = binary.BigEndian.AppendUint16(fci, pid)
+		fci = binary.BigEndian.AppendUint16(fci, blp)
+	}
+
+	return addFeedback(rtcp, packet, RtcpTypeRtpfb, RtcpRtpfbTypeNack, p.SenderSSRC, p.MediaSSRC, fci)
+}
+
+func (p *RTCPPictureLossIndication) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	return addFeedback(rtcp, packet, RtcpTypePsfb, RtcpPsfbTypePli, p.SenderSSRC, p.MediaSSRC, nil)
+}
+
+func decodeFullIntraRequest(sender uint32, fci []byte) (*RTCPFullIntraRequest, error) {
+	if len(fci)%8 != 0 {
+		return nil, errors.New("invalid fir length")
+	}
+
+	fir := &RTCPFullIntraRequest{SenderSSRC: sender}
+
+	for ; len(fci) >= 8; fci = fci[8:] {
+		fir.Entries = append(fir.Entries, RTCPFIREntry{
+			SSRC: binary.BigEndian.Uint32(fci),
+			Seq:  fci[4],
+		})
+	}
+
+	return fir, nil
+}
+
+func (p *RTCPFullIntraRequest) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	var fci []byte
+
+	for _, e := range p.Entries {
+		fci = binary.BigEndian.AppendUint32(fci, e.SSRC)
+		fci = append(fci, e.Seq, 0, 0, 0)
+	}
+
+	// the media source ssrc is not used for FIR and must be 0
+	return addFeedback(rtcp, packet, RtcpTypePsfb, RtcpPsfbTypeFir, p.SenderSSRC, 0, fci)
+}
+
+func decodeREMB(sender uint32, fci []byte) (*RTCPReceiverEstimatedMaxBitrate, error) {
+	num := int(fci[4])
+	exp := fci[5] >> 2
+	mantissa := uint64(fci[5]&0x3)<<16 | uint64(fci[6])<<8 | uint64(fci[7])
+
+	if len(fci) < 8+num*4 {
+		return nil, errors.New("short remb ssrc list")
+	}
+
+	remb := &RTCPReceiverEstimatedMaxBitrate{
+		SenderSSRC: sender,
+		Bitrate:    mantissa << exp,
+	}
+
+	for i := range num {
+		remb.SSRCs = append(remb.SSRCs, binary.BigEndian.Uint32(fci[8+i*4:]))
+	}
+
+	return remb, nil
+}
+
+func (p *RTCPReceiverEstimatedMaxBitrate) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if len(p.SSRCs) > 255 {
+		return fmt.Errorf("%w: remb: more than 255 ssrcs", ErrInvalidRTCP)
+	}
+
+	// the bitrate is encoded with an 18 bit mantissa and a 6 bit exponent
+	exp := max(bits.Len64(p.Bitrate)-18, 0)
+	mantissa := p.Bitrate >> exp
+
+	fci := []byte("REMB")
+	fci = append(fci, byte(len(p.SSRCs)), byte(exp<<2)|byte(mantissa>>16), byte(mantissa>>8), byte(mantissa))
+
+	for _, ssrc := range p.SSRCs {
+		fci = binary.BigEndian.AppendUint32(fci, ssrc)
+	}
+
+	// the media source ssrc is not used for REMB and must be 0
+	return addFeedback(rtcp, packet, RtcpTypePsfb, RtcpPsfbTypeAfb, p.SenderSSRC, 0, fci)
+}
+
+// packet status symbols of transport-wide cc feedback
+const (
+	twccNotReceived = 0
+	twccSmallDelta  = 1
+	twccLargeDelta  = 2
+
+	twccDeltaUnit     = 250 * time.Microsecond
+	twccReferenceUnit = 64 * time.Millisecond
+)
+
+func decodeTransportWideCC(sender, media uint32, fci []byte) (*RTCPTransportWideCC, error) {
+	if len(fci) < 8 {
+		return nil, errors.New("short twcc header")
+	}
+
+	baseSeq := binary.BigEndian.Uint16(fci)
+	statusCount := int(binary.BigEndian.Uint16(fci[2:]))
+	// the reference time is a signed 24 bit integer
+	reference := int32(binary.BigEndian.Uint32(fci[4:])) >> 8
+
+	twcc := &RTCPTransportWideCC{
+		SenderSSRC:    sender,
+		MediaSSRC:     media,
+		ReferenceTime: time.Duration(reference) * twccReferenceUnit,
+		FeedbackCount: fci[7],
+	}
+
+	pos := 8
+	symbols := make([]byte, 0, statusCount)
+
+	for len(symbols) < statusCount {
+		if pos+2 > len(fci) {
+			return nil, errors.New("short packet chunks")
+		}
+
+		chunk := binary.BigEndian.Uint16(fci[pos:])
+		pos += 2
+
+		switch {
+		case chunk&0x8000 == 0:
+			// run length chunk
+			symbol := byte(chunk>>13) & 0x3
+			for range min(int(chunk&0x1fff), statusCount-len(symbols)) {
+				symbols = append(symbols, symbol)
+			}
+		case chunk&0x4000 == 0:
+			// status vector chunk with 14 one bit symbols
+			for i := 13; i >= 0 && len(symbols) < statusCount; i-- {
+				symbols = append(symbols, byte(chunk>>i)&0x1)
+			}
+		default:
+			// status vector chunk with 7 two bit symbols
+			for i := 6; i >= 0 && len(symbols) < statusCount; i-- {
+				symbols = append(symbols, byte(chunk>>(2*i))&0x3)
+			}
+		}
+	}
+
+	for i, symbol := range symbols {
+		packet := RTCPTWCCPacket{Seq: baseSeq + uint16(i)}
+
+		switch symbol {
+		case twccSmallDelta:
+			if pos+1 > len(fci) {
+				return nil, errors.New("short receive deltas")
+			}
+
+			packet.Received = true
+			packet.Delta = time.Duration(fci[pos]) * twccDeltaUnit
+			pos++
+		case twccLargeDelta:
+			if pos+2 > len(fci) {
+				return nil, errors.New("short receive deltas")
+			}
+
+			packet.Received = true
+			packet.Delta = time.Duration(int16(binary.BigEndian.Uint16(fci[pos:]))) * twccDeltaUnit
+			pos += 2
+		}
+
+		twcc.Packets = append(twcc.Packets, packet)
+	}
+
+	return twcc, nil
+}
+
+func (p *RTCPTransportWideCC) addRTCP(rtcp *RTCPBuffer, packet *RTCPPacket) error {
+	if len(p.Packets) > math.MaxUint16 {
+		return fmt.Errorf("%w: twcc: too many packets", ErrInvalidRTCP)
+	}
+
+	var baseSeq uint16
+	if len(p.Packets) > 0 {
+		baseSeq = p.Packets[0].Seq
+	}
+
+	reference := int32(p.ReferenceTime / twccReferenceUnit)
+
+	fci := binary.BigEndian.AppendUint16(nil, baseSeq)
+	fci = binary.BigEndian.AppendUint16(fci, uint16(len(p.Packets)))
+	fci = binary.BigEndian.AppendUint32(fci, uint32(reference)<<8|uint32(p.FeedbackCount))
+
+	symbols := make([]byte, len(p.Packets))
+	var deltas []byte
+
+	for i, packet := range p.Packets {
+		if packet.Seq != baseSeq+uint16(i) {
+			return fmt.Errorf("%w: twcc: packets must have consecutive sequence numbers", ErrInvalidRTCP)
+		}
+
+		if !packet.Received {
+			continue
+		}
+
+		delta := packet.Delta / twccDeltaUnit
+
+		switch {
+		case delta >= 0 && delta <= math.MaxUint8:
+			symbols[i] = twccSmallDelta
+			deltas = append(deltas, byte(delta))
+		case delta >= math.MinInt16 && delta <= math.MaxInt16:
+			symbols[i] = twccLargeDelta
+			deltas = binary.BigEndian.AppendUint16(deltas, uint16(int16(delta)))
+		default:
+			return fmt.Errorf("%w: twcc: delta %v of packet %d out of range", ErrInvalidRTCP, packet.Delta, packet.Seq)
+		}
+	}
+
+	// all statuses are encoded in status vector chunks with 7 two bit symbols
+	for i := 0; i < len(symbols); i += 7 {
+		chunk := uint16(0xc000)
+
+		for j := 0; j < 7 && i+j < len(symbols); j++ {
+			chunk |= uint16(symbols[i+j]) << (2 * (6 - j))
+		}
+
+		fci = binary.BigEndian.AppendUint16(fci, chunk)
+	}
+
+	fci = padTo4(append(fci, deltas...))
+
+	return addFeedback(rtcp, packet, RtcpTypeRtpfb, RtcpRtpfbTypeTwcc, p.SenderSSRC, p.MediaSSRC, fci)
+}
@@ -0,0 +1,138 @@
+package gstrtp_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstrtp"
+)
+
+var testRTCPPackets = []gstrtp.RTCPPacketValue{
+	&gstrtp.RTCPSenderReport{
+		SSRC:        0x11223344,
+		NTPTime:     0x0102030405060708,
+		RTPTime:     90000,
+		PacketCount: 10,
+		OctetCount:  12000,
+		Reports: []gstrtp.RTCPReportBlock{
+			{SSRC: 0x55667788, FractionLost: 12, PacketsLost: -3, ExtHighestSeq: 70000, Jitter: 40, LSR: 1, DLSR: 2},
+		},
+	},
+	&gstrtp.RTCPSourceDescription{
+		Chunks: []gstrtp.RTCPSDESChunk{
+			{SSRC: 0x11223344, Items: []gstrtp.RTCPSDESItem{{Type: gstrtp.RtcpSdesCname, Value: "user@host"}}},
+			{SSRC: 0x55667788, Items: []gstrtp.RTCPSDESItem{{Type: gstrtp.RtcpSdesCname, Value: "ab"}, {Type: gstrtp.RtcpSdesTool, Value: "go-gst"}}},
+		},
+	},
+	&gstrtp.RTCPGenericNACK{SenderSSRC: 1, MediaSSRC: 2, Lost: []uint16{65534, 65535, 3, 40}},
+	&gstrtp.RTCPPictureLossIndication{SenderSSRC: 1, MediaSSRC: 2},
+	&gstrtp.RTCPFullIntraRequest{SenderSSRC: 1, Entries: []gstrtp.RTCPFIREntry{{SSRC: 2, Seq: 7}}},
+	&gstrtp.RTCPReceiverEstimatedMaxBitrate{SenderSSRC: 1, Bitrate: 1500000 &^ 0xff, SSRCs: []uint32{2, 3}},
+	&gstrtp.RTCPTransportWideCC{
+		SenderSSRC:    1,
+		MediaSSRC:     2,
+		ReferenceTime: -128 * time.Millisecond,
+		FeedbackCount: 9,
+		Packets: []gstrtp.RTCPTWCCPacket{
+			{Seq: 100, Received: true, Delta: 1 * time.Millisecond},
+			{Seq: 101},
+			{Seq: 102, Received: true, Delta: -500 * time.Microsecond},
+			{Seq: 103, Received: true, Delta: 200 * time.Millisecond},
+		},
+	},
+	&gstrtp.RTCPApp{Subtype: 3, SSRC: 1, Name: "TEST", Data: []byte{1, 2, 3, 4}},
+	&gstrtp.RTCPGoodbye{SSRCs: []uint32{0x11223344}, Reason: "bye"},
+}
+
+func TestRTCPBufferPackets(t *testing.T) {
+	gst.Init()
+
+	buf, err := gstrtp.BuildRTCPCompound(testRTCPPackets...)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	if !gstrtp.RTCPBufferValidate(buf) {
+		t.Fatal("built buffer is not a valid compound packet")
+	}
+
+	var types []gstrtp.RTCPType
+
+	err = gstrtp.WithRTCPBuffer(buf, gst.MapRead, func(rtcp *gstrtp.RTCPBuffer) error {
+		if count := rtcp.GetPacketCount(); count != uint(len(testRTCPPackets)) {
+			t.Errorf("unexpected packet count %d", count)
+		}
+
+		for packet, err := range rtcp.Packets() {
+			if err != nil {
+				return err
+			}
+
+			types = append(types, packet.PacketType())
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(types) != len(testRTCPPackets) {
+		t.Fatalf("unexpected packet types %v", types)
+	}
+
+	packets, err := gstrtp.DecodeRTCPBuffer(buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if !reflect.DeepEqual(packets, testRTCPPackets) {
+		t.Error("decoded packets differ from the original packets")
+	}
+}
+
+func TestRTCPBufferMalformed(t *testing.T) {
+	gst.Init()
+
+	buf, err := gstrtp.BuildRTCPCompound(testRTCPPackets...)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	// cut the last packet short
+	buf.SetSize(int(buf.GetSize()) - 4)
+
+	if _, err := gstrtp.DecodeRTCPBuffer(buf); !errors.Is(err, gstrtp.ErrInvalidRTCP) {
+		t.Fatalf("expected truncated buffer to fail, got %v", err)
+	}
+
+	var decoded int
+	var failed bool
+
+	err = gstrtp.WithRTCPBuffer(buf, gst.MapRead, func(rtcp *gstrtp.RTCPBuffer) error {
+		for _, err := range rtcp.Packets() {
+			if err != nil {
+				failed = true
+				continue
+			}
+
+			decoded++
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !failed || decoded != len(testRTCPPackets)-1 {
+		t.Errorf("expected %d packets and an error, got %d packets, error %t", len(testRTCPPackets)-1, decoded, failed)
+	}
+
+	if _, err := gstrtp.BuildRTCPCompound(&gstrtp.RTCPApp{Name: "TOOLONG"}); !errors.Is(err, gstrtp.ErrInvalidRTCP) {
+		t.Errorf("expected invalid app name to fail, got %v", err)
+	}
+}
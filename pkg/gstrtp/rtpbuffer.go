@@ -0,0 +1,318 @@
+package gstrtp
+
+import (
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+import "C"
+
+// ErrRTPMap is returned when a buffer cannot be mapped as RTP buffer.
+var ErrRTPMap = errors.New("could not map RTP buffer")
+
+// profiles of the RTP header extension
+const (
+	rtpOneByteExtensionProfile = 0xBEDE
+	rtpTwoByteExtensionProfile = 0x1000
+	rtpTwoByteExtensionMask    = 0xFFF0
+)
+
+// RTPHeaderExtensionElement is a single element of a one-byte or two-byte RTP
+// header extension (RFC 8285).
+type RTPHeaderExtensionElement struct {
+	ID   uint8
+	Data []byte
+}
+
+// RTPHeader is the decoded fixed header and header extension of an RTP packet.
+type RTPHeader struct {
+	Version     uint8
+	Padding     bool
+	Marker      bool
+	PayloadType uint8
+	Seq         uint16
+	Timestamp   uint32
+	SSRC        uint32
+	CSRCs       []uint32
+
+	// Extension is true if the packet has a header extension.
+	Extension bool
+	// ExtensionProfile is the 16 bit profile of the header extension, e.g. 0xBEDE for
+	// one-byte header extensions.
+	ExtensionProfile uint16
+	// ExtensionData is the raw data of the header extension.
+	ExtensionData []byte
+	// ExtensionElements are the decoded elements of a one-byte or two-byte header extension.
+	ExtensionElements []RTPHeaderExtensionElement
+}
+
+// ExtensionElement returns the data of the first header extension element with the given id.
+func (h *RTPHeader) ExtensionElement(id uint8) ([]byte, bool) {
+	for _, e := range h.ExtensionElements {
+		if e.ID == id {
+			return e.Data, true
+		}
+	}
+
+	return nil, false
+}
+
+// WithRTPBuffer maps the buffer as RTP buffer, calls fn and unmaps the buffer afterwards.
+// The [RTPBuffer] and all slices obtained from it, e.g. by [RTPBuffer.Payload], must not be
+// used after fn returned.
+func WithRTPBuffer(buffer *gst.Buffer, flags gst.MapFlags, fn func(*RTPBuffer) error) error {
+	cbuf := (*C.GstRTPBuffer)(C.g_malloc0(C.sizeof_GstRTPBuffer))
+	defer C.g_free(C.gpointer(cbuf))
+
+	ok := C.gst_rtp_buffer_map((*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer)), C.GstMapFlags(flags), cbuf)
+	runtime.KeepAlive(buffer)
+
+	if ok == 0 {
+		return ErrRTPMap
+	}
+
+	rtp := UnsafeRTPBufferFromGlibBorrow(unsafe.Pointer(cbuf))
+
+	defer func() {
+		rtp.Unmap()
+		// make accidental use after the scope fail instead of reading unmapped memory
+		rtp.native = nil
+	}()
+
+	return fn(rtp)
+}
+
+// Payload returns the payload of the packet. The slice references the mapped memory, so it is
+// only valid while the buffer is mapped and may only be written if the buffer was mapped writable.
+func (rtp *RTPBuffer) Payload() []byte {
+	var carg0 *C.GstRTPBuffer // in, none, converted
+
+	carg0 = (*C.GstRTPBuffer)(UnsafeRTPBufferToGlibNone(rtp))
+
+	data := C.gst_rtp_buffer_get_payload(carg0)
+	size := C.gst_rtp_buffer_get_payload_len(carg0)
+	runtime.KeepAlive(rtp)
+
+	if data == nil || size == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((*byte)(data), int(size))
+}
+
+// extensionData returns the profile and the data of the header extension. The slice references the mapped memory.
+func (rtp *RTPBuffer) extensionData() (uint16, []byte, bool) {
+	var carg0 *C.GstRTPBuffer // in, none, converted
+	var carg1 C.guint16       // out, none, casted
+	var carg2 C.gpointer      // out, none
+	var carg3 C.guint         // out, none, casted
+
+	carg0 = (*C.GstRTPBuffer)(UnsafeRTPBufferToGlibNone(rtp))
+
+	cret := C.gst_rtp_buffer_get_extension_data(carg0, &carg1, &carg2, &carg3)
+	runtime.KeepAlive(rtp)
+
+	if cret == 0 {
+		return 0, nil, false
+	}
+
+	if carg2 == nil || carg3 == 0 {
+		return uint16(carg1), nil, true
+	}
+
+	return uint16(carg1), unsafe.Slice((*byte)(carg2), int(carg3)*4), true
+}
+
+// Header decodes the header of the packet. The returned header is a copy and stays valid after
+// the buffer was unmapped.
+func (rtp *RTPBuffer) Header() RTPHeader {
+	h := RTPHeader{
+		Version:     rtp.GetVersion(),
+		Padding:     rtp.GetPadding(),
+		Marker:      rtp.GetMarker(),
+		PayloadType: rtp.GetPayloadType(),
+		Seq:         rtp.GetSeq(),
+		Timestamp:   rtp.GetTimestamp(),
+		SSRC:        rtp.GetSsrc(),
+	}
+
+	for i := range rtp.GetCsrcCount() {
+		h.CSRCs = append(h.CSRCs, rtp.GetCsrc(i))
+	}
+
+	profile, data, ok := rtp.extensionData()
+	if ok {
+		h.Extension = true
+		h.ExtensionProfile = profile
+		h.ExtensionData = append([]byte(nil), data...)
+		h.ExtensionElements = parseRTPHeaderExtensionElements(profile, h.ExtensionData)
+	}
+
+	return h
+}
+
+// parseRTPHeaderExtensionElements decodes the elements of a one-byte or two-byte header
+// extension. The returned elements reference data.
+func parseRTPHeaderExtensionElements(profile uint16, data []byte) []RTPHeaderExtensionElement {
+	var elements []RTPHeaderExtensionElement
+
+	switch {
+	case profile == rtpOneByteExtensionProfile:
+		for pos := 0; pos < len(data); {
+			id := data[pos] >> 4
+			length := int(data[pos]&0x0f) + 1
+
+			if id == 0 {
+				// padding
+				pos++
+				continue
+			}
+
+			if id == 15 || pos+1+length > len(data) {
+				// reserved id, stop parsing
+				break
+			}
+
+			elements = append(elements, RTPHeaderExtensionElement{
+				ID:   id,
+				Data: data[pos+1 : pos+1+length],
+			})
+
+			pos += 1 + length
+		}
+	case profile&rtpTwoByteExtensionMask == rtpTwoByteExtensionProfile:
+		for pos := 0; pos < len(data); {
+			id := data[pos]
+
+			if id == 0 {
+				// padding
+				pos++
+				continue
+			}
+
+			if pos+2 > len(data) || pos+2+int(data[pos+1]) > len(data) {
+				break
+			}
+
+			length := int(data[pos+1])
+
+			elements = append(elements, RTPHeaderExtensionElement{
+				ID:   id,
+				Data: data[pos+2 : pos+2+length],
+			})
+
+			pos += 2 + length
+		}
+	}
+
+	return elements
+}
+
+// AddExtensionOnebyteHeader wraps gst_rtp_buffer_add_extension_onebyte_header
+//
+// The id must be between 1 and 14 and data must have between 1 and 16 bytes.
+func (rtp *RTPBuffer) AddExtensionOnebyteHeader(id uint8, data []byte) bool {
+	var carg0 *C.GstRTPBuffer // in, none, converted
+	var carg1 C.guint8        // in, none, casted
+	var carg2 C.gconstpointer // in, none, array
+	var carg3 C.guint         // in, none, casted
+	var cret C.gboolean       // return
+
+	carg0 = (*C.GstRTPBuffer)(UnsafeRTPBufferToGlibNone(rtp))
+	carg1 = C.guint8(id)
+	if len(data) > 0 {
+		carg2 = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+	carg3 = C.guint(len(data))
+
+	cret = C.gst_rtp_buffer_add_extension_onebyte_header(carg0, carg1, carg2, carg3)
+	runtime.KeepAlive(rtp)
+	runtime.KeepAlive(data)
+
+	return cret != 0
+}
+
+// AddExtensionTwobytesHeader wraps gst_rtp_buffer_add_extension_twobytes_header
+//
+// The id must be between 1 and 255 and data must have at most 255 bytes.
+func (rtp *RTPBuffer) AddExtensionTwobytesHeader(appbits uint8, id uint8, data []byte) bool {
+	var carg0 *C.GstRTPBuffer // in, none, converted
+	var carg1 C.guint8        // in, none, casted
+	var carg2 C.guint8        // in, none, casted
+	var carg3 C.gconstpointer // in, none, array
+	var carg4 C.guint         // in, none, casted
+	var cret C.gboolean       // return
+
+	carg0 = (*C.GstRTPBuffer)(UnsafeRTPBufferToGlibNone(rtp))
+	carg1 = C.guint8(appbits)
+	carg2 = C.guint8(id)
+	if len(data) > 0 {
+		carg3 = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+	carg4 = C.guint(len(data))
+
+	cret = C.gst_rtp_buffer_add_extension_twobytes_header(carg0, carg1, carg2, carg3, carg4)
+	runtime.KeepAlive(rtp)
+	runtime.KeepAlive(data)
+
+	return cret != 0
+}
+
+// ParseRTPHeader decodes the RTP header at the start of data without mapping a buffer. It
+// returns the header and its length including CSRCs and the header extension.
+func ParseRTPHeader(data []byte) (RTPHeader, int, error) {
+	if len(data) < 12 {
+		return RTPHeader{}, 0, errors.New("rtp: short header")
+	}
+
+	h := RTPHeader{
+		Version:     data[0] >> 6,
+		Padding:     data[0]&0x20 != 0,
+		Extension:   data[0]&0x10 != 0,
+		Marker:      data[1]&0x80 != 0,
+		PayloadType: data[1] & 0x7f,
+		Seq:         binary.BigEndian.Uint16(data[2:]),
+		Timestamp:   binary.BigEndian.Uint32(data[4:]),
+		SSRC:        binary.BigEndian.Uint32(data[8:]),
+	}
+
+	pos := 12
+	csrcCount := int(data[0] & 0x0f)
+
+	if len(data) < pos+csrcCount*4 {
+		return RTPHeader{}, 0, errors.New("rtp: short csrc list")
+	}
+
+	for i := range csrcCount {
+		h.CSRCs = append(h.CSRCs, binary.BigEndian.Uint32(data[pos+i*4:]))
+	}
+
+	pos += csrcCount * 4
+
+	if h.Extension {
+		if len(data) < pos+4 {
+			return RTPHeader{}, 0, errors.New("rtp: short extension header")
+		}
+
+		h.ExtensionProfile = binary.BigEndian.Uint16(data[pos:])
+		length := int(binary.BigEndian.Uint16(data[pos+2:])) * 4
+		pos += 4
+
+		if len(data) < pos+length {
+			return RTPHeader{}, 0, errors.New("rtp: short extension data")
+		}
+
+		h.ExtensionData = append([]byte(nil), data[pos:pos+length]...)
+		h.ExtensionElements = parseRTPHeaderExtensionElements(h.ExtensionProfile, h.ExtensionData)
+		pos += length
+	}
+
+	return h, pos, nil
+}
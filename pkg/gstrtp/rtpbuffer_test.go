@@ -0,0 +1,110 @@
+package gstrtp_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstrtp"
+)
+
+func TestWithRTPBuffer(t *testing.T) {
+	gst.Init()
+
+	buf := gstrtp.NewRTPBufferAllocate(4, 0, 1)
+
+	err := gstrtp.WithRTPBuffer(buf, gst.MapRead|gst.MapWrite, func(rtp *gstrtp.RTPBuffer) error {
+		rtp.SetMarker(true)
+		rtp.SetPayloadType(96)
+		rtp.SetSeq(1234)
+		rtp.SetTimestamp(90000)
+		rtp.SetSsrc(0xdeadbeef)
+		rtp.SetCsrc(0, 42)
+
+		if !rtp.AddExtensionOnebyteHeader(1, []byte{0xaa, 0xbb, 0xcc}) {
+			t.Error("could not add extension 1")
+		}
+
+		if !rtp.AddExtensionOnebyteHeader(3, []byte{0x01}) {
+			t.Error("could not add extension 3")
+		}
+
+		copy(rtp.Payload(), []byte{1, 2, 3, 4})
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var header gstrtp.RTPHeader
+	var payload []byte
+
+	err = gstrtp.WithRTPBuffer(buf, gst.MapRead, func(rtp *gstrtp.RTPBuffer) error {
+		header = rtp.Header()
+		payload = bytes.Clone(rtp.Payload())
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Version != 2 || !header.Marker || header.PayloadType != 96 || header.Seq != 1234 ||
+		header.Timestamp != 90000 || header.SSRC != 0xdeadbeef {
+		t.Errorf("unexpected header %+v", header)
+	}
+
+	if !reflect.DeepEqual(header.CSRCs, []uint32{42}) {
+		t.Errorf("unexpected csrcs %v", header.CSRCs)
+	}
+
+	if !header.Extension || header.ExtensionProfile != 0xBEDE {
+		t.Errorf("unexpected extension profile %x", header.ExtensionProfile)
+	}
+
+	if data, ok := header.ExtensionElement(1); !ok || !bytes.Equal(data, []byte{0xaa, 0xbb, 0xcc}) {
+		t.Errorf("unexpected extension 1: %v", data)
+	}
+
+	if data, ok := header.ExtensionElement(3); !ok || !bytes.Equal(data, []byte{0x01}) {
+		t.Errorf("unexpected extension 3: %v", data)
+	}
+
+	if !bytes.Equal(payload, []byte{1, 2, 3, 4}) {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	info, ok := buf.Map(gst.MapRead)
+	if !ok {
+		t.Fatal("could not map buffer")
+	}
+
+	raw := bytes.Clone(info.Data())
+	info.Unmap()
+
+	parsed, n, err := gstrtp.ParseRTPHeader(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, header) {
+		t.Errorf("parsed header %+v differs from mapped header %+v", parsed, header)
+	}
+
+	if !bytes.Equal(raw[n:], payload) {
+		t.Errorf("unexpected payload after parsed header: %v", raw[n:])
+	}
+
+	errTest := errors.New("test")
+
+	if err := gstrtp.WithRTPBuffer(buf, gst.MapRead, func(*gstrtp.RTPBuffer) error { return errTest }); err != errTest {
+		t.Errorf("expected callback error, got %v", err)
+	}
+
+	if err := gstrtp.WithRTPBuffer(gst.NewBuffer(), gst.MapRead, func(*gstrtp.RTPBuffer) error { return nil }); !errors.Is(err, gstrtp.ErrRTPMap) {
+		t.Errorf("expected map error for empty buffer, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package gstrtp
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+import "C"
+
+type RTPHeaderExtensionExtManual interface {
+	// Read wraps gst_rtp_header_extension_read
+	Read(readFlags RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool
+
+	// Write wraps gst_rtp_header_extension_write
+	Write(inputMeta *gst.Buffer, writeFlags RTPHeaderExtensionFlags, output *gst.Buffer, data []byte) int
+}
+
+// Read wraps gst_rtp_header_extension_read
+//
+// Reads the extension data of a single header extension element and applies it to buffer.
+func (ext *RTPHeaderExtensionInstance) Read(readFlags RTPHeaderExtensionFlags, data []byte, buffer *gst.Buffer) bool {
+	var carg0 *C.GstRTPHeaderExtension     // in, none, converted
+	var carg1 C.GstRTPHeaderExtensionFlags // in, none, casted
+	var carg2 *C.guint8                    // in, none, array
+	var carg3 C.gsize                      // in, none, casted
+	var carg4 *C.GstBuffer                 // in, none, converted
+	var cret C.gboolean                    // return
+
+	carg0 = (*C.GstRTPHeaderExtension)(UnsafeRTPHeaderExtensionToGlibNone(ext))
+	carg1 = C.GstRTPHeaderExtensionFlags(readFlags)
+	if len(data) > 0 {
+		carg2 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+	carg3 = C.gsize(len(data))
+	carg4 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer))
+
+	cret = C.gst_rtp_header_extension_read(carg0, carg1, carg2, carg3, carg4)
+	runtime.KeepAlive(ext)
+	runtime.KeepAlive(data)
+	runtime.KeepAlive(buffer)
+
+	return cret != 0
+}
+
+// Write wraps gst_rtp_header_extension_write
+//
+// Writes the extension data for inputMeta into data, which must have at least the size returned
+// by [RTPHeaderExtensionInstance.GetMaxSize]. It returns the number of written bytes or a negative
+// value on error.
+func (ext *RTPHeaderExtensionInstance) Write(inputMeta *gst.Buffer, writeFlags RTPHeaderExtensionFlags, output *gst.Buffer, data []byte) int {
+	var carg0 *C.GstRTPHeaderExtension     // in, none, converted
+	var carg1 *C.GstBuffer                 // in, none, converted
+	var carg2 C.GstRTPHeaderExtensionFlags // in, none, casted
+	var carg3 *C.GstBuffer                 // in, none, converted
+	var carg4 *C.guint8                    // in, none, array
+	var carg5 C.gsize                      // in, none, casted
+	var cret C.gssize                      // return, none, casted
+
+	carg0 = (*C.GstRTPHeaderExtension)(UnsafeRTPHeaderExtensionToGlibNone(ext))
+	carg1 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(inputMeta))
+	carg2 = C.GstRTPHeaderExtensionFlags(writeFlags)
+	carg3 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(output))
+	if len(data) > 0 {
+		carg4 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+	carg5 = C.gsize(len(data))
+
+	cret = C.gst_rtp_header_extension_write(carg0, carg1, carg2, carg3, carg4, carg5)
+	runtime.KeepAlive(ext)
+	runtime.KeepAlive(inputMeta)
+	runtime.KeepAlive(output)
+	runtime.KeepAlive(data)
+
+	return int(cret)
+}
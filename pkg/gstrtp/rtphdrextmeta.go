@@ -0,0 +1,299 @@
+package gstrtp
+
+import (
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-rtp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtp/rtp.h>
+//
+// static GstStructure *_gogst_gstrtp1_hdrext_meta_structure(GstBuffer *buffer, const gchar *name, gboolean create) {
+//   GstCustomMeta *meta;
+//
+//   if (create && !gst_buffer_is_writable(buffer))
+//     return NULL;
+//
+//   meta = gst_buffer_get_custom_meta(buffer, name);
+//   if (meta == NULL && create)
+//     meta = gst_buffer_add_custom_meta(buffer, name);
+//   if (meta == NULL)
+//     return NULL;
+//
+//   return gst_custom_meta_get_structure(meta);
+// }
+//
+// static void _gogst_gstrtp1_structure_set_uint(GstStructure *s, const gchar *field, guint value) {
+//   GValue v = G_VALUE_INIT;
+//
+//   g_value_init(&v, G_TYPE_UINT);
+//   g_value_set_uint(&v, value);
+//   gst_structure_take_value(s, field, &v);
+// }
+//
+// static void _gogst_gstrtp1_structure_set_boolean(GstStructure *s, const gchar *field, gboolean value) {
+//   GValue v = G_VALUE_INIT;
+//
+//   g_value_init(&v, G_TYPE_BOOLEAN);
+//   g_value_set_boolean(&v, value);
+//   gst_structure_take_value(s, field, &v);
+// }
+//
+// static void _gogst_gstrtp1_structure_set_bytes(GstStructure *s, const gchar *field, gconstpointer data, gsize size) {
+//   GValue v = G_VALUE_INIT;
+//
+//   g_value_init(&v, G_TYPE_BYTES);
+//   g_value_take_boxed(&v, g_bytes_new(data, size));
+//   gst_structure_take_value(s, field, &v);
+// }
+//
+// static gboolean _gogst_gstrtp1_structure_get_bytes(const GstStructure *s, const gchar *field, gconstpointer *data, gsize *size) {
+//   const GValue *v = gst_structure_get_value(s, field);
+//
+//   if (v == NULL || !G_VALUE_HOLDS(v, G_TYPE_BYTES) || g_value_get_boxed(v) == NULL)
+//     return FALSE;
+//
+//   *data = g_bytes_get_data((GBytes *) g_value_get_boxed(v), size);
+//   return TRUE;
+// }
+import "C"
+
+// RTPHeaderExtensionMetaName is the name of the custom meta that carries the values of the Go
+// header extensions registered with [RegisterRTPHeaderExtensions] and
+// [RegisterRTPOpaqueHeaderExtension]. Payloaders write the values found on their input buffers,
+// depayloaders attach the received values to their output buffers.
+const RTPHeaderExtensionMetaName = "GoRTPHeaderExtensionMeta"
+
+// fields of the header extension meta structure
+const (
+	rtpMetaFieldAbsSendTime   = "abs-send-time"
+	rtpMetaFieldTWCCSeqnum    = "transport-wide-seqnum"
+	rtpMetaFieldAudioLevel    = "audio-level"
+	rtpMetaFieldVoiceActivity = "voice-activity"
+	rtpMetaFieldRotation      = "video-rotation"
+	rtpMetaFieldFlip          = "video-flip"
+	rtpMetaFieldCamera        = "video-camera"
+)
+
+var registerRTPHeaderExtensionMeta = sync.OnceFunc(func() {
+	gst.MetaRegisterCustomSimple(RTPHeaderExtensionMetaName)
+})
+
+// RTPAudioLevel is the value of the client-to-mixer audio level header extension (RFC 6464).
+type RTPAudioLevel struct {
+	// Level is the audio level in -dBov, between 0 and 127.
+	Level uint8
+	// Voice is true if the packet contains speech.
+	Voice bool
+}
+
+// RTPVideoOrientation is the value of the coordination of video orientation header extension
+// (3GPP TS 26.114).
+type RTPVideoOrientation struct {
+	// Rotation is the clockwise rotation in degrees, one of 0, 90, 180 and 270.
+	Rotation uint16
+	// Flip is true if the video is flipped horizontally.
+	Flip bool
+	// Camera is true if the video was captured by the back-facing camera.
+	Camera bool
+}
+
+// rtpHeaderExtensionMeta returns the structure of the header extension meta on the buffer. If
+// create is true, then the meta is added if needed, which requires a writable buffer.
+func rtpHeaderExtensionMeta(buffer *gst.Buffer, create bool) *C.GstStructure {
+	registerRTPHeaderExtensionMeta()
+
+	cname := C.CString(RTPHeaderExtensionMetaName)
+	defer C.free(unsafe.Pointer(cname))
+
+	var ccreate C.gboolean
+	if create {
+		ccreate = C.TRUE
+	}
+
+	s := C._gogst_gstrtp1_hdrext_meta_structure((*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer)), (*C.gchar)(cname), ccreate)
+	runtime.KeepAlive(buffer)
+
+	return s
+}
+
+func setRTPHeaderExtensionUint(buffer *gst.Buffer, field string, value uint) bool {
+	s := rtpHeaderExtensionMeta(buffer, true)
+	if s == nil {
+		return false
+	}
+
+	cfield := C.CString(field)
+	defer C.free(unsafe.Pointer(cfield))
+
+	C._gogst_gstrtp1_structure_set_uint(s, (*C.gchar)(cfield), C.guint(value))
+	runtime.KeepAlive(buffer)
+
+	return true
+}
+
+func setRTPHeaderExtensionBool(buffer *gst.Buffer, field string, value bool) bool {
+	s := rtpHeaderExtensionMeta(buffer, true)
+	if s == nil {
+		return false
+	}
+
+	cfield := C.CString(field)
+	defer C.free(unsafe.Pointer(cfield))
+
+	var cvalue C.gboolean
+	if value {
+		cvalue = C.TRUE
+	}
+
+	C._gogst_gstrtp1_structure_set_boolean(s, (*C.gchar)(cfield), cvalue)
+	runtime.KeepAlive(buffer)
+
+	return true
+}
+
+func getRTPHeaderExtensionUint(buffer *gst.Buffer, field string) (uint, bool) {
+	s := rtpHeaderExtensionMeta(buffer, false)
+	if s == nil {
+		return 0, false
+	}
+
+	cfield := C.CString(field)
+	defer C.free(unsafe.Pointer(cfield))
+
+	var value C.guint
+
+	ok := C.gst_structure_get_uint(s, (*C.gchar)(cfield), &value)
+	runtime.KeepAlive(buffer)
+
+	return uint(value), ok != 0
+}
+
+func getRTPHeaderExtensionBool(buffer *gst.Buffer, field string) (bool, bool) {
+	s := rtpHeaderExtensionMeta(buffer, false)
+	if s == nil {
+		return false, false
+	}
+
+	cfield := C.CString(field)
+	defer C.free(unsafe.Pointer(cfield))
+
+	var value C.gboolean
+
+	ok := C.gst_structure_get_boolean(s, (*C.gchar)(cfield), &value)
+	runtime.KeepAlive(buffer)
+
+	return value != 0, ok != 0
+}
+
+// SetRTPHeaderExtensionBytes stores data for the header extension with the given URI on the
+// buffer, which must be writable. The data is written by payloaders with an extension registered
+// by [RegisterRTPOpaqueHeaderExtension].
+func SetRTPHeaderExtensionBytes(buffer *gst.Buffer, uri string, data []byte) bool {
+	s := rtpHeaderExtensionMeta(buffer, true)
+	if s == nil {
+		return false
+	}
+
+	cfield := C.CString(uri)
+	defer C.free(unsafe.Pointer(cfield))
+
+	var cdata C.gconstpointer
+	if len(data) > 0 {
+		cdata = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+
+	C._gogst_gstrtp1_structure_set_bytes(s, (*C.gchar)(cfield), cdata, C.gsize(len(data)))
+	runtime.KeepAlive(buffer)
+	runtime.KeepAlive(data)
+
+	return true
+}
+
+// GetRTPHeaderExtensionBytes returns a copy of the data for the header extension with the given
+// URI, as stored by [SetRTPHeaderExtensionBytes] or by a depayloader with an extension registered
+// by [RegisterRTPOpaqueHeaderExtension].
+func GetRTPHeaderExtensionBytes(buffer *gst.Buffer, uri string) ([]byte, bool) {
+	s := rtpHeaderExtensionMeta(buffer, false)
+	if s == nil {
+		return nil, false
+	}
+
+	cfield := C.CString(uri)
+	defer C.free(unsafe.Pointer(cfield))
+
+	var data C.gconstpointer
+	var size C.gsize
+
+	ok := C._gogst_gstrtp1_structure_get_bytes(s, (*C.gchar)(cfield), &data, &size)
+
+	var goret []byte
+	if ok != 0 && size > 0 {
+		goret = C.GoBytes(unsafe.Pointer(data), C.int(size))
+	}
+	runtime.KeepAlive(buffer)
+
+	return goret, ok != 0
+}
+
+// GetRTPAbsSendTime returns the received absolute send time. Only the 6 least significant bits
+// of the seconds are transmitted, so the value is between 0 and 64 seconds.
+func GetRTPAbsSendTime(buffer *gst.Buffer) (time.Duration, bool) {
+	v, ok := getRTPHeaderExtensionUint(buffer, rtpMetaFieldAbsSendTime)
+	if !ok {
+		return 0, false
+	}
+
+	return absSendTimeToDuration(uint32(v)), true
+}
+
+// GetRTPTransportWideSeqnum returns the received transport-wide sequence number.
+func GetRTPTransportWideSeqnum(buffer *gst.Buffer) (uint16, bool) {
+	v, ok := getRTPHeaderExtensionUint(buffer, rtpMetaFieldTWCCSeqnum)
+
+	return uint16(v), ok
+}
+
+// SetRTPAudioLevel stores the audio level on the buffer, which must be writable.
+func SetRTPAudioLevel(buffer *gst.Buffer, level RTPAudioLevel) bool {
+	return setRTPHeaderExtensionUint(buffer, rtpMetaFieldAudioLevel, uint(min(level.Level, 127))) &&
+		setRTPHeaderExtensionBool(buffer, rtpMetaFieldVoiceActivity, level.Voice)
+}
+
+// GetRTPAudioLevel returns the audio level stored on the buffer.
+func GetRTPAudioLevel(buffer *gst.Buffer) (RTPAudioLevel, bool) {
+	level, ok := getRTPHeaderExtensionUint(buffer, rtpMetaFieldAudioLevel)
+	if !ok {
+		return RTPAudioLevel{}, false
+	}
+
+	voice, _ := getRTPHeaderExtensionBool(buffer, rtpMetaFieldVoiceActivity)
+
+	return RTPAudioLevel{Level: uint8(level), Voice: voice}, true
+}
+
+// SetRTPVideoOrientation stores the video orientation on the buffer, which must be writable. The
+// rotation is rounded down to a multiple of 90 degrees.
+func SetRTPVideoOrientation(buffer *gst.Buffer, orientation RTPVideoOrientation) bool {
+	return setRTPHeaderExtensionUint(buffer, rtpMetaFieldRotation, uint(orientation.Rotation%360/90*90)) &&
+		setRTPHeaderExtensionBool(buffer, rtpMetaFieldFlip, orientation.Flip) &&
+		setRTPHeaderExtensionBool(buffer, rtpMetaFieldCamera, orientation.Camera)
+}
+
+// GetRTPVideoOrientation returns the video orientation stored on the buffer.
+func GetRTPVideoOrientation(buffer *gst.Buffer) (RTPVideoOrientation, bool) {
+	rotation, ok := getRTPHeaderExtensionUint(buffer, rtpMetaFieldRotation)
+	if !ok {
+		return RTPVideoOrientation{}, false
+	}
+
+	flip, _ := getRTPHeaderExtensionBool(buffer, rtpMetaFieldFlip)
+	camera, _ := getRTPHeaderExtensionBool(buffer, rtpMetaFieldCamera)
+
+	return RTPVideoOrientation{Rotation: uint16(rotation), Flip: flip, Camera: camera}, true
+}
@@ -4128,30 +4128,6 @@ func (msg *RTSPMessage) InitResponse(code RTSPStatusCode, reason string, request
 	return goret
 }
 
-// ParseAuthCredentials wraps gst_rtsp_message_parse_auth_credentials
-// 
-// see also https://gstreamer.freedesktop.org/documentation/rtsp/gstrtspmessage.html#gst_rtsp_message_parse_auth_credentials
-func (msg *RTSPMessage) ParseAuthCredentials(field RTSPHeaderField) []*RTSPAuthCredential {
-	var carg0 *C.GstRTSPMessage         // in, none, converted
-	var carg1 C.GstRTSPHeaderField      // in, none, casted
-	var cret  **C.GstRTSPAuthCredential // return, transfer: full, C Pointers: 2, Name: array[RTSPAuthCredential], scope: , array (inner GstRTSPAuthCredential* (*typesystem.Record), zero-terminated)
-
-	carg0 = (*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg))
-	carg1 = C.GstRTSPHeaderField(field)
-
-	cret = C.gst_rtsp_message_parse_auth_credentials(carg0, carg1)
-	runtime.KeepAlive(msg)
-	runtime.KeepAlive(field)
-
-	var goret []*RTSPAuthCredential
-
-	_ = goret
-	_ = cret
-	panic("unimplemented conversion of []*RTSPAuthCredential (GstRTSPAuthCredential**) because of unknown reason")
-
-	return goret
-}
-
 // ParseData wraps gst_rtsp_message_parse_data
 // 
 // see also https://gstreamer.freedesktop.org/documentation/rtsp/gstrtspmessage.html#gst_rtsp_message_parse_data
@@ -4281,32 +4257,6 @@ func (msg *RTSPMessage) RemoveHeaderByName(header string, index int32) RTSPResul
 	return goret
 }
 
-// SetBody wraps gst_rtsp_message_set_body
-// 
-// see also https://gstreamer.freedesktop.org/documentation/rtsp/gstrtspmessage.html#gst_rtsp_message_set_body
-func (msg *RTSPMessage) SetBody(data []uint8) RTSPResult {
-	var carg0 *C.GstRTSPMessage // in, none, converted
-	var carg1 *C.guint8         // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg2)
-	var carg2 C.guint           // implicit
-	var cret  C.GstRTSPResult   // return, none, casted
-
-	carg0 = (*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg))
-	_ = data
-	_ = carg1
-	_ = carg2
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-
-	cret = C.gst_rtsp_message_set_body(carg0, carg1, carg2)
-	runtime.KeepAlive(msg)
-	runtime.KeepAlive(data)
-
-	var goret RTSPResult
-
-	goret = RTSPResult(cret)
-
-	return goret
-}
-
 // SetBodyBuffer wraps gst_rtsp_message_set_body_buffer
 // 
 // see also https://gstreamer.freedesktop.org/documentation/rtsp/gstrtspmessage.html#gst_rtsp_message_set_body_buffer
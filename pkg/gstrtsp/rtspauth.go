@@ -0,0 +1,54 @@
+package gstrtsp
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-rtsp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtsp/rtsp.h>
+import "C"
+
+// getters for the fields of GstRTSPAuthCredential:
+
+// GetScheme returns the authentication scheme of the credential
+func (c *RTSPAuthCredential) GetScheme() RTSPAuthMethod {
+	return RTSPAuthMethod(c.native.scheme)
+}
+
+// GetAuthorization returns the authorization of the credential, if the scheme has no parameters
+func (c *RTSPAuthCredential) GetAuthorization() string {
+	return C.GoString(c.native.authorization)
+}
+
+// Params returns an iterator over the parameters of the credential, e.g. realm and nonce
+func (c *RTSPAuthCredential) Params() iter.Seq[*RTSPAuthParam] {
+	return func(yield func(*RTSPAuthParam) bool) {
+		if c.native.params == nil {
+			return
+		}
+
+		for _, p := range unsafe.Slice(c.native.params, 1<<16) {
+			if p == nil {
+				return
+			}
+
+			if !yield(UnsafeRTSPAuthParamFromGlibBorrow(unsafe.Pointer(p))) {
+				return
+			}
+		}
+	}
+}
+
+// getters for the fields of GstRTSPAuthParam:
+
+// GetName returns the name of the parameter
+func (p *RTSPAuthParam) GetName() string {
+	return C.GoString(p.native.name)
+}
+
+// GetValue returns the value of the parameter
+func (p *RTSPAuthParam) GetValue() string {
+	return C.GoString(p.native.value)
+}
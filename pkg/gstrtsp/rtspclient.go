@@ -0,0 +1,780 @@
+package gstrtsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gstsdp"
+)
+
+// DefaultRTSPSessionTimeout is the session timeout that is assumed when the server
+// does not send a timeout parameter in the Session header.
+const DefaultRTSPSessionTimeout = 60 * time.Second
+
+// RTSPCloseTimeout limits the TEARDOWN that [RTSPClient.Close] sends for an active session.
+const RTSPCloseTimeout = 2 * time.Second
+
+// ErrRTSPClientClosed is returned by the methods of a closed [RTSPClient].
+var ErrRTSPClientClosed = errors.New("rtsp client closed")
+
+// RTSPResultError is returned by [RTSPClient] when an operation on the underlying
+// [RTSPConnection] or [RTSPMessage] failed.
+type RTSPResultError struct {
+	// Op is the failed operation, e.g. "send" or "receive".
+	Op string
+	// Result is the result returned by the failed call.
+	Result RTSPResult
+}
+
+func (e *RTSPResultError) Error() string {
+	return fmt.Sprintf("rtsp %s: %s", e.Op, RtspStrresult(e.Result))
+}
+
+// RTSPStatusError is returned by [RTSPClient] when the server responded with a status code other than 2xx.
+type RTSPStatusError struct {
+	Code   RTSPStatusCode
+	Reason string
+}
+
+func (e *RTSPStatusError) Error() string {
+	return fmt.Sprintf("rtsp status %d: %s", int(e.Code), e.Reason)
+}
+
+// RTSPResponse is a parsed response of the server.
+type RTSPResponse struct {
+	// Message is the full response message.
+	Message *RTSPMessage
+	// Code is the status code of the response.
+	Code RTSPStatusCode
+	// Reason is the reason phrase of the response.
+	Reason string
+}
+
+// Header returns the first value of the given header field or an empty string if the
+// response does not contain the header.
+func (r *RTSPResponse) Header(field RTSPHeaderField) string {
+	return r.Message.Header(field)
+}
+
+// RTSPClient is a minimal RTSP client on top of [RTSPConnection].
+//
+// Requests are serialized, so the client can be used from multiple goroutines. Interleaved
+// data can be read with [RTSPClient.ReadData] while requests are sent, responses that are
+// received by ReadData are handed to the waiting request. After a successful SETUP the client
+// keeps the session alive by sending GET_PARAMETER requests until the session is torn down.
+type RTSPClient struct {
+	// UserAgent is sent in the User-Agent header of every request, if not empty.
+	UserAgent string
+
+	// Timeout limits every send, every wait for a response and every receive of
+	// [RTSPClient.ReadData] if the context of the call has no deadline. A zero Timeout
+	// blocks until the context is done.
+	Timeout time.Duration
+
+	// OnData is called with the interleaved data that is received while waiting for a
+	// response. If OnData is nil, then such data is dropped. OnData must not call the
+	// methods of the client.
+	OnData func(channel uint8, data []byte)
+
+	conn *RTSPConnection
+	url  *RTSPUrl
+
+	// mu serializes the request/response exchanges on the connection
+	mu     sync.Mutex
+	closed atomic.Bool
+	cseq   int
+
+	// reader is held by the goroutine that receives from the connection
+	reader chan struct{}
+
+	// pendingMu guards the response channel of the request that waits for its response
+	pendingMu sync.Mutex
+	pending   *pendingResponse
+
+	baseURI        string
+	session        string
+	sessionTimeout time.Duration
+	user, pass     string
+
+	stopKeepalive context.CancelFunc
+	keepaliveDone chan struct{}
+}
+
+// DialRTSP parses the rtsp:// url and connects to the server. User and password
+// contained in the url are used to answer authentication challenges.
+func DialRTSP(ctx context.Context, rawurl string) (*RTSPClient, error) {
+	url, res := RTSPUrlParse(rawurl)
+	if res != RtspOK {
+		return nil, &RTSPResultError{Op: "parse url", Result: res}
+	}
+
+	conn, res := RTSPConnectionCreate(url)
+	if res != RtspOK {
+		return nil, &RTSPResultError{Op: "create connection", Result: res}
+	}
+
+	// the session header is managed by the client
+	conn.SetRememberSessionID(false)
+
+	c := &RTSPClient{
+		conn:    conn,
+		reader:  make(chan struct{}, 1),
+		url:     url,
+		baseURI: url.GetRequestURI(),
+		user:    url.GetUser(),
+		pass:    url.GetPasswd(),
+	}
+
+	err := c.withContext(ctx, "connect", func(timeout int64) RTSPResult {
+		return conn.ConnectUsec(timeout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// URL returns the parsed url the client is connected to.
+func (c *RTSPClient) URL() *RTSPUrl {
+	return c.url
+}
+
+// Connection returns the underlying connection.
+func (c *RTSPClient) Connection() *RTSPConnection {
+	return c.conn
+}
+
+// SetCredentials sets the user and password that are used to answer authentication challenges.
+func (c *RTSPClient) SetCredentials(user, pass string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.user = user
+	c.pass = pass
+}
+
+// Session returns the current session id and its timeout. The session id is empty before
+// a successful [RTSPClient.Setup].
+func (c *RTSPClient) Session() (string, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.session, c.sessionTimeout
+}
+
+// Options sends an OPTIONS request and returns the methods listed in the Public header.
+func (c *RTSPClient) Options(ctx context.Context) ([]string, *RTSPResponse, error) {
+	resp, err := c.request(ctx, RtspOptions, "*", nil, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var methods []string
+
+	for _, m := range strings.Split(resp.Header(RtspHdrPublic), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			methods = append(methods, m)
+		}
+	}
+
+	return methods, resp, nil
+}
+
+// Describe sends a DESCRIBE request and parses the SDP body of the response. The
+// Content-Base or Content-Location of the response is used to resolve the control
+// urls of subsequent Setup calls.
+func (c *RTSPClient) Describe(ctx context.Context) (*gstsdp.SDPMessage, *RTSPResponse, error) {
+	resp, err := c.request(ctx, RtspDescribe, c.url.GetRequestURI(), map[RTSPHeaderField]string{
+		RtspHdrAccept: "application/sdp",
+	}, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if ct := resp.Header(RtspHdrContentType); ct != "" && !strings.HasPrefix(ct, "application/sdp") {
+		return nil, resp, fmt.Errorf("rtsp describe: unexpected content type %q", ct)
+	}
+
+	body, res := resp.Message.GetBody()
+	if res != RtspOK {
+		return nil, resp, &RTSPResultError{Op: "get body", Result: res}
+	}
+
+	sdp, sdpres := gstsdp.NewSDPMessageFromText(string(body))
+	if sdpres != gstsdp.SdpOK {
+		return nil, resp, fmt.Errorf("rtsp describe: could not parse sdp: %s", sdpres)
+	}
+
+	base := resp.Header(RtspHdrContentBase)
+	if base == "" {
+		base = resp.Header(RtspHdrContentLocation)
+	}
+
+	if base != "" {
+		c.mu.Lock()
+		c.baseURI = base
+		c.mu.Unlock()
+	}
+
+	return sdp, resp, nil
+}
+
+// Setup sends a SETUP request for the given control url of a media and returns the
+// transport selected by the server. control is either an absolute url or resolved
+// against the base url of the presentation. transport is sent as the Transport header,
+// e.g. "RTP/AVP/TCP;unicast;interleaved=0-1".
+//
+// The session id of the response is used for all subsequent requests and the session is
+// kept alive until [RTSPClient.Teardown] or [RTSPClient.Close] is called.
+func (c *RTSPClient) Setup(ctx context.Context, control string, transport string) (*RTSPTransport, *RTSPResponse, error) {
+	resp, err := c.request(ctx, RtspSetup, c.controlURI(control), map[RTSPHeaderField]string{
+		RtspHdrTransport: transport,
+	}, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	session, timeout := parseSessionHeader(resp.Header(RtspHdrSession))
+	if session == "" {
+		return nil, resp, errors.New("rtsp setup: response has no session")
+	}
+
+	var selected *RTSPTransport
+
+	if header := resp.Header(RtspHdrTransport); header != "" {
+		var res RTSPResult
+
+		selected, res = RTSPTransportParse(header)
+		if res != RtspOK {
+			return nil, resp, &RTSPResultError{Op: "parse transport", Result: res}
+		}
+	}
+
+	c.mu.Lock()
+	c.session = session
+	c.sessionTimeout = timeout
+	c.startKeepalive()
+	c.mu.Unlock()
+
+	return selected, resp, nil
+}
+
+// Play sends a PLAY request for the presentation. rangeHeader is sent as the Range
+// header if not empty, e.g. "npt=0-".
+func (c *RTSPClient) Play(ctx context.Context, rangeHeader string) (*RTSPResponse, error) {
+	var headers map[RTSPHeaderField]string
+	if rangeHeader != "" {
+		headers = map[RTSPHeaderField]string{RtspHdrRange: rangeHeader}
+	}
+
+	return c.request(ctx, RtspPlay, c.aggregateURI(), headers, nil)
+}
+
+// Pause sends a PAUSE request for the presentation.
+func (c *RTSPClient) Pause(ctx context.Context) (*RTSPResponse, error) {
+	return c.request(ctx, RtspPause, c.aggregateURI(), nil, nil)
+}
+
+// Teardown sends a TEARDOWN request for the presentation and ends the session.
+func (c *RTSPClient) Teardown(ctx context.Context) (*RTSPResponse, error) {
+	resp, err := c.request(ctx, RtspTeardown, c.aggregateURI(), nil, nil)
+
+	c.endSession()
+
+	return resp, err
+}
+
+// GetParameter sends a GET_PARAMETER request. If params is not empty, then the request
+// has a text/parameters body with one parameter per line. Without parameters the request
+// can be used as a keepalive.
+func (c *RTSPClient) GetParameter(ctx context.Context, params ...string) (*RTSPResponse, error) {
+	var headers map[RTSPHeaderField]string
+	var body []byte
+
+	if len(params) > 0 {
+		headers = map[RTSPHeaderField]string{RtspHdrContentType: "text/parameters"}
+		body = []byte(strings.Join(params, "\r\n") + "\r\n")
+	}
+
+	return c.request(ctx, RtspGetParameter, c.aggregateURI(), headers, body)
+}
+
+// ReadData blocks until interleaved data was received and returns it together with its
+// channel. Responses that are received in the meantime are handed to the waiting request,
+// requests of the server are dropped.
+//
+// ReadData does not block requests, so it can be called in a loop after PLAY while the
+// keepalive is running. Cancelling the context interrupts all pending operations on the
+// connection, including a request that is sent at the same time.
+func (c *RTSPClient) ReadData(ctx context.Context) (uint8, []byte, error) {
+	select {
+	case c.reader <- struct{}{}:
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+	defer func() { <-c.reader }()
+
+	for {
+		if c.closed.Load() {
+			return 0, nil, ErrRTSPClientClosed
+		}
+
+		msg, err := c.receive(ctx)
+		if err != nil {
+			if c.closed.Load() {
+				return 0, nil, ErrRTSPClientClosed
+			}
+
+			return 0, nil, err
+		}
+
+		if msg.GetType() != RtspMessageData {
+			c.dispatch(msg)
+			continue
+		}
+
+		channel, _ := msg.ParseData()
+		data, _ := msg.GetBody()
+
+		return channel, data, nil
+	}
+}
+
+// Close ends the session and closes the connection. Pending requests and a pending
+// [RTSPClient.ReadData] are interrupted and return [ErrRTSPClientClosed]. If a session was set
+// up, then a TEARDOWN is sent that waits at most [RTSPCloseTimeout] for the response.
+func (c *RTSPClient) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+
+	// interrupt a pending request, so the lock is released even if the server stalls
+	c.conn.Flush(true)
+
+	c.teardownOnClose()
+	c.endSession()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// interrupt a pending ReadData and wait until it stopped using the connection
+	c.conn.Flush(true)
+	c.reader <- struct{}{}
+	defer func() { <-c.reader }()
+
+	if res := c.conn.Close(); res != RtspOK {
+		return &RTSPResultError{Op: "close", Result: res}
+	}
+
+	return nil
+}
+
+// teardownOnClose sends a TEARDOWN for the session, errors are ignored because the connection is
+// closed anyway
+func (c *RTSPClient) teardownOnClose() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == "" {
+		return
+	}
+
+	// the interrupted request released the lock, the connection can be used again
+	c.conn.Flush(false)
+
+	req, err := NewRTSPRequestBuilder(RtspTeardown, strings.TrimSuffix(c.baseURI, "/")).Build()
+	if err != nil {
+		return
+	}
+
+	if c.UserAgent != "" {
+		req.SetHeader(RtspHdrUserAgent, c.UserAgent)
+	}
+
+	req.SetHeader(RtspHdrSession, c.session)
+
+	ctx, cancel := context.WithTimeout(context.Background(), RTSPCloseTimeout)
+	defer cancel()
+
+	c.roundtrip(ctx, req)
+}
+
+// Do sends the request and waits for the response with the same CSeq. The User-Agent, CSeq
+// and Session headers are added to the request. If the server requests authentication and credentials are set, then
+// the request is retried once with the authorization. Interleaved data that is received while
+// waiting is passed to OnData.
+//
+// Do returns an [*RTSPStatusError] together with the response if the status code is not 2xx.
+func (c *RTSPClient) Do(ctx context.Context, req *RTSPMessage) (*RTSPResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed.Load() {
+		return nil, ErrRTSPClientClosed
+	}
+
+	if c.UserAgent != "" {
+		req.SetHeader(RtspHdrUserAgent, c.UserAgent)
+	}
+
+	if c.session != "" {
+		req.SetHeader(RtspHdrSession, c.session)
+	}
+
+	resp, err := c.roundtrip(ctx, req)
+	if err != nil {
+		return nil, c.closedErr(err)
+	}
+
+	if resp.Code == RtspStsUnauthorized && c.user != "" && c.setupAuth(resp) {
+		// the connection adds the authorization header when sending
+		req.RemoveHeader(RtspHdrAuthorization, -1)
+
+		resp, err = c.roundtrip(ctx, req)
+		if err != nil {
+			return nil, c.closedErr(err)
+		}
+	}
+
+	if resp.Code < 200 || resp.Code >= 300 {
+		return resp, &RTSPStatusError{Code: resp.Code, Reason: resp.Reason}
+	}
+
+	return resp, nil
+}
+
+// closedErr returns ErrRTSPClientClosed instead of err if the request was interrupted by Close
+func (c *RTSPClient) closedErr(err error) error {
+	if c.closed.Load() {
+		return ErrRTSPClientClosed
+	}
+
+	return err
+}
+
+func (c *RTSPClient) request(ctx context.Context, method RTSPMethod, uri string, headers map[RTSPHeaderField]string, body []byte) (*RTSPResponse, error) {
+	b := NewRTSPRequestBuilder(method, uri)
+
+	for field, value := range headers {
+		b.Header(field, value)
+	}
+
+	if body != nil {
+		b.Body(body)
+	}
+
+	req, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(ctx, req)
+}
+
+// pendingResponse receives the response with the CSeq of the request that waits for it
+type pendingResponse struct {
+	cseq      string
+	responses chan *RTSPMessage
+}
+
+// roundtrip sends the request with a new CSeq and receives the response with the same CSeq.
+// Must be called with the lock held.
+func (c *RTSPClient) roundtrip(ctx context.Context, req *RTSPMessage) (*RTSPResponse, error) {
+	c.cseq++
+	cseq := strconv.Itoa(c.cseq)
+
+	req.SetHeader(RtspHdrCseq, cseq)
+
+	responses := make(chan *RTSPMessage, 1)
+
+	c.setPending(&pendingResponse{cseq: cseq, responses: responses})
+	defer c.setPending(nil)
+
+	err := c.withContext(ctx, "send", func(timeout int64) RTSPResult {
+		return c.conn.SendUsec(req, timeout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case msg := <-responses:
+			return parseResponse(msg)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case c.reader <- struct{}{}:
+		}
+
+		// the previous reader may have received the response before releasing the connection
+		select {
+		case msg := <-responses:
+			<-c.reader
+			return parseResponse(msg)
+		default:
+		}
+
+		msg, err := c.receive(ctx)
+		<-c.reader
+
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.GetType() == RtspMessageData {
+			if c.OnData != nil {
+				channel, _ := msg.ParseData()
+				data, _ := msg.GetBody()
+
+				c.OnData(channel, data)
+			}
+
+			continue
+		}
+
+		c.dispatch(msg)
+	}
+}
+
+// setPending sets the request that waits for its response
+func (c *RTSPClient) setPending(p *pendingResponse) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	c.pending = p
+}
+
+// dispatch hands a response to the waiting request if the CSeq matches. Late responses of
+// abandoned requests and requests of the server are dropped.
+func (c *RTSPClient) dispatch(msg *RTSPMessage) {
+	if msg.GetType() != RtspMessageResponse {
+		return
+	}
+
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	if c.pending == nil || strings.TrimSpace(msg.Header(RtspHdrCseq)) != c.pending.cseq {
+		return
+	}
+
+	c.pending.responses <- msg
+	c.pending = nil
+}
+
+// parseResponse parses the status line of a response
+func parseResponse(msg *RTSPMessage) (*RTSPResponse, error) {
+	code, reason, _, res := msg.ParseResponse()
+	if res != RtspOK {
+		return nil, &RTSPResultError{Op: "parse response", Result: res}
+	}
+
+	return &RTSPResponse{
+		Message: msg,
+		Code:    code,
+		Reason:  reason,
+	}, nil
+}
+
+// receive receives the next message. Must be called while holding the reader.
+func (c *RTSPClient) receive(ctx context.Context) (*RTSPMessage, error) {
+	msg, res := NewRtspMessage()
+	if res != RtspOK {
+		return nil, &RTSPResultError{Op: "new message", Result: res}
+	}
+
+	err := c.withContext(ctx, "receive", func(timeout int64) RTSPResult {
+		return c.conn.ReceiveUsec(msg, timeout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// withContext calls fn with the timeout derived from the context and interrupts it when
+// the context is done.
+func (c *RTSPClient) withContext(ctx context.Context, op string, fn func(timeout int64) RTSPResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	timeout := c.Timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			return context.DeadlineExceeded
+		}
+	}
+
+	flushed := make(chan struct{})
+
+	stop := context.AfterFunc(ctx, func() {
+		defer close(flushed)
+		c.conn.Flush(true)
+	})
+
+	res := fn(timeout.Microseconds())
+
+	if !stop() {
+		// the pending operation was interrupted, make the connection usable again
+		<-flushed
+		c.conn.Flush(false)
+	}
+
+	if res == RtspOK {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return &RTSPResultError{Op: op, Result: res}
+}
+
+// setupAuth configures the connection for the strongest challenge of the response and
+// reports if the request should be retried. Must be called with the lock held.
+func (c *RTSPClient) setupAuth(resp *RTSPResponse) bool {
+	var chosen *RTSPAuthCredential
+
+	for _, cred := range resp.Message.ParseAuthCredentials(RtspHdrWwwAuthenticate) {
+		switch cred.GetScheme() {
+		case RtspAuthDigest:
+			chosen = cred
+		case RtspAuthBasic:
+			if chosen == nil {
+				chosen = cred
+			}
+		}
+	}
+
+	if chosen == nil {
+		return false
+	}
+
+	c.conn.ClearAuthParams()
+
+	if res := c.conn.SetAuth(chosen.GetScheme(), c.user, c.pass); res != RtspOK {
+		return false
+	}
+
+	for param := range chosen.Params() {
+		c.conn.SetAuthParam(param.GetName(), param.GetValue())
+	}
+
+	return true
+}
+
+// controlURI resolves the control attribute of a media against the base url
+func (c *RTSPClient) controlURI(control string) string {
+	if strings.HasPrefix(control, "rtsp://") || strings.HasPrefix(control, "rtsps://") {
+		return control
+	}
+
+	base := c.aggregateURI()
+
+	if control == "" || control == "*" {
+		return base
+	}
+
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	return base + control
+}
+
+// aggregateURI returns the url used for requests on the whole presentation
+func (c *RTSPClient) aggregateURI() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return strings.TrimSuffix(c.baseURI, "/")
+}
+
+// startKeepalive starts sending keepalives for the current session. Must be called with the lock held.
+func (c *RTSPClient) startKeepalive() {
+	if c.stopKeepalive != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.stopKeepalive = cancel
+	c.keepaliveDone = done
+
+	interval := max(c.sessionTimeout/2, time.Second)
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			reqctx, cancel := context.WithTimeout(ctx, interval)
+			c.GetParameter(reqctx)
+			cancel()
+		}
+	}()
+}
+
+// endSession stops the keepalive and forgets the session id
+func (c *RTSPClient) endSession() {
+	c.mu.Lock()
+	stop := c.stopKeepalive
+	done := c.keepaliveDone
+	c.stopKeepalive = nil
+	c.keepaliveDone = nil
+	c.session = ""
+	c.sessionTimeout = 0
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// parseSessionHeader splits a Session header of the form "id;timeout=60"
+func parseSessionHeader(header string) (string, time.Duration) {
+	id, params, _ := strings.Cut(header, ";")
+
+	timeout := DefaultRTSPSessionTimeout
+
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(key, "timeout") {
+			continue
+		}
+
+		if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+			timeout = time.Duration(secs) * time.Second
+		}
+	}
+
+	return strings.TrimSpace(id), timeout
+}
@@ -0,0 +1,328 @@
+package gstrtsp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gstrtsp"
+)
+
+const stubSDP = "v=0\r\n" +
+	"o=- 1 1 IN IP4 127.0.0.1\r\n" +
+	"s=stub\r\n" +
+	"t=0 0\r\n" +
+	"a=control:*\r\n" +
+	"m=video 0 RTP/AVP 96\r\n" +
+	"a=rtpmap:96 H264/90000\r\n" +
+	"a=control:stream=0\r\n"
+
+type stubRequest struct {
+	method  string
+	uri     string
+	headers textproto.MIMEHeader
+	body    string
+}
+
+// serveStubRTSP accepts a single connection and answers the requests of the client
+func serveStubRTSP(t *testing.T, user, pass string) (string, <-chan stubRequest) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { l.Close() })
+
+	requests := make(chan stubRequest, 32)
+	base := fmt.Sprintf("rtsp://%s/test", l.Addr())
+
+	go func() {
+		defer close(requests)
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := textproto.NewReader(bufio.NewReader(conn))
+		expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+
+			method, rest, _ := strings.Cut(line, " ")
+			uri, _, _ := strings.Cut(rest, " ")
+
+			headers, err := r.ReadMIMEHeader()
+			if err != nil {
+				return
+			}
+
+			var body []byte
+			if n, _ := strconv.Atoi(headers.Get("Content-Length")); n > 0 {
+				body = make([]byte, n)
+				if _, err := io.ReadFull(r.R, body); err != nil {
+					return
+				}
+			}
+
+			requests <- stubRequest{method: method, uri: uri, headers: headers, body: string(body)}
+
+			resp := "RTSP/1.0 200 OK\r\nCSeq: " + headers.Get("CSeq") + "\r\n"
+			var respBody string
+
+			switch method {
+			case "DESCRIBE":
+				if headers.Get("Authorization") != expectedAuth {
+					resp = "RTSP/1.0 401 Unauthorized\r\nCSeq: " + headers.Get("CSeq") + "\r\n" +
+						"WWW-Authenticate: Basic realm=\"stub\"\r\n"
+					break
+				}
+
+				resp += "Content-Base: " + base + "/\r\nContent-Type: application/sdp\r\n"
+				respBody = stubSDP
+			case "SETUP":
+				resp += "Session: 12345678;timeout=30\r\nTransport: " + headers.Get("Transport") + ";ssrc=1234ABCD\r\n"
+			case "PLAY":
+				resp += "Session: 12345678\r\nRTP-Info: url=" + base + "/stream=0;seq=1\r\n"
+			case "GET_PARAMETER":
+				// a late response of an abandoned request must be dropped by the client
+				resp = "RTSP/1.0 200 OK\r\nCSeq: 999\r\n\r\n" + resp
+				resp += "Session: 12345678\r\nContent-Type: text/parameters\r\n"
+				respBody = "position: 0\r\n"
+			default:
+				resp += "Session: 12345678\r\n"
+			}
+
+			if respBody != "" {
+				resp += "Content-Length: " + strconv.Itoa(len(respBody)) + "\r\n"
+			}
+
+			resp += "\r\n" + respBody
+
+			if _, err := conn.Write([]byte(resp)); err != nil {
+				return
+			}
+
+			if method == "GET_PARAMETER" {
+				// a single interleaved RTP packet on channel 0
+				if _, err := conn.Write([]byte{'$', 0, 0, 4, 0x80, 96, 0, 1}); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return base, requests
+}
+
+func TestRTSPClient(t *testing.T) {
+	base, requests := serveStubRTSP(t, "user", "secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := gstrtsp.DialRTSP(ctx, base)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	client.UserAgent = "go-gst-test"
+
+	_, resp, err := client.Describe(ctx)
+	if err == nil {
+		t.Fatal("expected describe without credentials to fail")
+	}
+
+	if resp == nil || resp.Code != gstrtsp.RtspStsUnauthorized {
+		t.Fatalf("expected 401 response, got %v", err)
+	}
+
+	client.SetCredentials("user", "secret")
+
+	sdp, _, err := client.Describe(ctx)
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+
+	if sdp.GetSessionName() != "stub" {
+		t.Errorf("unexpected session name %q", sdp.GetSessionName())
+	}
+
+	transport, _, err := client.Setup(ctx, "stream=0", "RTP/AVP/TCP;unicast;interleaved=0-1")
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	if transport.GetLowerTransport() != gstrtsp.RtspLowerTransTcp {
+		t.Errorf("unexpected lower transport %v", transport.GetLowerTransport())
+	}
+
+	if min, max := transport.GetInterleaved(); min != 0 || max != 1 {
+		t.Errorf("unexpected interleaved range %d-%d", min, max)
+	}
+
+	if transport.GetSSRC() != 0x1234ABCD {
+		t.Errorf("unexpected ssrc %x", transport.GetSSRC())
+	}
+
+	session, timeout := client.Session()
+	if session != "12345678" || timeout != 30*time.Second {
+		t.Errorf("unexpected session %q with timeout %v", session, timeout)
+	}
+
+	if _, err := client.Play(ctx, "npt=0-"); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+
+	type readResult struct {
+		channel uint8
+		data    []byte
+		err     error
+	}
+
+	// the stub only sends data after GET_PARAMETER, so the request is sent while ReadData blocks
+	read := make(chan readResult, 1)
+
+	go func() {
+		channel, data, err := client.ReadData(ctx)
+		read <- readResult{channel, data, err}
+	}()
+
+	resp, err = client.GetParameter(ctx, "position")
+	if err != nil {
+		t.Fatalf("get parameter: %v", err)
+	}
+
+	if body, _ := resp.Message.GetBody(); string(body) != "position: 0\r\n" {
+		t.Errorf("unexpected get parameter body %q", body)
+	}
+
+	r := <-read
+	if r.err != nil {
+		t.Fatalf("read data: %v", r.err)
+	}
+
+	if r.channel != 0 || len(r.data) != 4 {
+		t.Errorf("unexpected data on channel %d: %v", r.channel, r.data)
+	}
+
+	if _, err := client.Teardown(ctx); err != nil {
+		t.Fatalf("teardown: %v", err)
+	}
+
+	if session, _ := client.Session(); session != "" {
+		t.Errorf("session not cleared after teardown: %q", session)
+	}
+
+	client.Close()
+
+	var methods []string
+
+	for i := 1; ; i++ {
+		req, ok := <-requests
+		if !ok {
+			break
+		}
+
+		methods = append(methods, req.method)
+
+		if req.headers.Get("CSeq") != strconv.Itoa(i) {
+			t.Errorf("%s: unexpected cseq %q, expected %d", req.method, req.headers.Get("CSeq"), i)
+		}
+
+		if req.headers.Get("User-Agent") != "go-gst-test" {
+			t.Errorf("%s: unexpected user agent %q", req.method, req.headers.Get("User-Agent"))
+		}
+
+		switch req.method {
+		case "SETUP":
+			if req.uri != base+"/stream=0" {
+				t.Errorf("unexpected setup uri %q", req.uri)
+			}
+		case "PLAY", "GET_PARAMETER", "TEARDOWN":
+			if req.headers.Get("Session") != "12345678" {
+				t.Errorf("%s: unexpected session %q", req.method, req.headers.Get("Session"))
+			}
+
+			if req.uri != base {
+				t.Errorf("%s: unexpected uri %q", req.method, req.uri)
+			}
+		}
+
+		if req.method == "GET_PARAMETER" && req.body != "position\r\n" {
+			t.Errorf("unexpected get parameter body %q", req.body)
+		}
+	}
+
+	expected := "DESCRIBE DESCRIBE DESCRIBE SETUP PLAY GET_PARAMETER TEARDOWN"
+	if got := strings.Join(methods, " "); got != expected {
+		t.Errorf("unexpected requests %q, expected %q", got, expected)
+	}
+}
+
+func TestRTSPClientCloseStalled(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// the server reads the requests but never answers
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		io.Copy(io.Discard, conn)
+	}()
+
+	client, err := gstrtsp.DialRTSP(context.Background(), fmt.Sprintf("rtsp://%s/test", l.Addr()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		// neither the context nor the client limit the request
+		_, _, err := client.Options(context.Background())
+		done <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	closed := make(chan struct{})
+
+	go func() {
+		client.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close blocked on the stalled request")
+	}
+
+	if err := <-done; err != gstrtsp.ErrRTSPClientClosed {
+		t.Errorf("expected ErrRTSPClientClosed, got %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+package gstrtsp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-rtsp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtsp/rtsp.h>
+// static void _gstrtsp_message_set_version(GstRTSPMessage *msg, GstRTSPVersion version) {
+//   switch (msg->type) {
+//   case GST_RTSP_MESSAGE_REQUEST:
+//   case GST_RTSP_MESSAGE_HTTP_REQUEST:
+//     msg->type_data.request.version = version;
+//     break;
+//   case GST_RTSP_MESSAGE_RESPONSE:
+//   case GST_RTSP_MESSAGE_HTTP_RESPONSE:
+//     msg->type_data.response.version = version;
+//     break;
+//   default:
+//     break;
+//   }
+// }
+import "C"
+
+// ErrInvalidRTSPMessage is returned when raw data cannot be parsed into an [RTSPMessage].
+var ErrInvalidRTSPMessage = errors.New("invalid RTSP message")
+
+// GetBody wraps gst_rtsp_message_get_body
+//
+// The returned slice is a copy of the body of the message.
+func (msg *RTSPMessage) GetBody() ([]byte, RTSPResult) {
+	var carg0 *C.GstRTSPMessage // in, none, converted
+	var carg1 *C.guint8         // out, none, array
+	var carg2 C.guint           // out, none, casted
+	var cret C.GstRTSPResult    // return, none, casted
+
+	carg0 = (*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg))
+
+	cret = C.gst_rtsp_message_get_body(carg0, &carg1, &carg2)
+
+	var body []byte
+	var goret RTSPResult
+
+	if carg1 != nil && carg2 > 0 {
+		body = C.GoBytes(unsafe.Pointer(carg1), C.int(carg2))
+	}
+	runtime.KeepAlive(msg)
+
+	goret = RTSPResult(cret)
+
+	return body, goret
+}
+
+// SetBody wraps gst_rtsp_message_set_body
+//
+// The data is copied into the message.
+func (msg *RTSPMessage) SetBody(data []uint8) RTSPResult {
+	var carg0 *C.GstRTSPMessage // in, none, converted
+	var carg1 *C.guint8         // in, none, array
+	var carg2 C.guint           // in, none, casted
+	var cret C.GstRTSPResult    // return, none, casted
+
+	carg0 = (*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg))
+	if len(data) > 0 {
+		carg1 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+	carg2 = C.guint(len(data))
+
+	cret = C.gst_rtsp_message_set_body(carg0, carg1, carg2)
+	runtime.KeepAlive(msg)
+	runtime.KeepAlive(data)
+
+	var goret RTSPResult
+
+	goret = RTSPResult(cret)
+
+	return goret
+}
+
+// authCredentials owns the array returned by gst_rtsp_message_parse_auth_credentials
+type authCredentials struct {
+	native **C.GstRTSPAuthCredential
+}
+
+// ParseAuthCredentials wraps gst_rtsp_message_parse_auth_credentials
+//
+// The returned credentials are valid as long as they are referenced.
+func (msg *RTSPMessage) ParseAuthCredentials(field RTSPHeaderField) []*RTSPAuthCredential {
+	var carg0 *C.GstRTSPMessage        // in, none, converted
+	var carg1 C.GstRTSPHeaderField     // in, none, casted
+	var cret **C.GstRTSPAuthCredential // return, full, zero-terminated array
+
+	carg0 = (*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg))
+	carg1 = C.GstRTSPHeaderField(field)
+
+	cret = C.gst_rtsp_message_parse_auth_credentials(carg0, carg1)
+	runtime.KeepAlive(msg)
+	runtime.KeepAlive(field)
+
+	if cret == nil {
+		return nil
+	}
+
+	owner := &authCredentials{native: cret}
+	runtime.SetFinalizer(owner, func(owner *authCredentials) {
+		C.gst_rtsp_auth_credentials_free(owner.native)
+	})
+
+	var goret []*RTSPAuthCredential
+
+	for _, c := range unsafe.Slice(cret, 1<<16) {
+		if c == nil {
+			break
+		}
+
+		cred := UnsafeRTSPAuthCredentialFromGlibBorrow(unsafe.Pointer(c))
+
+		// the credential is owned by the array, so keep it alive:
+		runtime.AddCleanup(cred, func(_ *authCredentials) {}, owner)
+
+		goret = append(goret, cred)
+	}
+
+	return goret
+}
+
+// SetVersion sets the protocol version of a request or response message. Other
+// message types are not changed.
+func (msg *RTSPMessage) SetVersion(version RTSPVersion) {
+	C._gstrtsp_message_set_version((*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg)), C.GstRTSPVersion(version))
+	runtime.KeepAlive(msg)
+}
+
+// headerLines returns the serialized headers of the message, see gst_rtsp_message_append_headers
+func (msg *RTSPMessage) headerLines() string {
+	str := C.g_string_new(nil)
+	defer C.g_string_free(str, C.TRUE)
+
+	C.gst_rtsp_message_append_headers((*C.GstRTSPMessage)(UnsafeRTSPMessageToGlibNone(msg)), str)
+	runtime.KeepAlive(msg)
+
+	return C.GoStringN(str.str, C.int(str.len))
+}
+
+// HeadersByName returns an iterator over the header names and values of the message in
+// the order they were added.
+func (msg *RTSPMessage) HeadersByName() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, line := range strings.Split(msg.headerLines(), "\r\n") {
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+
+			if !yield(name, strings.TrimSpace(value)) {
+				return
+			}
+		}
+	}
+}
+
+// Headers returns an iterator over the header fields and values of the message in the order
+// they were added. Custom headers that have no [RTSPHeaderField] are yielded as [RtspHdrInvalid],
+// use [RTSPMessage.HeadersByName] to access their names.
+func (msg *RTSPMessage) Headers() iter.Seq2[RTSPHeaderField, string] {
+	return func(yield func(RTSPHeaderField, string) bool) {
+		for name, value := range msg.HeadersByName() {
+			if !yield(RtspFindHeaderField(name), value) {
+				return
+			}
+		}
+	}
+}
+
+// Header returns the first value of the header field or an empty string if the message
+// does not contain the header.
+func (msg *RTSPMessage) Header(field RTSPHeaderField) string {
+	value, res := msg.GetHeader(field, 0)
+	if res != RtspOK {
+		return ""
+	}
+
+	return value
+}
+
+// HeaderValues returns all values of the header field.
+func (msg *RTSPMessage) HeaderValues(field RTSPHeaderField) []string {
+	var values []string
+
+	for i := int32(0); ; i++ {
+		value, res := msg.GetHeader(field, i)
+		if res != RtspOK {
+			return values
+		}
+
+		values = append(values, value)
+	}
+}
+
+// SetHeader replaces all values of the header field with value.
+func (msg *RTSPMessage) SetHeader(field RTSPHeaderField, value string) RTSPResult {
+	msg.RemoveHeader(field, -1)
+
+	return msg.AddHeader(field, value)
+}
+
+// DumpString returns the message in its RTSP wire format, i.e. the request, status or
+// interleaved data line followed by the headers and the body. Unlike [RTSPMessage.Dump]
+// nothing is printed. An empty string is returned for interleaved data messages with a body
+// larger than 65535 bytes, which cannot be represented, [RTSPMessageBuilder.Body] rejects them.
+func (msg *RTSPMessage) DumpString() string {
+	var sb strings.Builder
+
+	body, _ := msg.GetBody()
+
+	switch msg.GetType() {
+	case RtspMessageRequest, RtspMessageHttpRequest:
+		method, uri, version, _ := msg.ParseRequest()
+		fmt.Fprintf(&sb, "%s %s %s/%s\r\n", RTSPMethodAsText(method), uri, protocolName(msg.GetType()), RTSPVersionAsText(version))
+	case RtspMessageResponse, RtspMessageHttpResponse:
+		code, reason, version, _ := msg.ParseResponse()
+		fmt.Fprintf(&sb, "%s/%s %d %s\r\n", protocolName(msg.GetType()), RTSPVersionAsText(version), int(code), reason)
+	case RtspMessageData:
+		if len(body) > rtspMaxDataSize {
+			return ""
+		}
+
+		channel, _ := msg.ParseData()
+
+		sb.Write([]byte{'$', channel, byte(len(body) >> 8), byte(len(body))})
+		sb.Write(body)
+
+		return sb.String()
+	default:
+		return ""
+	}
+
+	sb.WriteString(msg.headerLines())
+
+	if len(body) > 0 && msg.Header(RtspHdrContentLength) == "" {
+		fmt.Fprintf(&sb, "%s: %d\r\n", RtspHeaderAsText(RtspHdrContentLength), len(body))
+	}
+
+	sb.WriteString("\r\n")
+	sb.Write(body)
+
+	return sb.String()
+}
+
+func protocolName(t RTSPMsgType) string {
+	if t == RtspMessageHttpRequest || t == RtspMessageHttpResponse {
+		return "HTTP"
+	}
+
+	return "RTSP"
+}
+
+// ParseRTSPMessage parses the first RTSP request, response or interleaved data message
+// in data, e.g. as produced by [RTSPMessage.DumpString]. It returns the message and the
+// number of bytes consumed. The body length is taken from the Content-Length header.
+//
+// If data does not contain a complete message, then [io.ErrUnexpectedEOF] is returned.
+func ParseRTSPMessage(data []byte) (*RTSPMessage, int, error) {
+	if len(data) == 0 {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	if data[0] == '$' {
+		if len(data) < 4 {
+			return nil, 0, io.ErrUnexpectedEOF
+		}
+
+		size := int(data[2])<<8 | int(data[3])
+		if len(data) < 4+size {
+			return nil, 0, io.ErrUnexpectedEOF
+		}
+
+		msg, err := NewRTSPDataBuilder(data[1]).Body(data[4 : 4+size]).Build()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return msg, 4 + size, nil
+	}
+
+	end := bytes.Index(data, []byte("\r\n\r\n"))
+	if end < 0 {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	lines := strings.Split(string(data[:end]), "\r\n")
+	consumed := end + 4
+
+	b, err := builderFromStartLine(lines[0])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	contentLength := 0
+
+	for _, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: malformed header line %q", ErrInvalidRTSPMessage, line)
+		}
+
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		if RtspFindHeaderField(name) == RtspHdrContentLength {
+			contentLength, err = strconv.Atoi(value)
+			if err != nil || contentLength < 0 {
+				return nil, 0, fmt.Errorf("%w: invalid content length %q", ErrInvalidRTSPMessage, value)
+			}
+		}
+
+		b.HeaderByName(name, value)
+	}
+
+	if len(data) < consumed+contentLength {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	if contentLength > 0 {
+		b.Body(data[consumed : consumed+contentLength])
+		consumed += contentLength
+	}
+
+	msg, err := b.Build()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return msg, consumed, nil
+}
+
+// builderFromStartLine creates a request or response builder from the first line of a message
+func builderFromStartLine(line string) (*RTSPMessageBuilder, error) {
+	first, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed start line %q", ErrInvalidRTSPMessage, line)
+	}
+
+	if strings.HasPrefix(first, "RTSP/") {
+		version, err := parseVersion(first)
+		if err != nil {
+			return nil, err
+		}
+
+		codestr, reason, _ := strings.Cut(rest, " ")
+
+		code, err := strconv.Atoi(codestr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid status code %q", ErrInvalidRTSPMessage, codestr)
+		}
+
+		return NewRTSPResponseBuilder(RTSPStatusCode(code), reason, nil).Version(version), nil
+	}
+
+	uri, versionstr, ok := strings.Cut(rest, " ")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed request line %q", ErrInvalidRTSPMessage, line)
+	}
+
+	method := RtspFindMethod(first)
+	if method == RtspInvalid {
+		return nil, fmt.Errorf("%w: unknown method %q", ErrInvalidRTSPMessage, first)
+	}
+
+	version, err := parseVersion(versionstr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRTSPRequestBuilder(method, uri).Version(version), nil
+}
+
+func parseVersion(str string) (RTSPVersion, error) {
+	switch str {
+	case "RTSP/1.0":
+		return RtspVersion10, nil
+	case "RTSP/1.1":
+		return RtspVersion11, nil
+	case "RTSP/2.0":
+		return RtspVersion20, nil
+	default:
+		return RtspVersionInvalid, fmt.Errorf("%w: unsupported version %q", ErrInvalidRTSPMessage, str)
+	}
+}
@@ -0,0 +1,115 @@
+package gstrtsp_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gstrtsp"
+)
+
+func TestRTSPMessageRoundtrip(t *testing.T) {
+	msg, err := gstrtsp.NewRTSPRequestBuilder(gstrtsp.RtspSetParameter, "rtsp://127.0.0.1/test").
+		Header(gstrtsp.RtspHdrCseq, "3").
+		HeaderByName("X-Custom", "foo").
+		ContentBody("text/parameters", []byte("volume: 10\r\n")).
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	var fields []gstrtsp.RTSPHeaderField
+	for field := range msg.Headers() {
+		fields = append(fields, field)
+	}
+
+	expectedFields := []gstrtsp.RTSPHeaderField{gstrtsp.RtspHdrCseq, gstrtsp.RtspHdrInvalid, gstrtsp.RtspHdrContentType}
+	if len(fields) != len(expectedFields) {
+		t.Fatalf("unexpected headers %v", fields)
+	}
+
+	for i := range fields {
+		if fields[i] != expectedFields[i] {
+			t.Errorf("header %d: got %v, expected %v", i, fields[i], expectedFields[i])
+		}
+	}
+
+	raw := msg.DumpString()
+	expected := "SET_PARAMETER rtsp://127.0.0.1/test RTSP/1.0\r\n" +
+		"CSeq: 3\r\n" +
+		"X-Custom: foo\r\n" +
+		"Content-Type: text/parameters\r\n" +
+		"Content-Length: 12\r\n" +
+		"\r\n" +
+		"volume: 10\r\n"
+
+	if raw != expected {
+		t.Fatalf("unexpected dump:\n%q\nexpected:\n%q", raw, expected)
+	}
+
+	// trailing data of the next message must not be consumed
+	parsed, n, err := gstrtsp.ParseRTSPMessage([]byte(raw + "$\x00"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if n != len(raw) {
+		t.Errorf("consumed %d bytes, expected %d", n, len(raw))
+	}
+
+	if parsed.DumpString() != raw {
+		t.Errorf("roundtrip mismatch:\n%q", parsed.DumpString())
+	}
+
+	if v, _ := parsed.GetHeaderByName("X-Custom", 0); v != "foo" {
+		t.Errorf("unexpected custom header %q", v)
+	}
+
+	if _, _, err := gstrtsp.ParseRTSPMessage([]byte(raw[:len(raw)-2])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected unexpected EOF for truncated message, got %v", err)
+	}
+}
+
+func TestRTSPResponseBuilder(t *testing.T) {
+	req, _, err := gstrtsp.ParseRTSPMessage([]byte("OPTIONS * RTSP/1.0\r\nCSeq: 7\r\n\r\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	resp, err := gstrtsp.NewRTSPResponseBuilder(gstrtsp.RtspStsOK, "", req).
+		Header(gstrtsp.RtspHdrPublic, "OPTIONS, DESCRIBE").
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	if resp.Header(gstrtsp.RtspHdrCseq) != "7" {
+		t.Errorf("CSeq not copied from request: %q", resp.Header(gstrtsp.RtspHdrCseq))
+	}
+
+	expected := "RTSP/1.0 200 OK\r\nCSeq: 7\r\nPublic: OPTIONS, DESCRIBE\r\n\r\n"
+	if got := resp.DumpString(); got != expected {
+		t.Errorf("unexpected dump %q", got)
+	}
+
+	data, n, err := gstrtsp.ParseRTSPMessage([]byte{'$', 1, 0, 2, 0xab, 0xcd})
+	if err != nil || n != 6 {
+		t.Fatalf("parse data: %v", err)
+	}
+
+	if channel, _ := data.ParseData(); channel != 1 {
+		t.Errorf("unexpected channel %d", channel)
+	}
+
+	// the length field of interleaved data has 16 bits
+	_, err = gstrtsp.NewRTSPDataBuilder(0).Body(make([]byte, 0x10000)).Build()
+
+	var resErr *gstrtsp.RTSPResultError
+	if !errors.As(err, &resErr) || resErr.Result != gstrtsp.RtspEinval {
+		t.Errorf("expected EINVAL for oversized data, got %v", err)
+	}
+
+	if _, err := gstrtsp.NewRTSPDataBuilder(0).Body(make([]byte, 0xffff)).Build(); err != nil {
+		t.Errorf("build data: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package gstrtsp
+
+// rtspMaxDataSize is the largest body of an interleaved data message
+const rtspMaxDataSize = 0xffff
+
+// RTSPMessageBuilder builds an [RTSPMessage] with chained calls. The first failing call is
+// remembered and returned by [RTSPMessageBuilder.Build], all following calls are ignored.
+//
+//	msg, err := gstrtsp.NewRTSPRequestBuilder(gstrtsp.RtspDescribe, "rtsp://example.com/stream").
+//		Header(gstrtsp.RtspHdrAccept, "application/sdp").
+//		Header(gstrtsp.RtspHdrCseq, "1").
+//		Build()
+type RTSPMessageBuilder struct {
+	msg *RTSPMessage
+	err error
+}
+
+// NewRTSPRequestBuilder creates a builder for a request message.
+func NewRTSPRequestBuilder(method RTSPMethod, uri string) *RTSPMessageBuilder {
+	msg, res := RtspMessageNewRequest(method, uri)
+
+	return newRTSPMessageBuilder(msg, "new request", res)
+}
+
+// NewRTSPResponseBuilder creates a builder for a response message. If request is not nil,
+// then the CSeq and Session headers are copied from the request. If reason is empty, then
+// the default reason phrase of the code is used.
+func NewRTSPResponseBuilder(code RTSPStatusCode, reason string, request *RTSPMessage) *RTSPMessageBuilder {
+	msg, res := RtspMessageNewResponse(code, reason, request)
+
+	return newRTSPMessageBuilder(msg, "new response", res)
+}
+
+// NewRTSPDataBuilder creates a builder for an interleaved data message on the given channel.
+func NewRTSPDataBuilder(channel uint8) *RTSPMessageBuilder {
+	msg, res := RtspMessageNewData(channel)
+
+	return newRTSPMessageBuilder(msg, "new data", res)
+}
+
+func newRTSPMessageBuilder(msg *RTSPMessage, op string, res RTSPResult) *RTSPMessageBuilder {
+	b := &RTSPMessageBuilder{msg: msg}
+	b.check(op, res)
+
+	return b
+}
+
+// check remembers the first failed result
+func (b *RTSPMessageBuilder) check(op string, res RTSPResult) {
+	if b.err == nil && res != RtspOK {
+		b.err = &RTSPResultError{Op: op, Result: res}
+	}
+}
+
+// Version sets the protocol version of a request or response, the default is [RtspVersion10].
+func (b *RTSPMessageBuilder) Version(version RTSPVersion) *RTSPMessageBuilder {
+	if b.err == nil {
+		b.msg.SetVersion(version)
+	}
+
+	return b
+}
+
+// Header adds a header. Multiple values for the same field are kept in order.
+func (b *RTSPMessageBuilder) Header(field RTSPHeaderField, value string) *RTSPMessageBuilder {
+	if b.err == nil {
+		b.check("add header", b.msg.AddHeader(field, value))
+	}
+
+	return b
+}
+
+// HeaderByName adds a header by its name, which allows custom headers.
+func (b *RTSPMessageBuilder) HeaderByName(name string, value string) *RTSPMessageBuilder {
+	if b.err == nil {
+		b.check("add header", b.msg.AddHeaderByName(name, value))
+	}
+
+	return b
+}
+
+// SetHeader replaces all values of the header field with value.
+func (b *RTSPMessageBuilder) SetHeader(field RTSPHeaderField, value string) *RTSPMessageBuilder {
+	if b.err == nil {
+		b.check("set header", b.msg.SetHeader(field, value))
+	}
+
+	return b
+}
+
+// Body sets a copy of data as the body of the message. The Content-Length header is added when
+// the message is sent or dumped. The body of an interleaved data message is limited to 65535
+// bytes by its 16 bit length field, larger bodies fail with [RtspEinval].
+func (b *RTSPMessageBuilder) Body(data []byte) *RTSPMessageBuilder {
+	if b.err == nil && b.msg.GetType() == RtspMessageData && len(data) > rtspMaxDataSize {
+		b.check("set body", RtspEinval)
+	}
+
+	if b.err == nil {
+		b.check("set body", b.msg.SetBody(data))
+	}
+
+	return b
+}
+
+// ContentBody sets the Content-Type header and the body of the message.
+func (b *RTSPMessageBuilder) ContentBody(contentType string, data []byte) *RTSPMessageBuilder {
+	return b.SetHeader(RtspHdrContentType, contentType).Body(data)
+}
+
+// Build returns the message or the first error that occurred while building it.
+func (b *RTSPMessageBuilder) Build() (*RTSPMessage, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.msg, nil
+}
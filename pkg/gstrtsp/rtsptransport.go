@@ -0,0 +1,118 @@
+package gstrtsp
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-rtsp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtsp/rtsp.h>
+import "C"
+
+// RTSPTransportParse wraps gst_rtsp_transport_parse
+func RTSPTransportParse(str string) (*RTSPTransport, RTSPResult) {
+	transport, res := NewRTSPTransport()
+	if res != RtspOK {
+		return nil, res
+	}
+
+	var carg1 *C.gchar            // in, none, string
+	var carg2 *C.GstRTSPTransport // out, caller-allocates
+	var cret C.GstRTSPResult      // return, none, casted
+
+	carg1 = (*C.gchar)(unsafe.Pointer(C.CString(str)))
+	defer C.free(unsafe.Pointer(carg1))
+	carg2 = (*C.GstRTSPTransport)(UnsafeRTSPTransportToGlibNone(transport))
+
+	cret = C.gst_rtsp_transport_parse(carg1, carg2)
+	runtime.KeepAlive(str)
+	runtime.KeepAlive(transport)
+
+	var goret RTSPResult
+
+	goret = RTSPResult(cret)
+
+	if goret != RtspOK {
+		return nil, goret
+	}
+
+	return transport, goret
+}
+
+// getters for the fields of GstRTSPTransport:
+
+// GetTrans returns the transport mode
+func (t *RTSPTransport) GetTrans() RTSPTransMode {
+	return RTSPTransMode(t.native.trans)
+}
+
+// GetProfile returns the transport profile
+func (t *RTSPTransport) GetProfile() RTSPProfile {
+	return RTSPProfile(t.native.profile)
+}
+
+// GetLowerTransport returns the lower transport
+func (t *RTSPTransport) GetLowerTransport() RTSPLowerTrans {
+	return RTSPLowerTrans(t.native.lower_transport)
+}
+
+// GetDestination returns the destination ip/hostname
+func (t *RTSPTransport) GetDestination() string {
+	return C.GoString(t.native.destination)
+}
+
+// GetSource returns the source ip/hostname
+func (t *RTSPTransport) GetSource() string {
+	return C.GoString(t.native.source)
+}
+
+// GetLayers returns the number of layers
+func (t *RTSPTransport) GetLayers() uint {
+	return uint(t.native.layers)
+}
+
+// GetModePlay returns true if play mode was selected
+func (t *RTSPTransport) GetModePlay() bool {
+	return t.native.mode_play != 0
+}
+
+// GetModeRecord returns true if record mode was selected
+func (t *RTSPTransport) GetModeRecord() bool {
+	return t.native.mode_record != 0
+}
+
+// GetAppend returns true if append mode was selected
+func (t *RTSPTransport) GetAppend() bool {
+	return t.native.append != 0
+}
+
+// GetInterleaved returns the interleave range
+func (t *RTSPTransport) GetInterleaved() (min int, max int) {
+	return int(t.native.interleaved.min), int(t.native.interleaved.max)
+}
+
+// GetTTL returns the time to live for multicast UDP
+func (t *RTSPTransport) GetTTL() uint {
+	return uint(t.native.ttl)
+}
+
+// GetPort returns the port pair for multicast sessions
+func (t *RTSPTransport) GetPort() (min int, max int) {
+	return int(t.native.port.min), int(t.native.port.max)
+}
+
+// GetClientPort returns the client port pair for receiving data
+func (t *RTSPTransport) GetClientPort() (min int, max int) {
+	return int(t.native.client_port.min), int(t.native.client_port.max)
+}
+
+// GetServerPort returns the server port pair for receiving data
+func (t *RTSPTransport) GetServerPort() (min int, max int) {
+	return int(t.native.server_port.min), int(t.native.server_port.max)
+}
+
+// GetSSRC returns the ssrc that the sender/receiver will use
+func (t *RTSPTransport) GetSSRC() uint {
+	return uint(t.native.ssrc)
+}
@@ -0,0 +1,38 @@
+package gstrtsp
+
+// #cgo pkg-config: gstreamer-rtsp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/rtsp/rtsp.h>
+import "C"
+
+// getters for the fields of GstRTSPUrl:
+
+// GetTransports returns the allowed lower transports of the url
+func (url *RTSPUrl) GetTransports() RTSPLowerTrans {
+	return RTSPLowerTrans(url.native.transports)
+}
+
+// GetUser returns the user of the url
+func (url *RTSPUrl) GetUser() string {
+	return C.GoString(url.native.user)
+}
+
+// GetPasswd returns the password of the url
+func (url *RTSPUrl) GetPasswd() string {
+	return C.GoString(url.native.passwd)
+}
+
+// GetHost returns the host of the url
+func (url *RTSPUrl) GetHost() string {
+	return C.GoString(url.native.host)
+}
+
+// GetAbspath returns the absolute path of the url
+func (url *RTSPUrl) GetAbspath() string {
+	return C.GoString(url.native.abspath)
+}
+
+// GetQuery returns the query of the url
+func (url *RTSPUrl) GetQuery() string {
+	return C.GoString(url.native.query)
+}
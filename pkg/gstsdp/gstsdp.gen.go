@@ -4548,38 +4548,6 @@ func (msg *SDPMessage) AddPhone(phone string) SDPResult {
 	return goret
 }
 
-// AddTime wraps gst_sdp_message_add_time
-// 
-// see also https://gstreamer.freedesktop.org/documentation/sdp/gstsdpmessage.html#gst_sdp_message_add_time
-func (msg *SDPMessage) AddTime(start string, stop string, repeat []string) SDPResult {
-	var carg0 *C.GstSDPMessage // in, none, converted
-	var carg1 *C.gchar         // in, none, string
-	var carg2 *C.gchar         // in, none, string
-	var carg3 **C.gchar        // in, transfer: none, C Pointers: 2, Name: array[utf8], array (inner gchar* (*typesystem.StringPrimitive), zero-terminated)
-	var cret  C.GstSDPResult   // return, none, casted
-
-	carg0 = (*C.GstSDPMessage)(UnsafeSDPMessageToGlibNone(msg))
-	carg1 = (*C.gchar)(transfer.GLibString(start))
-	defer C.g_free(C.gpointer(carg1))
-	carg2 = (*C.gchar)(transfer.GLibString(stop))
-	defer C.g_free(C.gpointer(carg2))
-	_ = repeat
-	_ = carg3
-	panic("unimplemented conversion of []string (const gchar**) because of unimplemented: inner pointers in array")
-
-	cret = C.gst_sdp_message_add_time(carg0, carg1, carg2, carg3)
-	runtime.KeepAlive(msg)
-	runtime.KeepAlive(start)
-	runtime.KeepAlive(stop)
-	runtime.KeepAlive(repeat)
-
-	var goret SDPResult
-
-	goret = SDPResult(cret)
-
-	return goret
-}
-
 // AddZone wraps gst_sdp_message_add_zone
 // 
 // see also https://gstreamer.freedesktop.org/documentation/sdp/gstsdpmessage.html#gst_sdp_message_add_zone
@@ -0,0 +1,237 @@
+package gstsdp
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// MediaRTPCaps are the RTP caps of all payload types of a single media section of an SDP message.
+type MediaRTPCaps struct {
+	// Index is the index of the media section in the message.
+	Index uint
+	// Media is the media type, e.g. "audio" or "video".
+	Media string
+	// Caps contains an application/x-rtp caps for every payload type of the media in the order
+	// of the m= line.
+	Caps []*gst.Caps
+}
+
+// CapsForPayload returns the caps of the given payload type or nil if the media has no such payload.
+func (m *MediaRTPCaps) CapsForPayload(pt int32) *gst.Caps {
+	for _, caps := range m.Caps {
+		if caps.GetSize() == 0 {
+			continue
+		}
+
+		if p, ok := caps.GetStructure(0).GetInt("payload"); ok && p == pt {
+			return caps
+		}
+	}
+
+	return nil
+}
+
+// RTPCaps returns the application/x-rtp caps for every media section of the message.
+//
+// The caps contain the payload information of the rtpmap, fmtp and rtcp-fb attributes,
+// as well as the extmap, ssrc and other session and media level attributes. If the media
+// uses MIKEY key management, then the srtp and srtcp keying fields are added, too.
+//
+// Media sections with formats that are not RTP payload types are skipped.
+func (msg *SDPMessage) RTPCaps() ([]MediaRTPCaps, error) {
+	var medias []MediaRTPCaps
+
+	for i, media := range msg.Medias() {
+		caps, err := media.RTPCaps(msg)
+		if err != nil {
+			return nil, fmt.Errorf("media %d: %w", i, err)
+		}
+
+		if len(caps) == 0 {
+			continue
+		}
+
+		medias = append(medias, MediaRTPCaps{
+			Index: i,
+			Media: media.GetMedia(),
+			Caps:  caps,
+		})
+	}
+
+	return medias, nil
+}
+
+// RTPCaps returns the application/x-rtp caps for every payload type of the media. If msg
+// is not nil, then the session level attributes of the message are added to the caps as well.
+func (media *SDPMedia) RTPCaps(msg *SDPMessage) ([]*gst.Caps, error) {
+	var result []*gst.Caps
+
+	for _, format := range media.Formats() {
+		pt, err := strconv.ParseInt(format, 10, 32)
+		if err != nil {
+			// not an RTP payload type
+			continue
+		}
+
+		caps := media.GetCapsFromMedia(int32(pt))
+		if caps == nil {
+			return nil, fmt.Errorf("%w: no caps for payload type %d", ErrInvalidSDP, pt)
+		}
+
+		if msg != nil {
+			if res := msg.AttributesToCaps(caps); res != SdpOK {
+				return nil, fmt.Errorf("%w: could not add session attributes for payload type %d: %s", ErrInvalidSDP, pt, res)
+			}
+		}
+
+		if res := media.AttributesToCaps(caps); res != SdpOK {
+			return nil, fmt.Errorf("%w: could not add media attributes for payload type %d: %s", ErrInvalidSDP, pt, res)
+		}
+
+		result = append(result, caps)
+	}
+
+	return result, nil
+}
+
+// NewSDPMediaFromRTPCaps creates a new media section from the caps of one or more payloaders,
+// e.g. the caps of the src pad of rtpopuspay. All caps must have the same media type.
+//
+// The port of the media is set to 9 and can be changed afterwards. If the caps contain srtp keying
+// fields, then a MIKEY key-mgmt attribute is added.
+func NewSDPMediaFromRTPCaps(proto string, caps ...*gst.Caps) (*SDPMedia, error) {
+	if len(caps) == 0 {
+		return nil, fmt.Errorf("%w: no caps given", ErrInvalidSDP)
+	}
+
+	for i, c := range caps {
+		if c.GetSize() == 0 {
+			return nil, fmt.Errorf("%w: caps %d are empty", ErrInvalidSDP, i)
+		}
+	}
+
+	media, res := NewSDPMedia()
+	if res != SdpOK {
+		return nil, fmt.Errorf("%w: could not create media: %s", ErrInvalidSDP, res)
+	}
+
+	mediaType := caps[0].GetStructure(0).GetString("media")
+
+	results := []SDPResult{
+		media.SetPortInfo(9, 1),
+		media.SetProto(proto),
+	}
+
+	hasKey := false
+
+	for i, c := range caps {
+		s := c.GetStructure(0)
+
+		if t := s.GetString("media"); t != mediaType {
+			return nil, fmt.Errorf("%w: caps %d have media type %q, expected %q", ErrInvalidSDP, i, t, mediaType)
+		}
+
+		results = append(results, media.SetMediaFromCaps(c))
+
+		if ssrc, ok := s.GetUint("ssrc"); ok {
+			if cname := s.GetString(fmt.Sprintf("ssrc-%d-cname", ssrc)); cname != "" {
+				results = append(results, media.AddAttribute("ssrc", fmt.Sprintf("%d cname:%s", ssrc, cname)))
+			}
+		}
+
+		if !hasKey && s.HasField("srtp-key") {
+			if mikey := NewMIKEYMessageFromCaps(c); mikey != nil {
+				results = append(results, media.AddAttribute("key-mgmt", "mikey "+mikey.Base64Encode()))
+				hasKey = true
+			}
+		}
+	}
+
+	for _, res := range results {
+		if res != SdpOK {
+			return nil, fmt.Errorf("%w: could not set media from caps: %s", ErrInvalidSDP, res)
+		}
+	}
+
+	media.dedupAttributes()
+
+	return media, nil
+}
+
+// NewSDPMessageFromRTPCaps creates a new SDP message from the caps of one or more payloaders. The
+// caps are grouped into media sections by their media type in order of their first occurrence.
+//
+// The message contains placeholder origin, session name and timing fields, that can be replaced
+// afterwards.
+func NewSDPMessageFromRTPCaps(proto string, caps ...*gst.Caps) (*SDPMessage, error) {
+	msg, res := NewSDPMessage()
+	if res != SdpOK {
+		return nil, fmt.Errorf("%w: could not create message: %s", ErrInvalidSDP, res)
+	}
+
+	results := []SDPResult{
+		msg.SetVersion("0"),
+		msg.SetOrigin("-", "0", "0", "IN", "IP4", "127.0.0.1"),
+		msg.SetSessionName("-"),
+		msg.AddTime("0", "0", nil),
+	}
+
+	for _, res := range results {
+		if res != SdpOK {
+			return nil, fmt.Errorf("%w: could not set session fields: %s", ErrInvalidSDP, res)
+		}
+	}
+
+	var order []string
+	grouped := make(map[string][]*gst.Caps)
+
+	for _, c := range caps {
+		if c.GetSize() == 0 {
+			return nil, fmt.Errorf("%w: empty caps", ErrInvalidSDP)
+		}
+
+		mediaType := c.GetStructure(0).GetString("media")
+
+		if _, ok := grouped[mediaType]; !ok {
+			order = append(order, mediaType)
+		}
+
+		grouped[mediaType] = append(grouped[mediaType], c)
+	}
+
+	for _, mediaType := range order {
+		media, err := NewSDPMediaFromRTPCaps(proto, grouped[mediaType]...)
+		if err != nil {
+			return nil, err
+		}
+
+		if res := msg.AddMedia(media); res != SdpOK {
+			return nil, fmt.Errorf("%w: could not add %s media: %s", ErrInvalidSDP, mediaType, res)
+		}
+	}
+
+	return msg, nil
+}
+
+// dedupAttributes removes attributes with the same key and value, e.g. extmaps that were
+// added for multiple payload types.
+func (media *SDPMedia) dedupAttributes() {
+	type attr struct{ key, value string }
+
+	seen := make(map[attr]struct{})
+
+	for i := uint(0); i < media.AttributesLen(); {
+		a := media.GetAttribute(i)
+		k := attr{a.GetKey(), a.GetValue()}
+
+		if _, ok := seen[k]; ok {
+			media.RemoveAttribute(i)
+			continue
+		}
+
+		seen[k] = struct{}{}
+		i++
+	}
+}
@@ -0,0 +1,61 @@
+package gstsdp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstsdp"
+)
+
+func TestRTPCaps(t *testing.T) {
+	gst.Init()
+
+	opus := gst.CapsFromString("application/x-rtp, media=(string)audio, payload=(int)111, clock-rate=(int)48000, encoding-name=(string)OPUS")
+	pcmu := gst.CapsFromString("application/x-rtp, media=(string)audio, payload=(int)0, clock-rate=(int)8000, encoding-name=(string)PCMU")
+
+	msg, err := gstsdp.NewSDPMessageFromRTPCaps("RTP/AVP", opus, pcmu)
+	if err != nil {
+		t.Fatalf("could not create message: %v", err)
+	}
+
+	medias, err := msg.RTPCaps()
+	if err != nil {
+		t.Fatalf("could not convert message: %v", err)
+	}
+
+	if len(medias) != 1 || medias[0].Media != "audio" || len(medias[0].Caps) != 2 {
+		t.Fatalf("unexpected medias %+v", medias)
+	}
+
+	caps := medias[0].CapsForPayload(0)
+	if caps == nil {
+		t.Fatal("no caps for payload 0")
+	}
+
+	if name := caps.GetStructure(0).GetString("encoding-name"); name != "PCMU" {
+		t.Errorf("unexpected encoding name %q", name)
+	}
+
+	if caps := medias[0].CapsForPayload(8); caps != nil {
+		t.Errorf("unexpected caps for payload 8: %v", caps)
+	}
+}
+
+func TestRTPCapsEmpty(t *testing.T) {
+	gst.Init()
+
+	if _, err := gstsdp.NewSDPMediaFromRTPCaps("RTP/AVP", gst.NewCapsEmpty()); !errors.Is(err, gstsdp.ErrInvalidSDP) {
+		t.Errorf("expected ErrInvalidSDP for empty caps, got %v", err)
+	}
+
+	if _, err := gstsdp.NewSDPMessageFromRTPCaps("RTP/AVP", gst.NewCapsEmpty()); !errors.Is(err, gstsdp.ErrInvalidSDP) {
+		t.Errorf("expected ErrInvalidSDP for empty caps, got %v", err)
+	}
+
+	media := gstsdp.MediaRTPCaps{Media: "audio", Caps: []*gst.Caps{gst.NewCapsEmpty()}}
+
+	if caps := media.CapsForPayload(0); caps != nil {
+		t.Errorf("unexpected caps for payload 0: %v", caps)
+	}
+}
@@ -0,0 +1,18 @@
+package gstsdp
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// getters for the fields of GstSDPBandwidth:
+
+// GetBwtype returns the bandwidth modifier type of the SDPBandwidth
+func (b *SDPBandwidth) GetBwtype() string {
+	return C.GoString(b.native.bwtype)
+}
+
+// GetBandwidth returns the bandwidth in kilobits per second
+func (b *SDPBandwidth) GetBandwidth() uint {
+	return uint(b.native.bandwidth)
+}
@@ -0,0 +1,33 @@
+package gstsdp
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// getters for the fields of GstSDPConnection:
+
+// GetNettype returns the network type of the SDPConnection
+func (c *SDPConnection) GetNettype() string {
+	return C.GoString(c.native.nettype)
+}
+
+// GetAddrtype returns the address type of the SDPConnection
+func (c *SDPConnection) GetAddrtype() string {
+	return C.GoString(c.native.addrtype)
+}
+
+// GetAddress returns the address of the SDPConnection
+func (c *SDPConnection) GetAddress() string {
+	return C.GoString(c.native.address)
+}
+
+// GetTTL returns the time to live of the SDPConnection
+func (c *SDPConnection) GetTTL() uint {
+	return uint(c.native.ttl)
+}
+
+// GetAddrNumber returns the number of layers of the SDPConnection
+func (c *SDPConnection) GetAddrNumber() uint {
+	return uint(c.native.addr_number)
+}
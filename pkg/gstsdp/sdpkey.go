@@ -0,0 +1,18 @@
+package gstsdp
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// getters for the fields of GstSDPKey:
+
+// GetType returns the encryption type of the SDPKey
+func (k *SDPKey) GetType() string {
+	return C.GoString(k.native._type)
+}
+
+// GetData returns the encryption data of the SDPKey
+func (k *SDPKey) GetData() string {
+	return C.GoString(k.native.data)
+}
@@ -1,6 +1,16 @@
 package gstsdp
 
-import "iter"
+import (
+	"iter"
+	"runtime"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
 
 // Getters for SDPMedia properties.
 // All simple fields already have getters generated, we need to create iterators for the GArrays.
@@ -37,3 +47,28 @@ func (m *SDPMedia) Bandwidths() iter.Seq2[uint, *SDPBandwidth] {
 func (m *SDPMedia) Attributes() iter.Seq2[uint, *SDPAttribute] {
 	return getIter(m.AttributesLen(), m.GetAttribute)
 }
+
+// SetMediaFromCaps wraps gst_sdp_media_set_media_from_caps
+//
+// It adds the format, rtpmap, fmtp, rtcp-fb and extmap attributes described by the first structure
+// of the caps to the media.
+//
+// see also https://gstreamer.freedesktop.org/documentation/sdp/gstsdpmessage.html#gst_sdp_media_set_media_from_caps
+func (media *SDPMedia) SetMediaFromCaps(caps *gst.Caps) SDPResult {
+	var carg1 *C.GstCaps     // in, none, converted
+	var carg2 *C.GstSDPMedia // in, none, converted
+	var cret C.GstSDPResult  // return, none, casted
+
+	carg1 = (*C.GstCaps)(gst.UnsafeCapsToGlibNone(caps))
+	carg2 = (*C.GstSDPMedia)(UnsafeSDPMediaToGlibNone(media))
+
+	cret = C.gst_sdp_media_set_media_from_caps(carg1, carg2)
+	runtime.KeepAlive(caps)
+	runtime.KeepAlive(media)
+
+	var goret SDPResult
+
+	goret = SDPResult(cret)
+
+	return goret
+}
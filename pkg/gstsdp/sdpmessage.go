@@ -2,6 +2,8 @@ package gstsdp
 
 import (
 	"iter"
+	"runtime"
+	"unsafe"
 )
 
 // #cgo pkg-config: gstreamer-sdp-1.0
@@ -63,3 +65,43 @@ func (s *SDPMessage) Attributes() iter.Seq2[uint, *SDPAttribute] {
 func (s *SDPMessage) Medias() iter.Seq2[uint, *SDPMedia] {
 	return getIter(s.MediasLen(), s.GetMedia)
 }
+
+// AddTime wraps gst_sdp_message_add_time
+//
+// see also https://gstreamer.freedesktop.org/documentation/sdp/gstsdpmessage.html#gst_sdp_message_add_time
+func (msg *SDPMessage) AddTime(start string, stop string, repeat []string) SDPResult {
+	var carg0 *C.GstSDPMessage // in, none, converted
+	var carg1 *C.gchar         // in, none, string
+	var carg2 *C.gchar         // in, none, string
+	var carg3 **C.gchar        // in, none, array of strings, zero-terminated
+	var cret C.GstSDPResult    // return, none, casted
+
+	carg0 = (*C.GstSDPMessage)(UnsafeSDPMessageToGlibNone(msg))
+	carg1 = (*C.gchar)(unsafe.Pointer(C.CString(start)))
+	defer C.free(unsafe.Pointer(carg1))
+	carg2 = (*C.gchar)(unsafe.Pointer(C.CString(stop)))
+	defer C.free(unsafe.Pointer(carg2))
+
+	// the array is allocated in C memory, because it contains pointers to C memory
+	repeats := (**C.gchar)(C.calloc(C.size_t(len(repeat)+1), C.size_t(unsafe.Sizeof(carg3))))
+	defer C.free(unsafe.Pointer(repeats))
+
+	repeatSlice := unsafe.Slice(repeats, len(repeat)+1)
+	for i, r := range repeat {
+		repeatSlice[i] = (*C.gchar)(unsafe.Pointer(C.CString(r)))
+		defer C.free(unsafe.Pointer(repeatSlice[i]))
+	}
+	carg3 = repeats
+
+	cret = C.gst_sdp_message_add_time(carg0, carg1, carg2, carg3)
+	runtime.KeepAlive(msg)
+	runtime.KeepAlive(start)
+	runtime.KeepAlive(stop)
+	runtime.KeepAlive(repeat)
+
+	var goret SDPResult
+
+	goret = SDPResult(cret)
+
+	return goret
+}
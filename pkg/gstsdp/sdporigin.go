@@ -0,0 +1,38 @@
+package gstsdp
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// getters for the fields of GstSDPOrigin:
+
+// GetUsername returns the user's login on the originating host
+func (o *SDPOrigin) GetUsername() string {
+	return C.GoString(o.native.username)
+}
+
+// GetSessID returns the session id of the SDPOrigin
+func (o *SDPOrigin) GetSessID() string {
+	return C.GoString(o.native.sess_id)
+}
+
+// GetSessVersion returns the session version of the SDPOrigin
+func (o *SDPOrigin) GetSessVersion() string {
+	return C.GoString(o.native.sess_version)
+}
+
+// GetNettype returns the network type of the SDPOrigin
+func (o *SDPOrigin) GetNettype() string {
+	return C.GoString(o.native.nettype)
+}
+
+// GetAddrtype returns the address type of the SDPOrigin
+func (o *SDPOrigin) GetAddrtype() string {
+	return C.GoString(o.native.addrtype)
+}
+
+// GetAddr returns the address of the originating host
+func (o *SDPOrigin) GetAddr() string {
+	return C.GoString(o.native.addr)
+}
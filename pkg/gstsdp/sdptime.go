@@ -0,0 +1,45 @@
+package gstsdp
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// getters for the fields of GstSDPTime:
+
+// GetStart returns the start time for the conference. The value is the decimal
+// representation of Network Time Protocol (NTP) time values in seconds
+func (t *SDPTime) GetStart() string {
+	return C.GoString(t.native.start)
+}
+
+// GetStop returns the stop time for the conference
+func (t *SDPTime) GetStop() string {
+	return C.GoString(t.native.stop)
+}
+
+// RepeatsLen returns the number of repeat times of the SDPTime
+func (t *SDPTime) RepeatsLen() uint {
+	if t.native.repeat == nil {
+		return 0
+	}
+
+	return uint(t.native.repeat.len)
+}
+
+// GetRepeat returns the repeat time at the given index
+func (t *SDPTime) GetRepeat(idx uint) string {
+	repeats := unsafe.Slice((**C.gchar)(unsafe.Pointer(t.native.repeat.data)), t.native.repeat.len)
+
+	return C.GoString(repeats[idx])
+}
+
+// Repeats returns an iterator over the repeat times of the SDPTime
+func (t *SDPTime) Repeats() iter.Seq2[uint, string] {
+	return getIter(t.RepeatsLen(), t.GetRepeat)
+}
@@ -0,0 +1,18 @@
+package gstsdp
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// getters for the fields of GstSDPZone:
+
+// GetTime returns the NTP time that a time zone adjustment happens
+func (z *SDPZone) GetTime() string {
+	return C.GoString(z.native.time)
+}
+
+// GetTypedTime returns the offset from the time when the session was first scheduled
+func (z *SDPZone) GetTypedTime() string {
+	return C.GoString(z.native.typed_time)
+}
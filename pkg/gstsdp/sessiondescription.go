@@ -0,0 +1,636 @@
+package gstsdp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// #cgo pkg-config: gstreamer-sdp-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/sdp/sdp.h>
+import "C"
+
+// ErrInvalidSDP is returned when an SDP message cannot be parsed or built.
+var ErrInvalidSDP = errors.New("invalid SDP")
+
+// ParseSDP parses the given text into a new SDPMessage.
+func ParseSDP(text string) (*SDPMessage, error) {
+	msg, res := NewSDPMessageFromText(text)
+	if res != SdpOK {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSDP, res)
+	}
+
+	return msg, nil
+}
+
+// String returns the SDP text representation of the message.
+func (msg *SDPMessage) String() string {
+	return msg.AsText()
+}
+
+// SessionDescription is a plain go representation of an [SDPMessage]. It can be freely modified
+// and converted back into an [SDPMessage] with [SessionDescription.SDPMessage].
+type SessionDescription struct {
+	Version     string
+	Origin      Origin
+	SessionName string
+	Information string
+	URI         string
+	Emails      []string
+	Phones      []string
+	// Connection is nil if the session has no session level connection
+	Connection *Connection
+	Bandwidths []Bandwidth
+	Times      []Time
+	Zones      []Zone
+	// Key is nil if the session has no session level encryption key
+	Key        *Key
+	Attributes []Attribute
+	Media      []Media
+}
+
+// Origin is the o= line of a session description.
+type Origin struct {
+	Username       string
+	SessionID      string
+	SessionVersion string
+	NetType        string
+	AddrType       string
+	Address        string
+}
+
+// Connection is a c= line of a session description.
+type Connection struct {
+	NetType    string
+	AddrType   string
+	Address    string
+	TTL        uint
+	AddrNumber uint
+}
+
+// Bandwidth is a b= line of a session description. The bandwidth is given in kilobits per second.
+type Bandwidth struct {
+	Type      string
+	Bandwidth uint
+}
+
+// Time is a t= line with its r= lines of a session description.
+type Time struct {
+	Start   string
+	Stop    string
+	Repeats []string
+}
+
+// Zone is a single adjustment of the z= line of a session description.
+type Zone struct {
+	Time      string
+	TypedTime string
+}
+
+// Key is a k= line of a session description.
+type Key struct {
+	Type string
+	Data string
+}
+
+// Attribute is a generic a= line of a session description.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Media is an m= section of a session description.
+type Media struct {
+	Media    string
+	Port     uint
+	NumPorts uint
+	Proto    string
+	// Formats are the formats of the m= line in order. The rtpmap, fmtp and rtcp-fb attributes
+	// of the formats are decoded into the format and are not part of Attributes.
+	Formats     []Format
+	Information string
+	Connections []Connection
+	Bandwidths  []Bandwidth
+	// Key is nil if the media has no encryption key
+	Key *Key
+	// Attributes contains all media attributes that are not decoded into Formats.
+	Attributes []Attribute
+}
+
+// Format is a single format of an m= line, e.g. an RTP payload type, with its decoded attributes.
+type Format struct {
+	// Format is the format as written in the m= line, e.g. "96".
+	Format string
+	// RTPMap is nil if the format has no rtpmap attribute
+	RTPMap *RTPMap
+	// Parameters are the decoded parameters of the fmtp attribute in order.
+	Parameters []FormatParameter
+	// Feedback contains the values of the rtcp-fb attributes of this format, e.g. "nack pli".
+	Feedback []string
+}
+
+// RTPMap is the decoded value of an rtpmap attribute.
+type RTPMap struct {
+	EncodingName string
+	ClockRate    uint
+	// EncodingParams are the optional encoding parameters, e.g. the number of audio channels.
+	EncodingParams string
+}
+
+// String returns the rtpmap attribute value without the payload type.
+func (r RTPMap) String() string {
+	s := fmt.Sprintf("%s/%d", r.EncodingName, r.ClockRate)
+
+	if r.EncodingParams != "" {
+		s += "/" + r.EncodingParams
+	}
+
+	return s
+}
+
+// FormatParameter is a single parameter of an fmtp attribute. Parameters that are not in
+// key=value form (e.g. "0-15" for telephone-event) are stored in Key with an empty Value.
+type FormatParameter struct {
+	Key   string
+	Value string
+}
+
+// MediaOfType returns the first media section of the given media type, e.g. "audio", or nil
+// if there is none.
+func (d *SessionDescription) MediaOfType(media string) *Media {
+	for i := range d.Media {
+		if d.Media[i].Media == media {
+			return &d.Media[i]
+		}
+	}
+
+	return nil
+}
+
+// Attribute returns the value of the first attribute with the given key.
+func (m *Media) Attribute(key string) (string, bool) {
+	for _, a := range m.Attributes {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Format returns the format with the given payload, e.g. "96", or nil if there is none.
+func (m *Media) Format(format string) *Format {
+	for i := range m.Formats {
+		if m.Formats[i].Format == format {
+			return &m.Formats[i]
+		}
+	}
+
+	return nil
+}
+
+// FilterFormats removes all formats for which keep returns false.
+func (m *Media) FilterFormats(keep func(f *Format) bool) {
+	formats := m.Formats[:0]
+
+	for i := range m.Formats {
+		if keep(&m.Formats[i]) {
+			formats = append(formats, m.Formats[i])
+		}
+	}
+
+	m.Formats = formats
+}
+
+// Parameter returns the value of the fmtp parameter with the given key.
+func (f *Format) Parameter(key string) (string, bool) {
+	for _, p := range f.Parameters {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SetParameter sets the fmtp parameter with the given key, replacing the existing value.
+func (f *Format) SetParameter(key, value string) {
+	for i, p := range f.Parameters {
+		if p.Key == key {
+			f.Parameters[i].Value = value
+			return
+		}
+	}
+
+	f.Parameters = append(f.Parameters, FormatParameter{Key: key, Value: value})
+}
+
+// ParseSessionDescription parses the given SDP text into a [SessionDescription].
+func ParseSessionDescription(text string) (*SessionDescription, error) {
+	msg, err := ParseSDP(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.SessionDescription(), nil
+}
+
+// SDP returns the SDP text representation of the session description or an error wrapping
+// [ErrInvalidSDP] if the description cannot be converted.
+func (d *SessionDescription) SDP() (string, error) {
+	msg, err := d.SDPMessage()
+	if err != nil {
+		return "", err
+	}
+
+	return msg.AsText(), nil
+}
+
+// MarshalText implements [encoding.TextMarshaler] with the SDP text representation.
+func (d *SessionDescription) MarshalText() ([]byte, error) {
+	text, err := d.SDP()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(text), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] by parsing the SDP text.
+func (d *SessionDescription) UnmarshalText(text []byte) error {
+	parsed, err := ParseSessionDescription(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = *parsed
+
+	return nil
+}
+
+// String returns the SDP text representation of the session description for debugging.
+// Use [SessionDescription.SDP] to detect descriptions that cannot be converted.
+func (d *SessionDescription) String() string {
+	text, err := d.SDP()
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+
+	return text
+}
+
+// SessionDescription converts the message into a plain go [SessionDescription].
+func (msg *SDPMessage) SessionDescription() *SessionDescription {
+	d := &SessionDescription{
+		Version:     msg.GetVersion(),
+		Origin:      originFromNative(&msg.native.origin),
+		SessionName: msg.GetSessionName(),
+		Information: msg.GetInformation(),
+		URI:         msg.GetURI(),
+		Connection:  connectionFromNative(&msg.native.connection),
+		Key:         keyFromNative(&msg.native.key),
+	}
+
+	for _, email := range msg.Emails() {
+		d.Emails = append(d.Emails, email)
+	}
+
+	for _, phone := range msg.Phones() {
+		d.Phones = append(d.Phones, phone)
+	}
+
+	for _, bw := range msg.Bandwidths() {
+		d.Bandwidths = append(d.Bandwidths, Bandwidth{Type: bw.GetBwtype(), Bandwidth: bw.GetBandwidth()})
+	}
+
+	for _, t := range msg.Times() {
+		time := Time{Start: t.GetStart(), Stop: t.GetStop()}
+
+		for _, r := range t.Repeats() {
+			time.Repeats = append(time.Repeats, r)
+		}
+
+		d.Times = append(d.Times, time)
+	}
+
+	for _, z := range msg.Zones() {
+		d.Zones = append(d.Zones, Zone{Time: z.GetTime(), TypedTime: z.GetTypedTime()})
+	}
+
+	for _, a := range msg.Attributes() {
+		d.Attributes = append(d.Attributes, Attribute{Key: a.GetKey(), Value: a.GetValue()})
+	}
+
+	for _, m := range msg.Medias() {
+		d.Media = append(d.Media, m.toGo())
+	}
+
+	return d
+}
+
+func (media *SDPMedia) toGo() Media {
+	m := Media{
+		Media:       media.GetMedia(),
+		Port:        media.GetPort(),
+		NumPorts:    media.GetNumPorts(),
+		Proto:       media.GetProto(),
+		Information: media.GetInformation(),
+		Key:         keyFromNative(&media.native.key),
+	}
+
+	formats := make(map[string]int)
+
+	for _, f := range media.Formats() {
+		formats[f] = len(m.Formats)
+		m.Formats = append(m.Formats, Format{Format: f})
+	}
+
+	for _, c := range media.Connections() {
+		// connections without an address are skipped like the session connection
+		if conn := connectionFromNative(c.native); conn != nil {
+			m.Connections = append(m.Connections, *conn)
+		}
+	}
+
+	for _, bw := range media.Bandwidths() {
+		m.Bandwidths = append(m.Bandwidths, Bandwidth{Type: bw.GetBwtype(), Bandwidth: bw.GetBandwidth()})
+	}
+
+	for _, a := range media.Attributes() {
+		key, value := a.GetKey(), a.GetValue()
+
+		switch key {
+		case "rtpmap", "fmtp", "rtcp-fb":
+			format, rest, _ := strings.Cut(value, " ")
+
+			idx, ok := formats[format]
+			if !ok {
+				// attribute of an unknown format or a wildcard, keep it as is
+				break
+			}
+
+			f := &m.Formats[idx]
+
+			switch key {
+			case "rtpmap":
+				rtpmap, err := parseRTPMap(rest)
+				if err != nil {
+					break
+				}
+				f.RTPMap = rtpmap
+				continue
+			case "fmtp":
+				f.Parameters = parseFormatParameters(rest)
+				continue
+			case "rtcp-fb":
+				f.Feedback = append(f.Feedback, rest)
+				continue
+			}
+		}
+
+		m.Attributes = append(m.Attributes, Attribute{Key: key, Value: value})
+	}
+
+	return m
+}
+
+// SDPMessage converts the session description into a new [SDPMessage].
+func (d *SessionDescription) SDPMessage() (*SDPMessage, error) {
+	msg, res := NewSDPMessage()
+	if res != SdpOK {
+		return nil, fmt.Errorf("%w: could not create message: %s", ErrInvalidSDP, res)
+	}
+
+	version := d.Version
+	if version == "" {
+		version = "0"
+	}
+
+	results := []SDPResult{
+		msg.SetVersion(version),
+		msg.SetOrigin(d.Origin.Username, d.Origin.SessionID, d.Origin.SessionVersion, d.Origin.NetType, d.Origin.AddrType, d.Origin.Address),
+		msg.SetSessionName(d.SessionName),
+	}
+
+	if d.Information != "" {
+		results = append(results, msg.SetInformation(d.Information))
+	}
+
+	if d.URI != "" {
+		results = append(results, msg.SetURI(d.URI))
+	}
+
+	for _, email := range d.Emails {
+		results = append(results, msg.AddEmail(email))
+	}
+
+	for _, phone := range d.Phones {
+		results = append(results, msg.AddPhone(phone))
+	}
+
+	if c := d.Connection; c != nil {
+		results = append(results, msg.SetConnection(c.NetType, c.AddrType, c.Address, c.TTL, c.AddrNumber))
+	}
+
+	for _, bw := range d.Bandwidths {
+		results = append(results, msg.AddBandwidth(bw.Type, bw.Bandwidth))
+	}
+
+	for _, t := range d.Times {
+		results = append(results, msg.AddTime(t.Start, t.Stop, t.Repeats))
+	}
+
+	for _, z := range d.Zones {
+		results = append(results, msg.AddZone(z.Time, z.TypedTime))
+	}
+
+	if k := d.Key; k != nil {
+		results = append(results, msg.SetKey(k.Type, k.Data))
+	}
+
+	for _, a := range d.Attributes {
+		results = append(results, msg.AddAttribute(a.Key, a.Value))
+	}
+
+	for _, res := range results {
+		if res != SdpOK {
+			return nil, fmt.Errorf("%w: could not set session fields: %s", ErrInvalidSDP, res)
+		}
+	}
+
+	for i, m := range d.Media {
+		media, err := m.sdpMedia()
+		if err != nil {
+			return nil, fmt.Errorf("media %d: %w", i, err)
+		}
+
+		if res := msg.AddMedia(media); res != SdpOK {
+			return nil, fmt.Errorf("%w: could not add media %d: %s", ErrInvalidSDP, i, res)
+		}
+	}
+
+	return msg, nil
+}
+
+func (m *Media) sdpMedia() (*SDPMedia, error) {
+	media, res := NewSDPMedia()
+	if res != SdpOK {
+		return nil, fmt.Errorf("%w: could not create media: %s", ErrInvalidSDP, res)
+	}
+
+	results := []SDPResult{
+		media.SetMedia(m.Media),
+		media.SetPortInfo(m.Port, m.NumPorts),
+		media.SetProto(m.Proto),
+	}
+
+	for _, f := range m.Formats {
+		results = append(results, media.AddFormat(f.Format))
+	}
+
+	if m.Information != "" {
+		results = append(results, media.SetInformation(m.Information))
+	}
+
+	for _, c := range m.Connections {
+		results = append(results, media.AddConnection(c.NetType, c.AddrType, c.Address, c.TTL, c.AddrNumber))
+	}
+
+	for _, bw := range m.Bandwidths {
+		results = append(results, media.AddBandwidth(bw.Type, bw.Bandwidth))
+	}
+
+	if k := m.Key; k != nil {
+		results = append(results, media.SetKey(k.Type, k.Data))
+	}
+
+	for _, a := range m.Attributes {
+		results = append(results, media.AddAttribute(a.Key, a.Value))
+	}
+
+	for _, f := range m.Formats {
+		if f.RTPMap != nil {
+			results = append(results, media.AddAttribute("rtpmap", f.Format+" "+f.RTPMap.String()))
+		}
+
+		if len(f.Parameters) > 0 {
+			results = append(results, media.AddAttribute("fmtp", f.Format+" "+formatParametersString(f.Parameters)))
+		}
+
+		for _, fb := range f.Feedback {
+			results = append(results, media.AddAttribute("rtcp-fb", f.Format+" "+fb))
+		}
+	}
+
+	for _, res := range results {
+		if res != SdpOK {
+			return nil, fmt.Errorf("%w: could not set media fields: %s", ErrInvalidSDP, res)
+		}
+	}
+
+	return media, nil
+}
+
+// parseRTPMap parses the value of an rtpmap attribute without the payload type, e.g. "opus/48000/2"
+func parseRTPMap(value string) (*RTPMap, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("%w: invalid rtpmap %q", ErrInvalidSDP, value)
+	}
+
+	clockRate, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid rtpmap clock rate %q", ErrInvalidSDP, value)
+	}
+
+	rtpmap := &RTPMap{
+		EncodingName: parts[0],
+		ClockRate:    uint(clockRate),
+	}
+
+	if len(parts) == 3 {
+		rtpmap.EncodingParams = parts[2]
+	}
+
+	return rtpmap, nil
+}
+
+// parseFormatParameters parses the value of an fmtp attribute without the payload type, e.g. "minptime=10;useinbandfec=1"
+func parseFormatParameters(value string) []FormatParameter {
+	var params []FormatParameter
+
+	for p := range strings.SplitSeq(value, ";") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		key, val, _ := strings.Cut(p, "=")
+
+		params = append(params, FormatParameter{Key: strings.TrimSpace(key), Value: strings.TrimSpace(val)})
+	}
+
+	return params
+}
+
+func formatParametersString(params []FormatParameter) string {
+	parts := make([]string, 0, len(params))
+
+	for _, p := range params {
+		if p.Value == "" {
+			parts = append(parts, p.Key)
+			continue
+		}
+
+		parts = append(parts, p.Key+"="+p.Value)
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func originFromNative(o *C.GstSDPOrigin) Origin {
+	origin := UnsafeSDPOriginFromGlibBorrow(unsafe.Pointer(o))
+
+	return Origin{
+		Username:       origin.GetUsername(),
+		SessionID:      origin.GetSessID(),
+		SessionVersion: origin.GetSessVersion(),
+		NetType:        origin.GetNettype(),
+		AddrType:       origin.GetAddrtype(),
+		Address:        origin.GetAddr(),
+	}
+}
+
+// connectionFromNative returns nil if the connection has no address
+func connectionFromNative(c *C.GstSDPConnection) *Connection {
+	if c.address == nil {
+		return nil
+	}
+
+	conn := UnsafeSDPConnectionFromGlibBorrow(unsafe.Pointer(c))
+
+	return &Connection{
+		NetType:    conn.GetNettype(),
+		AddrType:   conn.GetAddrtype(),
+		Address:    conn.GetAddress(),
+		TTL:        conn.GetTTL(),
+		AddrNumber: conn.GetAddrNumber(),
+	}
+}
+
+// keyFromNative returns nil if the key has no type
+func keyFromNative(k *C.GstSDPKey) *Key {
+	if k._type == nil {
+		return nil
+	}
+
+	key := UnsafeSDPKeyFromGlibBorrow(unsafe.Pointer(k))
+
+	return &Key{
+		Type: key.GetType(),
+		Data: key.GetData(),
+	}
+}
@@ -0,0 +1,114 @@
+package gstsdp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gstsdp"
+)
+
+const testSDP = "v=0\r\n" +
+	"o=- 4611731400430051336 2 IN IP4 127.0.0.1\r\n" +
+	"s=-\r\n" +
+	"t=0 0\r\n" +
+	"a=group:BUNDLE 0\r\n" +
+	"m=audio 9 UDP/TLS/RTP/SAVPF 111 0\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"b=AS:64\r\n" +
+	"a=mid:0\r\n" +
+	"a=rtpmap:111 opus/48000/2\r\n" +
+	"a=fmtp:111 minptime=10;useinbandfec=1\r\n" +
+	"a=rtcp-fb:111 transport-cc\r\n" +
+	"a=rtpmap:0 PCMU/8000\r\n"
+
+func TestSessionDescriptionRoundtrip(t *testing.T) {
+	desc, err := gstsdp.ParseSessionDescription(testSDP)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if desc.Origin.SessionID != "4611731400430051336" {
+		t.Errorf("unexpected session id %q", desc.Origin.SessionID)
+	}
+
+	if len(desc.Media) != 1 {
+		t.Fatalf("expected 1 media, got %d", len(desc.Media))
+	}
+
+	audio := desc.MediaOfType("audio")
+
+	if len(audio.Attributes) != 1 || audio.Attributes[0].Key != "mid" {
+		t.Errorf("unexpected attributes %+v", audio.Attributes)
+	}
+
+	opus := audio.Format("111")
+	if opus == nil || opus.RTPMap == nil {
+		t.Fatal("opus format not decoded")
+	}
+
+	if opus.RTPMap.EncodingName != "opus" || opus.RTPMap.ClockRate != 48000 || opus.RTPMap.EncodingParams != "2" {
+		t.Errorf("unexpected rtpmap %+v", opus.RTPMap)
+	}
+
+	if v, ok := opus.Parameter("useinbandfec"); !ok || v != "1" {
+		t.Errorf("unexpected fmtp %+v", opus.Parameters)
+	}
+
+	if len(opus.Feedback) != 1 || opus.Feedback[0] != "transport-cc" {
+		t.Errorf("unexpected feedback %+v", opus.Feedback)
+	}
+
+	// munge: drop PCMU and cap the bitrate
+	audio.FilterFormats(func(f *gstsdp.Format) bool {
+		return f.RTPMap != nil && f.RTPMap.EncodingName == "opus"
+	})
+	opus = audio.Format("111")
+	opus.SetParameter("maxaveragebitrate", "32000")
+	audio.Bandwidths = []gstsdp.Bandwidth{{Type: "AS", Bandwidth: 32}}
+
+	text, err := desc.SDP()
+	if err != nil {
+		t.Fatalf("conversion error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n",
+		"b=AS:32\r\n",
+		"a=fmtp:111 minptime=10;useinbandfec=1;maxaveragebitrate=32000\r\n",
+		"a=rtcp-fb:111 transport-cc\r\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in:\n%s", expected, text)
+		}
+	}
+
+	if strings.Contains(text, "PCMU") {
+		t.Errorf("PCMU was not filtered:\n%s", text)
+	}
+
+	reparsed, err := gstsdp.ParseSessionDescription(text)
+	if err != nil {
+		t.Fatalf("reparse error: %v", err)
+	}
+
+	if reparsed.String() != text {
+		t.Errorf("roundtrip mismatch:\n%s\n%s", reparsed.String(), text)
+	}
+}
+
+func TestSessionDescriptionText(t *testing.T) {
+	var desc gstsdp.SessionDescription
+
+	if err := desc.UnmarshalText([]byte(testSDP)); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	text, err := desc.MarshalText()
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	if !strings.Contains(string(text), "a=rtpmap:111 opus/48000/2\r\n") {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+}
@@ -920,74 +920,6 @@ func TagListFromId3v2Tag(buffer *gst.Buffer) *gst.TagList {
 	return goret
 }
 
-// TagListFromVorbiscomment wraps gst_tag_list_from_vorbiscomment
-// 
-// see also https://gstreamer.freedesktop.org/documentation/tag
-func TagListFromVorbiscomment(data []uint8, idData []uint8) (string, *gst.TagList) {
-	var carg1 *C.guint8     // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg2)
-	var carg2 C.gsize       // implicit
-	var carg3 *C.guint8     // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg4)
-	var carg4 C.guint       // implicit
-	var carg5 *C.gchar      // out, full, string
-	var cret  *C.GstTagList // return, full, converted, nullable
-
-	_ = data
-	_ = carg1
-	_ = carg2
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-	_ = idData
-	_ = carg3
-	_ = carg4
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-
-	cret = C.gst_tag_list_from_vorbiscomment(carg1, carg2, carg3, carg4, &carg5)
-	runtime.KeepAlive(data)
-	runtime.KeepAlive(idData)
-
-	var vendorString string
-	var goret        *gst.TagList
-
-	vendorString = C.GoString((*C.char)(unsafe.Pointer(carg5)))
-	defer C.g_free(C.gpointer(carg5))
-	if cret != nil {
-		goret = gst.UnsafeTagListFromGlibFull(unsafe.Pointer(cret))
-	}
-
-	return vendorString, goret
-}
-
-// TagListFromVorbiscommentBuffer wraps gst_tag_list_from_vorbiscomment_buffer
-// 
-// see also https://gstreamer.freedesktop.org/documentation/tag
-func TagListFromVorbiscommentBuffer(buffer *gst.Buffer, idData []uint8) (string, *gst.TagList) {
-	var carg1 *C.GstBuffer  // in, none, converted
-	var carg2 *C.guint8     // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg3)
-	var carg3 C.guint       // implicit
-	var carg4 *C.gchar      // out, full, string
-	var cret  *C.GstTagList // return, full, converted, nullable
-
-	carg1 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer))
-	_ = idData
-	_ = carg2
-	_ = carg3
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-
-	cret = C.gst_tag_list_from_vorbiscomment_buffer(carg1, carg2, carg3, &carg4)
-	runtime.KeepAlive(buffer)
-	runtime.KeepAlive(idData)
-
-	var vendorString string
-	var goret        *gst.TagList
-
-	vendorString = C.GoString((*C.char)(unsafe.Pointer(carg4)))
-	defer C.g_free(C.gpointer(carg4))
-	if cret != nil {
-		goret = gst.UnsafeTagListFromGlibFull(unsafe.Pointer(cret))
-	}
-
-	return vendorString, goret
-}
-
 // TagListFromXmpBuffer wraps gst_tag_list_from_xmp_buffer
 // 
 // see also https://gstreamer.freedesktop.org/documentation/tag
@@ -1080,69 +1012,6 @@ func TagListToExifBufferWithTiffHeader(taglist *gst.TagList) *gst.Buffer {
 	return goret
 }
 
-// TagListToVorbiscommentBuffer wraps gst_tag_list_to_vorbiscomment_buffer
-// 
-// see also https://gstreamer.freedesktop.org/documentation/tag
-func TagListToVorbiscommentBuffer(list *gst.TagList, idData []uint8, vendorString string) *gst.Buffer {
-	var carg1 *C.GstTagList // in, none, converted
-	var carg2 *C.guint8     // in, transfer: none, C Pointers: 1, Name: array[guint8], array (inner guint8 (*typesystem.CastablePrimitive), length-by: carg3)
-	var carg3 C.guint       // implicit
-	var carg4 *C.gchar      // in, none, string, nullable-string
-	var cret  *C.GstBuffer  // return, full, converted
-
-	carg1 = (*C.GstTagList)(gst.UnsafeTagListToGlibNone(list))
-	_ = idData
-	_ = carg2
-	_ = carg3
-	panic("unimplemented conversion of []uint8 (const guint8*) because of unimplemented: non-fixed size array")
-	if vendorString != "" {
-		carg4 = (*C.gchar)(transfer.GLibString(vendorString))
-		defer C.g_free(C.gpointer(carg4))
-	}
-
-	cret = C.gst_tag_list_to_vorbiscomment_buffer(carg1, carg2, carg3, carg4)
-	runtime.KeepAlive(list)
-	runtime.KeepAlive(idData)
-	runtime.KeepAlive(vendorString)
-
-	var goret *gst.Buffer
-
-	goret = gst.UnsafeBufferFromGlibFull(unsafe.Pointer(cret))
-
-	return goret
-}
-
-// TagListToXmpBuffer wraps gst_tag_list_to_xmp_buffer
-// 
-// see also https://gstreamer.freedesktop.org/documentation/tag
-func TagListToXmpBuffer(list *gst.TagList, readOnly bool, schemas []string) *gst.Buffer {
-	var carg1 *C.GstTagList // in, none, converted
-	var carg2 C.gboolean    // in
-	var carg3 **C.gchar     // in, transfer: none, C Pointers: 2, Name: array[utf8], array (inner gchar* (*typesystem.StringPrimitive), zero-terminated)
-	var cret  *C.GstBuffer  // return, full, converted, nullable
-
-	carg1 = (*C.GstTagList)(gst.UnsafeTagListToGlibNone(list))
-	if readOnly {
-		carg2 = C.TRUE
-	}
-	_ = schemas
-	_ = carg3
-	panic("unimplemented conversion of []string (const gchar**) because of unimplemented: inner pointers in array")
-
-	cret = C.gst_tag_list_to_xmp_buffer(carg1, carg2, carg3)
-	runtime.KeepAlive(list)
-	runtime.KeepAlive(readOnly)
-	runtime.KeepAlive(schemas)
-
-	var goret *gst.Buffer
-
-	if cret != nil {
-		goret = gst.UnsafeBufferFromGlibFull(unsafe.Pointer(cret))
-	}
-
-	return goret
-}
-
 // TagParseExtendedComment wraps gst_tag_parse_extended_comment
 // 
 // see also https://gstreamer.freedesktop.org/documentation/tag
@@ -0,0 +1,782 @@
+package gsttag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-tag-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/tag/tag.h>
+import "C"
+
+var (
+	// ErrUnknownMetadataFormat is returned if the format of the metadata could not be detected.
+	ErrUnknownMetadataFormat = errors.New("unknown metadata format")
+	// ErrInvalidMetadata is returned if the metadata could not be parsed or written.
+	ErrInvalidMetadata = errors.New("invalid metadata")
+)
+
+// MetadataFormat is the format of the metadata of a file, see [DetectMetadataFormat].
+type MetadataFormat int
+
+const (
+	MetadataFormatUnknown MetadataFormat = iota
+	// MetadataFormatID3v2 is an ID3v2 tag at the start of the file, e.g. of MP3 files.
+	MetadataFormatID3v2
+	// MetadataFormatFLAC is the vorbis comment block of a FLAC file.
+	MetadataFormatFLAC
+	// MetadataFormatXMP is an XMP packet, e.g. of a .xmp sidecar file.
+	MetadataFormatXMP
+	// MetadataFormatEXIF is an EXIF block that starts with the "Exif\0\0" identifier.
+	MetadataFormatEXIF
+	// MetadataFormatJPEG are the EXIF and XMP segments of a JPEG file.
+	MetadataFormatJPEG
+	// MetadataFormatOgg is the vorbis comment header of an Ogg Vorbis or Opus file.
+	MetadataFormatOgg
+)
+
+func (f MetadataFormat) String() string {
+	switch f {
+	case MetadataFormatID3v2:
+		return "id3v2"
+	case MetadataFormatFLAC:
+		return "flac"
+	case MetadataFormatXMP:
+		return "xmp"
+	case MetadataFormatEXIF:
+		return "exif"
+	case MetadataFormatJPEG:
+		return "jpeg"
+	case MetadataFormatOgg:
+		return "ogg"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	exifIdentifier = "Exif\x00\x00"
+	xmpIdentifier  = "http://ns.adobe.com/xap/1.0/\x00"
+
+	flacVorbisCommentBlock = 4
+	jpegAPP0               = 0xe0
+	jpegAPP1               = 0xe1
+	jpegSOS                = 0xda
+)
+
+// DetectMetadataFormat detects the format from the first bytes of a file.
+func DetectMetadataFormat(header []byte) MetadataFormat {
+	switch {
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return MetadataFormatID3v2
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return MetadataFormatFLAC
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return MetadataFormatOgg
+	case bytes.HasPrefix(header, []byte{0xff, 0xd8, 0xff}):
+		return MetadataFormatJPEG
+	case bytes.HasPrefix(header, []byte(exifIdentifier)):
+		return MetadataFormatEXIF
+	}
+
+	text := bytes.TrimLeft(bytes.TrimPrefix(header, []byte("\xef\xbb\xbf")), " \t\r\n")
+
+	if bytes.HasPrefix(text, []byte("<?xpacket")) || bytes.HasPrefix(text, []byte("<x:xmpmeta")) || bytes.HasPrefix(text, []byte("<x:xapmeta")) {
+		return MetadataFormatXMP
+	}
+
+	return MetadataFormatUnknown
+}
+
+// ReadMetadata reads the tags of the file contents in data. An empty list is returned if the
+// file contains no metadata.
+func ReadMetadata(data []byte) (*gst.TagList, MetadataFormat, error) {
+	format := DetectMetadataFormat(data)
+
+	var tags *gst.TagList
+	var err error
+
+	switch format {
+	case MetadataFormatID3v2:
+		tags, err = readID3v2(data)
+	case MetadataFormatFLAC:
+		tags, err = readFLAC(data)
+	case MetadataFormatOgg:
+		tags, err = readOgg(data)
+	case MetadataFormatXMP:
+		tags = TagListFromXmpBuffer(bufferFromBytes(data))
+	case MetadataFormatEXIF:
+		tags = TagListFromExifBufferWithTiffHeader(bufferFromBytes(data[len(exifIdentifier):]))
+	case MetadataFormatJPEG:
+		tags, err = readJPEG(data)
+	default:
+		return nil, format, ErrUnknownMetadataFormat
+	}
+
+	if err != nil {
+		return nil, format, err
+	}
+
+	if tags == nil {
+		return nil, format, fmt.Errorf("%w: could not parse %s", ErrInvalidMetadata, format)
+	}
+
+	return tags, format, nil
+}
+
+// WriteMetadata replaces the metadata of the file contents in data with tags and returns the
+// new contents. ID3v2 tags are rewritten as ID3v2.4 tags that contain the text frames, comments
+// and images of tags. The other frames of the old tag, e.g. PRIV, UFID, USLT and TXXX frames,
+// are kept unchanged, an error is returned if they cannot be kept. The comment header of Ogg
+// Vorbis and Opus files is replaced. JPEG files get an EXIF and an XMP segment.
+func WriteMetadata(data []byte, tags *gst.TagList) ([]byte, error) {
+	switch format := DetectMetadataFormat(data); format {
+	case MetadataFormatID3v2:
+		return writeID3v2(data, tags)
+	case MetadataFormatFLAC:
+		return writeFLAC(data, tags)
+	case MetadataFormatOgg:
+		return writeOgg(data, tags)
+	case MetadataFormatXMP:
+		return xmpPacket(tags)
+	case MetadataFormatEXIF:
+		return append([]byte(exifIdentifier), bufferBytes(TagListToExifBufferWithTiffHeader(tags))...), nil
+	case MetadataFormatJPEG:
+		return writeJPEG(data, tags)
+	default:
+		return nil, ErrUnknownMetadataFormat
+	}
+}
+
+// ReadMetadataFile reads the tags of the file at path, see [ReadMetadata].
+func ReadMetadataFile(path string) (*gst.TagList, MetadataFormat, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, MetadataFormatUnknown, err
+	}
+
+	tags, format, err := ReadMetadata(data)
+	if err != nil {
+		return nil, format, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return tags, format, nil
+}
+
+// WriteMetadataFile replaces the metadata of the file at path with tags, see [WriteMetadata].
+// The file is replaced atomically by renaming a temporary file.
+func WriteMetadataFile(path string, tags *gst.TagList) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	data, err = WriteMetadata(data, tags)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func readID3v2(data []byte) (*gst.TagList, error) {
+	buffer := bufferFromBytes(data)
+
+	if size := TagGetId3v2TagSize(buffer); size == 0 || size > uint(len(data)) {
+		return nil, fmt.Errorf("%w: invalid id3v2 tag size", ErrInvalidMetadata)
+	}
+
+	return TagListFromId3v2Tag(buffer), nil
+}
+
+func writeID3v2(data []byte, tags *gst.TagList) ([]byte, error) {
+	size := TagGetId3v2TagSize(bufferFromBytes(data))
+	if size == 0 || size > uint(len(data)) {
+		return nil, fmt.Errorf("%w: invalid id3v2 tag size", ErrInvalidMetadata)
+	}
+
+	kept, err := id3v2KeptFrames(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := id3v2Tag(tags, kept)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tag, data[size:]...), nil
+}
+
+// id3v2Replaced are the ID3v2.3 frames that are replaced by TDRC and TDOR in ID3v2.4
+var id3v2Replaced = map[string]bool{"TYER": true, "TDAT": true, "TIME": true, "TRDA": true, "TORY": true, "TSIZ": true}
+
+// id3v2KeptFrames returns the frames of the tag at the start of data that are not created from
+// the tag list, converted to ID3v2.4 frames
+func id3v2KeptFrames(data []byte) ([]byte, error) {
+	version, flags := data[3], data[5]
+	body := data[10:min(10+int(id3v2Synchsafe(data[6:10])), len(data))]
+
+	if version < 4 && flags&0x80 != 0 {
+		// ID3v2.3 and older unsynchronise the whole tag
+		body = bytes.ReplaceAll(body, []byte{0xff, 0x00}, []byte{0xff})
+	}
+
+	if version == 2 {
+		return nil, id3v22Check(body, flags)
+	}
+
+	if flags&0x40 != 0 {
+		if len(body) < 4 {
+			return nil, fmt.Errorf("%w: truncated id3v2 extended header", ErrInvalidMetadata)
+		}
+
+		// the ID3v2.4 size includes the size field itself
+		size := int(id3v2Synchsafe(body))
+		if version == 3 {
+			size = 4 + int(binary.BigEndian.Uint32(body))
+		}
+
+		body = body[min(size, len(body)):]
+	}
+
+	var kept []byte
+	var err error
+
+	for len(body) >= 10 && body[0] != 0 {
+		id := string(body[:4])
+
+		size := int(binary.BigEndian.Uint32(body[4:]))
+		if version == 4 {
+			size = int(id3v2Synchsafe(body[4:]))
+		}
+
+		if 10+size > len(body) {
+			return nil, fmt.Errorf("%w: truncated id3v2 frame %s", ErrInvalidMetadata, id)
+		}
+
+		frame := body[:10+size]
+		body = body[10+size:]
+
+		status, format := frame[8], frame[9]
+
+		switch {
+		case id == "COMM" || id == "APIC":
+			continue
+		case id[0] == 'T' && id != "TXXX" && id != "TLEN" && (TagFromId3Tag(id) != "" || id3v2Replaced[id] && version == 3):
+			continue
+		}
+
+		if version == 4 {
+			// frames that must be discarded when the tag is altered
+			if status&0x40 == 0 {
+				kept = append(kept, frame...)
+			}
+
+			continue
+		}
+
+		if status&0x80 != 0 {
+			continue
+		}
+
+		if format != 0 {
+			return nil, fmt.Errorf("%w: cannot keep compressed, encrypted or grouped id3v2.3 frame %s", ErrInvalidMetadata, id)
+		}
+
+		// the ID3v2.3 status flags are shifted by one in ID3v2.4
+		if kept, err = appendID3v2FrameFlags(kept, id, status>>1, 0, frame[10:]); err != nil {
+			return nil, err
+		}
+	}
+
+	return kept, nil
+}
+
+// id3v22Check returns an error if an ID3v2.2 tag contains frames that would be dropped, because
+// they cannot be converted to ID3v2.4 frames
+func id3v22Check(body []byte, flags byte) error {
+	if flags&0x40 != 0 {
+		return fmt.Errorf("%w: cannot rewrite compressed id3v2.2 tag", ErrInvalidMetadata)
+	}
+
+	for len(body) >= 6 && body[0] != 0 {
+		id := string(body[:3])
+		size := int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+
+		if (id[0] != 'T' || id == "TXX") && id != "COM" && id != "PIC" {
+			return fmt.Errorf("%w: cannot keep id3v2.2 frame %s", ErrInvalidMetadata, id)
+		}
+
+		body = body[min(6+size, len(body)):]
+	}
+
+	return nil
+}
+
+func id3v2Synchsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
+
+// id3v2Tag creates an ID3v2.4 tag with UTF-8 text frames, followed by the kept frames
+func id3v2Tag(tags *gst.TagList, kept []byte) ([]byte, error) {
+	var ids []string
+	values := make(map[string][]string)
+
+	for i := range uint(tags.NTags()) {
+		tag := tags.NthTagName(i)
+
+		id := TagToId3Tag(tag)
+		if len(id) != 4 || id[0] != 'T' || id == "TXXX" || id == "TLEN" {
+			continue
+		}
+
+		for j := range tags.GetTagSize(tag) {
+			text, ok := id3v2Text(tags.GetValueIndex(tag, j))
+			if !ok {
+				continue
+			}
+
+			if _, ok := values[id]; !ok {
+				ids = append(ids, id)
+			}
+
+			values[id] = append(values[id], text)
+		}
+	}
+
+	// the counts are stored in the same frame as the numbers
+	for id, count := range map[string]string{"TRCK": gst.TAG_TRACK_COUNT, "TPOS": gst.TAG_ALBUM_VOLUME_COUNT} {
+		if n, ok := tags.GetUint(count); ok && len(values[id]) > 0 {
+			values[id][0] += "/" + strconv.FormatUint(uint64(n), 10)
+		}
+	}
+
+	var frames []byte
+	var err error
+
+	for _, id := range ids {
+		text := []byte{0x03} // UTF-8
+
+		for i, v := range values[id] {
+			if i > 0 {
+				text = append(text, 0)
+			}
+
+			text = append(text, v...)
+		}
+
+		if frames, err = appendID3v2Frame(frames, id, text); err != nil {
+			return nil, err
+		}
+	}
+
+	for j := range tags.GetTagSize(gst.TAG_COMMENT) {
+		comment, ok := tags.GetStringIndex(gst.TAG_COMMENT, j)
+		if !ok {
+			continue
+		}
+
+		// encoding, unknown language and an empty description
+		frame := append([]byte{0x03, 'X', 'X', 'X', 0}, comment...)
+
+		if frames, err = appendID3v2Frame(frames, "COMM", frame); err != nil {
+			return nil, err
+		}
+	}
+
+	var images struct {
+		Images []gst.TagImage `gsttag:"image"`
+	}
+
+	if err := tags.UnmarshalTags(&images); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+
+	for _, img := range images.Images {
+		// the ID3 picture types are the GstTagImageType values shifted by two icon types, e.g. 3
+		// for the front cover
+		pictureType := byte(0)
+		if img.Type > 0 {
+			pictureType = byte(img.Type + 2)
+		}
+
+		frame := append([]byte{0x03}, img.MIME...)
+		frame = append(frame, 0, pictureType, 0)
+		frame = append(frame, img.Data...)
+
+		if frames, err = appendID3v2Frame(frames, "APIC", frame); err != nil {
+			return nil, err
+		}
+	}
+
+	frames = append(frames, kept...)
+
+	if len(frames) >= 1<<28 {
+		return nil, fmt.Errorf("%w: id3v2 tag too large", ErrInvalidMetadata)
+	}
+
+	tag := []byte{'I', 'D', '3', 4, 0, 0}
+	tag = appendSynchsafe(tag, uint32(len(frames)))
+
+	return append(tag, frames...), nil
+}
+
+func id3v2Text(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case uint32, uint64, int32, int64:
+		return fmt.Sprint(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case *gst.DateTime:
+		return v.ToIso8601String(), true
+	default:
+		return "", false
+	}
+}
+
+func appendID3v2Frame(frames []byte, id string, data []byte) ([]byte, error) {
+	return appendID3v2FrameFlags(frames, id, 0, 0, data)
+}
+
+func appendID3v2FrameFlags(frames []byte, id string, status, format byte, data []byte) ([]byte, error) {
+	if len(data) >= 1<<28 {
+		return nil, fmt.Errorf("%w: id3v2 frame %s too large", ErrInvalidMetadata, id)
+	}
+
+	frames = append(frames, id...)
+	frames = appendSynchsafe(frames, uint32(len(data)))
+	frames = append(frames, status, format)
+
+	return append(frames, data...), nil
+}
+
+func appendSynchsafe(b []byte, n uint32) []byte {
+	return append(b, byte(n>>21)&0x7f, byte(n>>14)&0x7f, byte(n>>7)&0x7f, byte(n)&0x7f)
+}
+
+type flacBlock struct {
+	typ  byte
+	data []byte
+}
+
+// parseFLAC splits a FLAC file into its metadata blocks and the audio frames
+func parseFLAC(data []byte) ([]flacBlock, []byte, error) {
+	var blocks []flacBlock
+
+	pos := 4
+
+	for {
+		if pos+4 > len(data) {
+			return nil, nil, fmt.Errorf("%w: truncated flac metadata block", ErrInvalidMetadata)
+		}
+
+		header := data[pos]
+		length := int(data[pos+1])<<16 | int(data[pos+2])<<8 | int(data[pos+3])
+		pos += 4
+
+		if pos+length > len(data) {
+			return nil, nil, fmt.Errorf("%w: truncated flac metadata block", ErrInvalidMetadata)
+		}
+
+		blocks = append(blocks, flacBlock{typ: header & 0x7f, data: data[pos : pos+length]})
+		pos += length
+
+		if header&0x80 != 0 {
+			return blocks, data[pos:], nil
+		}
+	}
+}
+
+func readFLAC(data []byte) (*gst.TagList, error) {
+	blocks, _, err := parseFLAC(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range blocks {
+		if b.typ == flacVorbisCommentBlock {
+			_, tags := TagListFromVorbiscomment(b.data, nil)
+
+			return tags, nil
+		}
+	}
+
+	return gst.NewTagListEmpty(), nil
+}
+
+func writeFLAC(data []byte, tags *gst.TagList) ([]byte, error) {
+	blocks, audio, err := parseFLAC(data)
+	if err != nil {
+		return nil, err
+	}
+
+	vendor := ""
+
+	for _, b := range blocks {
+		if b.typ == flacVorbisCommentBlock {
+			vendor, _ = TagListFromVorbiscomment(b.data, nil)
+
+			break
+		}
+	}
+
+	comment := bufferBytes(TagListToVorbiscommentBuffer(tags, nil, vendor))
+
+	// FLAC stores the comment without the framing bit of vorbis
+	if len(comment) > 0 {
+		comment = comment[:len(comment)-1]
+	}
+
+	if len(comment) > 0xffffff {
+		return nil, fmt.Errorf("%w: vorbis comment too large", ErrInvalidMetadata)
+	}
+
+	out := []byte("fLaC")
+	last := 0
+	written := false
+
+	appendBlock := func(b flacBlock) {
+		last = len(out)
+		out = append(out, b.typ, byte(len(b.data)>>16), byte(len(b.data)>>8), byte(len(b.data)))
+		out = append(out, b.data...)
+	}
+
+	for i, b := range blocks {
+		if b.typ == flacVorbisCommentBlock {
+			if written {
+				continue
+			}
+
+			b.data = comment
+			written = true
+		}
+
+		appendBlock(b)
+
+		// the comment is added after the stream info, which is always the first block
+		if i == 0 && !written {
+			appendBlock(flacBlock{typ: flacVorbisCommentBlock, data: comment})
+			written = true
+		}
+	}
+
+	// mark the last metadata block
+	out[last] |= 0x80
+
+	return append(out, audio...), nil
+}
+
+type jpegSegment struct {
+	marker byte
+	data   []byte
+}
+
+// parseJPEG splits a JPEG file into the segments before the image data and the rest of the
+// file, starting at the start of scan marker
+func parseJPEG(data []byte) ([]jpegSegment, []byte, error) {
+	var segments []jpegSegment
+
+	pos := 2
+
+	for {
+		if pos+2 > len(data) || data[pos] != 0xff {
+			return nil, nil, fmt.Errorf("%w: invalid jpeg segment", ErrInvalidMetadata)
+		}
+
+		marker := data[pos+1]
+
+		if marker == 0xff {
+			// fill byte
+			pos++
+
+			continue
+		}
+
+		if marker == jpegSOS {
+			return segments, data[pos:], nil
+		}
+
+		if pos+4 > len(data) {
+			return nil, nil, fmt.Errorf("%w: truncated jpeg segment", ErrInvalidMetadata)
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil, nil, fmt.Errorf("%w: truncated jpeg segment", ErrInvalidMetadata)
+		}
+
+		segments = append(segments, jpegSegment{marker: marker, data: data[pos+4 : pos+2+length]})
+		pos += 2 + length
+	}
+}
+
+func readJPEG(data []byte) (*gst.TagList, error) {
+	segments, _, err := parseJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var exif, xmp *gst.TagList
+
+	for _, s := range segments {
+		if s.marker != jpegAPP1 {
+			continue
+		}
+
+		switch {
+		case exif == nil && bytes.HasPrefix(s.data, []byte(exifIdentifier)):
+			exif = TagListFromExifBufferWithTiffHeader(bufferFromBytes(s.data[len(exifIdentifier):]))
+		case xmp == nil && bytes.HasPrefix(s.data, []byte(xmpIdentifier)):
+			xmp = TagListFromXmpBuffer(bufferFromBytes(s.data[len(xmpIdentifier):]))
+		}
+	}
+
+	switch {
+	case exif == nil && xmp == nil:
+		return gst.NewTagListEmpty(), nil
+	case xmp == nil:
+		return exif, nil
+	case exif != nil:
+		// xmp supports multiple values per tag, so its values take precedence
+		xmp.Insert(exif, gst.TagMergeKeep)
+	}
+
+	return xmp, nil
+}
+
+func writeJPEG(data []byte, tags *gst.TagList) ([]byte, error) {
+	segments, rest, err := parseJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	exif := append([]byte(exifIdentifier), bufferBytes(TagListToExifBufferWithTiffHeader(tags))...)
+
+	packet, err := xmpPacket(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	xmp := append([]byte(xmpIdentifier), packet...)
+
+	if len(exif) > 0xffff-2 || len(xmp) > 0xffff-2 {
+		return nil, fmt.Errorf("%w: metadata too large for a jpeg segment", ErrInvalidMetadata)
+	}
+
+	// the new segments replace the old ones, or are added after the JFIF segment
+	var kept []jpegSegment
+
+	insert := 0
+
+	for _, s := range segments {
+		if s.marker == jpegAPP1 && (bytes.HasPrefix(s.data, []byte(exifIdentifier)) || bytes.HasPrefix(s.data, []byte(xmpIdentifier))) {
+			continue
+		}
+
+		if s.marker == jpegAPP0 && insert == len(kept) {
+			insert++
+		}
+
+		kept = append(kept, s)
+	}
+
+	out := []byte{0xff, 0xd8}
+
+	for i, s := range kept {
+		if i == insert {
+			out = appendJPEGSegment(out, jpegSegment{marker: jpegAPP1, data: exif})
+			out = appendJPEGSegment(out, jpegSegment{marker: jpegAPP1, data: xmp})
+		}
+
+		out = appendJPEGSegment(out, s)
+	}
+
+	if insert == len(kept) {
+		out = appendJPEGSegment(out, jpegSegment{marker: jpegAPP1, data: exif})
+		out = appendJPEGSegment(out, jpegSegment{marker: jpegAPP1, data: xmp})
+	}
+
+	return append(out, rest...), nil
+}
+
+func appendJPEGSegment(out []byte, s jpegSegment) []byte {
+	out = append(out, 0xff, s.marker)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(s.data)+2))
+
+	return append(out, s.data...)
+}
+
+func xmpPacket(tags *gst.TagList) ([]byte, error) {
+	buffer := TagListToXmpBuffer(tags, false, nil)
+	if buffer == nil {
+		return nil, fmt.Errorf("%w: could not create xmp packet", ErrInvalidMetadata)
+	}
+
+	return bufferBytes(buffer), nil
+}
+
+// bufferFromBytes copies data into a new buffer
+func bufferFromBytes(data []byte) *gst.Buffer {
+	var cdata C.gconstpointer
+	if len(data) > 0 {
+		cdata = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+
+	cret := C.gst_buffer_new_memdup(cdata, C.gsize(len(data)))
+	runtime.KeepAlive(data)
+
+	return gst.UnsafeBufferFromGlibFull(unsafe.Pointer(cret))
+}
+
+// bufferBytes copies the contents of the buffer
+func bufferBytes(buffer *gst.Buffer) []byte {
+	info, ok := buffer.Map(gst.MapRead)
+	if !ok {
+		return nil
+	}
+
+	defer info.Unmap()
+
+	return append([]byte(nil), info.Data()...)
+}
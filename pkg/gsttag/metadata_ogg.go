@@ -0,0 +1,296 @@
+package gsttag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+const (
+	oggHeaderSize = 27
+
+	oggContinued = 0x01
+	oggBOS       = 0x02
+
+	// oggNoGranule is the granule position of a page on which no packet ends
+	oggNoGranule = ^uint64(0)
+)
+
+// oggCodec describes the header packets of a codec that stores its tags in a vorbis comment
+type oggCodec struct {
+	ident   string
+	comment string
+	headers int
+	// framing is true if the comment packet ends with the framing bit of vorbis
+	framing bool
+}
+
+var oggCodecs = []oggCodec{
+	{ident: "\x01vorbis", comment: "\x03vorbis", headers: 3, framing: true},
+	{ident: "OpusHead", comment: "OpusTags", headers: 2},
+}
+
+type oggPage struct {
+	flags    byte
+	granule  uint64
+	serial   uint32
+	sequence uint32
+	segments []byte
+	data     []byte
+	raw      []byte
+}
+
+// parseOgg splits an Ogg file into its pages
+func parseOgg(data []byte) ([]oggPage, error) {
+	var pages []oggPage
+
+	for pos := 0; pos < len(data); {
+		if pos+oggHeaderSize > len(data) || !bytes.Equal(data[pos:pos+4], []byte("OggS")) {
+			return nil, fmt.Errorf("%w: invalid ogg page at offset %d", ErrInvalidMetadata, pos)
+		}
+
+		h := data[pos:]
+		n := int(h[26])
+
+		if pos+oggHeaderSize+n > len(data) {
+			return nil, fmt.Errorf("%w: truncated ogg page", ErrInvalidMetadata)
+		}
+
+		segments := h[oggHeaderSize : oggHeaderSize+n]
+
+		size := 0
+		for _, s := range segments {
+			size += int(s)
+		}
+
+		end := pos + oggHeaderSize + n + size
+		if end > len(data) {
+			return nil, fmt.Errorf("%w: truncated ogg page", ErrInvalidMetadata)
+		}
+
+		pages = append(pages, oggPage{
+			flags:    h[5],
+			granule:  binary.LittleEndian.Uint64(h[6:]),
+			serial:   binary.LittleEndian.Uint32(h[14:]),
+			sequence: binary.LittleEndian.Uint32(h[18:]),
+			segments: segments,
+			data:     data[pos+oggHeaderSize+n : end],
+			raw:      data[pos:end],
+		})
+
+		pos = end
+	}
+
+	if len(pages) == 0 || pages[0].flags&oggBOS == 0 {
+		return nil, fmt.Errorf("%w: ogg file does not start with a stream", ErrInvalidMetadata)
+	}
+
+	return pages, nil
+}
+
+// oggHeaders returns the codec and the header packets of the first stream, together with the
+// index of the page after the last header page
+func oggHeaders(pages []oggPage) (oggCodec, [][]byte, int, error) {
+	serial := pages[0].serial
+
+	var packets [][]byte
+	var packet []byte
+	var codec oggCodec
+
+	for i, page := range pages {
+		if page.serial != serial {
+			continue
+		}
+
+		pos := 0
+
+		for j, s := range page.segments {
+			packet = append(packet, page.data[pos:pos+int(s)]...)
+			pos += int(s)
+
+			if s == 255 {
+				continue
+			}
+
+			packets = append(packets, packet)
+			packet = nil
+
+			if len(packets) == 1 {
+				found := false
+
+				for _, c := range oggCodecs {
+					if bytes.HasPrefix(packets[0], []byte(c.ident)) {
+						codec, found = c, true
+					}
+				}
+
+				if !found {
+					return oggCodec{}, nil, 0, fmt.Errorf("%w: unsupported ogg codec", ErrUnknownMetadataFormat)
+				}
+
+				// the identification header is alone on the first page
+				if i != 0 || j != len(page.segments)-1 {
+					return oggCodec{}, nil, 0, fmt.Errorf("%w: ogg identification header does not end the first page", ErrInvalidMetadata)
+				}
+			}
+
+			if len(packets) == codec.headers {
+				// the first audio packet starts on a new page
+				if j != len(page.segments)-1 {
+					return oggCodec{}, nil, 0, fmt.Errorf("%w: ogg headers do not end a page", ErrInvalidMetadata)
+				}
+
+				if !bytes.HasPrefix(packets[1], []byte(codec.comment)) {
+					return oggCodec{}, nil, 0, fmt.Errorf("%w: missing ogg comment header", ErrInvalidMetadata)
+				}
+
+				return codec, packets, i + 1, nil
+			}
+		}
+	}
+
+	return oggCodec{}, nil, 0, fmt.Errorf("%w: truncated ogg headers", ErrInvalidMetadata)
+}
+
+func readOgg(data []byte) (*gst.TagList, error) {
+	pages, err := parseOgg(data)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, headers, _, err := oggHeaders(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	_, tags := TagListFromVorbiscomment(headers[1], []byte(codec.comment))
+
+	return tags, nil
+}
+
+// writeOgg replaces the comment header of the first stream. The header pages of the stream are
+// paginated again, so the sequence numbers of its following pages are adjusted.
+func writeOgg(data []byte, tags *gst.TagList) ([]byte, error) {
+	pages, err := parseOgg(data)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, headers, end, err := oggHeaders(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	vendor, _ := TagListFromVorbiscomment(headers[1], []byte(codec.comment))
+
+	comment := bufferBytes(TagListToVorbiscommentBuffer(tags, []byte(codec.comment), vendor))
+	if !codec.framing && len(comment) > 0 {
+		comment = comment[:len(comment)-1]
+	}
+
+	headers[1] = comment
+
+	serial := pages[0].serial
+
+	out := append([]byte(nil), pages[0].raw...)
+	out, sequence := appendOggPackets(out, serial, pages[0].sequence+1, headers[1:])
+
+	delta := sequence - pages[end-1].sequence - 1
+
+	for i, page := range pages[1:] {
+		switch {
+		case page.serial != serial:
+			out = append(out, page.raw...)
+		case i+1 >= end:
+			page.sequence += delta
+			out = appendOggPage(out, page)
+		}
+	}
+
+	return out, nil
+}
+
+// appendOggPackets paginates the packets and returns the sequence number of the next page
+func appendOggPackets(out []byte, serial, sequence uint32, packets [][]byte) ([]byte, uint32) {
+	page := oggPage{serial: serial, granule: oggNoGranule}
+
+	flush := func(continued bool) {
+		page.sequence = sequence
+		out = appendOggPage(out, page)
+		sequence++
+
+		page = oggPage{serial: serial, granule: oggNoGranule}
+		if continued {
+			page.flags = oggContinued
+		}
+	}
+
+	for _, packet := range packets {
+		for {
+			n := min(len(packet), 255)
+
+			page.segments = append(page.segments, byte(n))
+			page.data = append(page.data, packet[:n]...)
+			packet = packet[n:]
+
+			last := n < 255
+			if last {
+				// header packets have a granule position of 0
+				page.granule = 0
+			}
+
+			if len(page.segments) == 255 {
+				flush(!last)
+			}
+
+			if last {
+				break
+			}
+		}
+	}
+
+	if len(page.segments) > 0 {
+		flush(false)
+	}
+
+	return out, sequence
+}
+
+// appendOggPage serializes the page with a new checksum
+func appendOggPage(out []byte, page oggPage) []byte {
+	start := len(out)
+
+	out = append(out, "OggS"...)
+	out = append(out, 0, page.flags)
+	out = binary.LittleEndian.AppendUint64(out, page.granule)
+	out = binary.LittleEndian.AppendUint32(out, page.serial)
+	out = binary.LittleEndian.AppendUint32(out, page.sequence)
+	out = append(out, 0, 0, 0, 0, byte(len(page.segments)))
+	out = append(out, page.segments...)
+	out = append(out, page.data...)
+
+	binary.LittleEndian.PutUint32(out[start+22:], oggCRC(out[start:]))
+
+	return out
+}
+
+// oggCRC computes the checksum of a page, which uses the CRC-32 polynomial without reflection
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
@@ -0,0 +1,201 @@
+package gsttag_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gsttag"
+)
+
+type testMetadata struct {
+	Title   string   `gsttag:"title"`
+	Artists []string `gsttag:"artist"`
+}
+
+func TestMetadataRoundtrip(t *testing.T) {
+	gst.Init()
+
+	audio := []byte{0xff, 0xf8, 0x01, 0x02, 0x03}
+
+	flac := append([]byte("fLaC"), 0x80, 0x00, 0x00, 0x22) // last block, stream info
+	flac = append(flac, make([]byte, 0x22)...)
+	flac = append(flac, audio...)
+
+	id3 := append([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 0}, audio...)
+
+	vorbis := oggStream(
+		[]byte("\x01vorbis\x00\x00\x00\x00\x02"),
+		append([]byte("\x03vorbis\x04\x00\x00\x00test\x00\x00\x00\x00"), 1),
+		[]byte("\x05vorbis"),
+		audio,
+	)
+
+	opus := oggStream(
+		[]byte("OpusHead\x01\x02"),
+		[]byte("OpusTags\x04\x00\x00\x00test\x00\x00\x00\x00"),
+		audio,
+	)
+
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x04, 'J', 'F', 0xff, 0xda, 0x00, 0x02, 0x01, 0xff, 0xd9}
+
+	want := testMetadata{Title: "Song", Artists: []string{"A", "B"}}
+
+	tags, err := gst.MarshalTags(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		data   []byte
+		format gsttag.MetadataFormat
+		tail   []byte
+	}{
+		{flac, gsttag.MetadataFormatFLAC, audio},
+		{id3, gsttag.MetadataFormatID3v2, audio},
+		{jpeg, gsttag.MetadataFormatJPEG, jpeg[8:]},
+		{vorbis, gsttag.MetadataFormatOgg, audio},
+		{opus, gsttag.MetadataFormatOgg, audio},
+	} {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			empty, format, err := gsttag.ReadMetadata(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if format != tc.format || !empty.IsEmpty() {
+				t.Fatalf("unexpected format %s or tags %s", format, empty.String())
+			}
+
+			written, err := gsttag.WriteMetadata(tc.data, tags)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.HasSuffix(written, tc.tail) {
+				t.Fatalf("the media data was not preserved: % x", written)
+			}
+
+			read, _, err := gsttag.ReadMetadata(written)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got testMetadata
+
+			if err := read.UnmarshalTags(&got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %#v, got %#v from %s", want, got, read.String())
+			}
+		})
+	}
+
+	if _, _, err := gsttag.ReadMetadata([]byte("RIFF")); err != gsttag.ErrUnknownMetadataFormat {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// oggStream creates an Ogg stream with the header packets on their own pages, followed by a page
+// with the audio packet
+func oggStream(packets ...[]byte) []byte {
+	var data []byte
+
+	for i, packet := range packets {
+		flags := byte(0)
+		if i == 0 {
+			flags = 0x02 // beginning of stream
+		}
+
+		data = append(data, "OggS"...)
+		data = append(data, 0, flags)
+		data = append(data, make([]byte, 8)...)           // granule position
+		data = append(data, 1, 0, 0, 0, byte(i), 0, 0, 0) // serial and sequence number
+		data = append(data, 0, 0, 0, 0, 1, byte(len(packet)))
+		data = append(data, packet...)
+	}
+
+	return data
+}
+
+func TestWriteMetadataKeepsID3v2Frames(t *testing.T) {
+	gst.Init()
+
+	priv := append([]byte("owner\x00"), 1, 2, 3)
+
+	for _, tc := range []struct {
+		name  string
+		tag   []byte
+		frame []byte
+	}{
+		{
+			name: "id3v2.3",
+			tag: id3v2Tag(3,
+				id3v2Frame("TIT2", []byte("\x00Old")),
+				id3v2Frame("TYER", []byte("\x001999")),
+				id3v2Frame("PRIV", priv),
+			),
+			frame: append([]byte{'P', 'R', 'I', 'V', 0, 0, 0, byte(len(priv)), 0, 0}, priv...),
+		},
+		{
+			name: "id3v2.4",
+			tag: id3v2Tag(4,
+				id3v2Frame("TIT2", []byte("\x03Old")),
+				id3v2Frame("PRIV", priv),
+			),
+			frame: append([]byte{'P', 'R', 'I', 'V', 0, 0, 0, byte(len(priv)), 0, 0}, priv...),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := gst.MarshalTags(testMetadata{Title: "New"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			written, err := gsttag.WriteMetadata(tc.tag, tags)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Contains(written, tc.frame) {
+				t.Fatalf("the PRIV frame was dropped: % x", written)
+			}
+
+			if bytes.Contains(written, []byte("Old")) || bytes.Contains(written, []byte("TYER")) {
+				t.Fatalf("the replaced frames were kept: % x", written)
+			}
+
+			read, _, err := gsttag.ReadMetadata(written)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if title, _ := read.GetString(gst.TAG_TITLE); title != "New" {
+				t.Fatalf("unexpected title %q", title)
+			}
+		})
+	}
+
+	// ID3v2.2 frames cannot be converted, so the tag is not rewritten
+	v22 := append([]byte{'I', 'D', '3', 2, 0, 0, 0, 0, 0, 10}, 'U', 'L', 'T', 0, 0, 4, 0, 'e', 'n', 'g')
+
+	if _, err := gsttag.WriteMetadata(v22, gst.NewTagListEmpty()); !errors.Is(err, gsttag.ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+}
+
+func id3v2Tag(version byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+
+	return append([]byte{'I', 'D', '3', version, 0, 0, 0, 0, 0, byte(len(body))}, body...)
+}
+
+// id3v2Frame creates a frame without flags, its size is valid for ID3v2.3 and ID3v2.4 as long as
+// the data is shorter than 128 bytes
+func id3v2Frame(id string, data []byte) []byte {
+	return append([]byte{id[0], id[1], id[2], id[3], 0, 0, 0, byte(len(data)), 0, 0}, data...)
+}
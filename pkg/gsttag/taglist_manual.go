@@ -0,0 +1,157 @@
+package gsttag
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/core/transfer"
+	"github.com/go-gst/go-gst/pkg/gst"
+)
+
+// #cgo pkg-config: gstreamer-tag-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/tag/tag.h>
+import "C"
+
+// TagListFromVorbiscomment wraps gst_tag_list_from_vorbiscomment
+//
+// see also https://gstreamer.freedesktop.org/documentation/tag/gstvorbistag.html#gst_tag_list_from_vorbiscomment
+func TagListFromVorbiscomment(data []uint8, idData []uint8) (string, *gst.TagList) {
+	var carg1 *C.guint8    // in, none, array
+	var carg2 C.gsize      // in, none, casted
+	var carg3 *C.guint8    // in, none, array, nullable
+	var carg4 C.guint      // in, none, casted
+	var carg5 *C.gchar     // out, full, string, nullable-string
+	var cret *C.GstTagList // return, full, converted, nullable
+
+	if len(data) > 0 {
+		carg1 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(data)))
+	}
+	carg2 = C.gsize(len(data))
+	if len(idData) > 0 {
+		carg3 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(idData)))
+	}
+	carg4 = C.guint(len(idData))
+
+	cret = C.gst_tag_list_from_vorbiscomment(carg1, carg2, carg3, carg4, &carg5)
+	runtime.KeepAlive(data)
+	runtime.KeepAlive(idData)
+
+	var vendorString string
+	var goret *gst.TagList
+
+	if carg5 != nil {
+		vendorString = C.GoString((*C.char)(unsafe.Pointer(carg5)))
+		C.g_free(C.gpointer(carg5))
+	}
+	if cret != nil {
+		goret = gst.UnsafeTagListFromGlibFull(unsafe.Pointer(cret))
+	}
+
+	return vendorString, goret
+}
+
+// TagListFromVorbiscommentBuffer wraps gst_tag_list_from_vorbiscomment_buffer
+//
+// see also https://gstreamer.freedesktop.org/documentation/tag/gstvorbistag.html#gst_tag_list_from_vorbiscomment_buffer
+func TagListFromVorbiscommentBuffer(buffer *gst.Buffer, idData []uint8) (string, *gst.TagList) {
+	var carg1 *C.GstBuffer // in, none, converted
+	var carg2 *C.guint8    // in, none, array, nullable
+	var carg3 C.guint      // in, none, casted
+	var carg4 *C.gchar     // out, full, string, nullable-string
+	var cret *C.GstTagList // return, full, converted, nullable
+
+	carg1 = (*C.GstBuffer)(gst.UnsafeBufferToGlibNone(buffer))
+	if len(idData) > 0 {
+		carg2 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(idData)))
+	}
+	carg3 = C.guint(len(idData))
+
+	cret = C.gst_tag_list_from_vorbiscomment_buffer(carg1, carg2, carg3, &carg4)
+	runtime.KeepAlive(buffer)
+	runtime.KeepAlive(idData)
+
+	var vendorString string
+	var goret *gst.TagList
+
+	if carg4 != nil {
+		vendorString = C.GoString((*C.char)(unsafe.Pointer(carg4)))
+		C.g_free(C.gpointer(carg4))
+	}
+	if cret != nil {
+		goret = gst.UnsafeTagListFromGlibFull(unsafe.Pointer(cret))
+	}
+
+	return vendorString, goret
+}
+
+// TagListToVorbiscommentBuffer wraps gst_tag_list_to_vorbiscomment_buffer
+//
+// see also https://gstreamer.freedesktop.org/documentation/tag/gstvorbistag.html#gst_tag_list_to_vorbiscomment_buffer
+func TagListToVorbiscommentBuffer(list *gst.TagList, idData []uint8, vendorString string) *gst.Buffer {
+	var carg1 *C.GstTagList // in, none, converted
+	var carg2 *C.guint8     // in, none, array, nullable
+	var carg3 C.guint       // in, none, casted
+	var carg4 *C.gchar      // in, none, string, nullable-string
+	var cret *C.GstBuffer   // return, full, converted
+
+	carg1 = (*C.GstTagList)(gst.UnsafeTagListToGlibNone(list))
+	if len(idData) > 0 {
+		carg2 = (*C.guint8)(unsafe.Pointer(unsafe.SliceData(idData)))
+	}
+	carg3 = C.guint(len(idData))
+	if vendorString != "" {
+		carg4 = (*C.gchar)(transfer.GLibString(vendorString))
+		defer C.g_free(C.gpointer(carg4))
+	}
+
+	cret = C.gst_tag_list_to_vorbiscomment_buffer(carg1, carg2, carg3, carg4)
+	runtime.KeepAlive(list)
+	runtime.KeepAlive(idData)
+
+	var goret *gst.Buffer
+
+	goret = gst.UnsafeBufferFromGlibFull(unsafe.Pointer(cret))
+
+	return goret
+}
+
+// TagListToXmpBuffer wraps gst_tag_list_to_xmp_buffer
+//
+// see also https://gstreamer.freedesktop.org/documentation/tag/gstxmptag.html#gst_tag_list_to_xmp_buffer
+func TagListToXmpBuffer(list *gst.TagList, readOnly bool, schemas []string) *gst.Buffer {
+	var carg1 *C.GstTagList // in, none, converted
+	var carg2 C.gboolean    // in
+	var carg3 **C.gchar     // in, none, array of strings, zero-terminated, nullable
+	var cret *C.GstBuffer   // return, full, converted, nullable
+
+	carg1 = (*C.GstTagList)(gst.UnsafeTagListToGlibNone(list))
+	if readOnly {
+		carg2 = C.TRUE
+	}
+	if schemas != nil {
+		// the array is allocated in C memory, because it contains pointers to C memory
+		cschemas := (**C.gchar)(C.calloc(C.size_t(len(schemas)+1), C.size_t(unsafe.Sizeof(carg3))))
+		defer C.free(unsafe.Pointer(cschemas))
+
+		schemaSlice := unsafe.Slice(cschemas, len(schemas)+1)
+		for i, schema := range schemas {
+			schemaSlice[i] = (*C.gchar)(unsafe.Pointer(C.CString(schema)))
+			defer C.free(unsafe.Pointer(schemaSlice[i]))
+		}
+		carg3 = cschemas
+	}
+
+	cret = C.gst_tag_list_to_xmp_buffer(carg1, carg2, carg3)
+	runtime.KeepAlive(list)
+	runtime.KeepAlive(readOnly)
+	runtime.KeepAlive(schemas)
+
+	var goret *gst.Buffer
+
+	if cret != nil {
+		goret = gst.UnsafeBufferFromGlibFull(unsafe.Pointer(cret))
+	}
+
+	return goret
+}
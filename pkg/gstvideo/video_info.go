@@ -16,3 +16,32 @@ func (info *VideoInfo) SetFramerate(num, denom int) {
 func (info *VideoInfo) GetSize() int {
 	return int(info.videoInfo.native.size)
 }
+
+// GetFormat returns the format of the video
+func (info *VideoInfo) GetFormat() VideoFormat {
+	if info.videoInfo.native.finfo == nil {
+		return VideoFormatUnknown
+	}
+
+	return VideoFormat(info.videoInfo.native.finfo.format)
+}
+
+// GetWidth returns the width of the video in pixels
+func (info *VideoInfo) GetWidth() int {
+	return int(info.videoInfo.native.width)
+}
+
+// GetHeight returns the height of the video in pixels
+func (info *VideoInfo) GetHeight() int {
+	return int(info.videoInfo.native.height)
+}
+
+// GetStride returns the number of bytes of one row of the given plane
+func (info *VideoInfo) GetStride(plane int) int {
+	return int(info.videoInfo.native.stride[plane])
+}
+
+// GetOffset returns the offset of the given plane in a frame
+func (info *VideoInfo) GetOffset(plane int) int {
+	return int(info.videoInfo.native.offset[plane])
+}
@@ -0,0 +1,355 @@
+package gstwebrtc
+
+import (
+	"context"
+	"io"
+	"iter"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/go-gst/go-glib/pkg/glib/v2"
+	"github.com/go-gst/go-glib/pkg/gobject/v2"
+)
+
+// #cgo pkg-config: gstreamer-webrtc-1.0
+// #cgo CFLAGS: -Wno-deprecated-declarations
+// #include <gst/webrtc/webrtc.h>
+import "C"
+
+// DefaultDataChannelMaxBufferedAmount is the default amount of bytes that may be queued
+// in a data channel before [DataChannelConn.Write] blocks.
+const DefaultDataChannelMaxBufferedAmount = 1 << 20
+
+// DataChannelMessage is a single message received on a data channel.
+type DataChannelMessage struct {
+	// Data contains the payload of the message. For string messages this is the
+	// UTF-8 encoded string.
+	Data []byte
+
+	// IsString is true if the message was sent as a string message by the peer.
+	IsString bool
+}
+
+// String returns the payload of the message as a string.
+func (m DataChannelMessage) String() string {
+	return string(m.Data)
+}
+
+// DataChannelConn adapts a [WebRTCDataChannel] to an [io.ReadWriteCloser].
+//
+// Every call to Write sends exactly one binary message, so the caller is responsible for
+// keeping the writes below the maximum message size negotiated for the channel. Write blocks
+// while the buffered amount of the channel exceeds the configured maximum, which keeps memory
+// bounded when the peer is slower than the producer.
+//
+// Received messages are queued until they are consumed by either Read or [DataChannelConn.Messages].
+// Mixing both on the same connection splits the incoming messages between the consumers.
+type DataChannelConn struct {
+	channel WebRTCDataChannel
+
+	maxBufferedAmount uint64
+
+	mu sync.Mutex
+	// notEmpty is signaled when a message was received or the channel was closed
+	notEmpty *sync.Cond
+	// lowWater is closed and replaced whenever the buffered amount went below the low threshold
+	lowWater chan struct{}
+
+	queue []DataChannelMessage
+	// pending is the unread part of the message that is currently consumed by Read
+	pending []byte
+	closed  bool
+	err     error
+
+	handles   []gobject.SignalHandle
+	closeOnce sync.Once
+}
+
+var _ io.ReadWriteCloser = (*DataChannelConn)(nil)
+
+// NewDataChannelConn creates a new [DataChannelConn] for the given data channel. Writes block once
+// more than maxBufferedAmount bytes are queued in the channel. If maxBufferedAmount is 0, then
+// [DefaultDataChannelMaxBufferedAmount] is used.
+//
+// The buffered-amount-low-threshold property of the channel is set to half of maxBufferedAmount.
+func NewDataChannelConn(channel WebRTCDataChannel, maxBufferedAmount uint64) *DataChannelConn {
+	if maxBufferedAmount == 0 {
+		maxBufferedAmount = DefaultDataChannelMaxBufferedAmount
+	}
+
+	c := &DataChannelConn{
+		channel:           channel,
+		maxBufferedAmount: maxBufferedAmount,
+		lowWater:          make(chan struct{}),
+	}
+	c.notEmpty = sync.NewCond(&c.mu)
+
+	channel.SetObjectProperty("buffered-amount-low-threshold", maxBufferedAmount/2)
+
+	c.handles = []gobject.SignalHandle{
+		channel.ConnectOnMessageData(func(_ WebRTCDataChannel, data *glib.Bytes) {
+			c.push(DataChannelMessage{Data: bytesToGo(data)})
+		}),
+		channel.ConnectOnMessageString(func(_ WebRTCDataChannel, str string) {
+			c.push(DataChannelMessage{Data: []byte(str), IsString: true})
+		}),
+		channel.ConnectOnBufferedAmountLow(func(_ WebRTCDataChannel) {
+			c.mu.Lock()
+			c.signalLowWater()
+			c.mu.Unlock()
+		}),
+		channel.ConnectOnClose(func(_ WebRTCDataChannel) {
+			c.closeWithError(nil)
+		}),
+		channel.ConnectOnError(func(_ WebRTCDataChannel, err error) {
+			c.closeWithError(err)
+		}),
+	}
+
+	if state, ok := channel.ObjectProperty("ready-state").(WebRTCDataChannelState); ok && state == WebrtcDataChannelStateClosed {
+		c.closeWithError(nil)
+	}
+
+	return c
+}
+
+// Channel returns the underlying data channel.
+func (c *DataChannelConn) Channel() WebRTCDataChannel {
+	return c.channel
+}
+
+// Read implements [io.Reader]. It reads the data of the received messages as a continuous stream.
+// Read returns [io.EOF] once the channel is closed and all received messages were consumed.
+func (c *DataChannelConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.pending) == 0 {
+		for len(c.queue) == 0 && !c.closed {
+			c.notEmpty.Wait()
+		}
+
+		if len(c.queue) == 0 {
+			if c.err != nil {
+				return 0, c.err
+			}
+			return 0, io.EOF
+		}
+
+		c.pending = c.queue[0].Data
+		c.queue = c.queue[1:]
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+// Write implements [io.Writer]. It sends p as a single binary message and blocks while the buffered
+// amount of the channel exceeds the configured maximum.
+func (c *DataChannelConn) Write(p []byte) (int, error) {
+	return c.WriteContext(context.Background(), p)
+}
+
+// WriteContext is like [DataChannelConn.Write], but stops waiting for the buffered amount to drop
+// when the context is done.
+func (c *DataChannelConn) WriteContext(ctx context.Context, p []byte) (int, error) {
+	if err := c.waitBuffered(ctx); err != nil {
+		return 0, err
+	}
+
+	ok, err := c.channel.SendDataFull(bytesFromGo(p))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, io.ErrClosedPipe
+	}
+
+	return len(p), nil
+}
+
+// WriteString sends s as a single string message, applying the same flow control as
+// [DataChannelConn.Write].
+func (c *DataChannelConn) WriteString(s string) (int, error) {
+	if err := c.waitBuffered(context.Background()); err != nil {
+		return 0, err
+	}
+
+	ok, err := c.channel.SendStringFull(s)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, io.ErrClosedPipe
+	}
+
+	return len(s), nil
+}
+
+// waitBuffered blocks until the buffered amount of the channel is at most maxBufferedAmount
+func (c *DataChannelConn) waitBuffered(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		closed := c.closed
+		lowWater := c.lowWater
+		c.mu.Unlock()
+
+		if closed {
+			return io.ErrClosedPipe
+		}
+
+		// the property must not be read while holding the lock, because the
+		// buffered amount low signal may be emitted while webrtcbin holds its own lock
+		if c.bufferedAmount() <= c.maxBufferedAmount {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-lowWater:
+		}
+	}
+}
+
+func (c *DataChannelConn) bufferedAmount() uint64 {
+	amount, _ := c.channel.ObjectProperty("buffered-amount").(uint64)
+	return amount
+}
+
+// Messages returns an iterator over the received messages. The iterator ends when the
+// channel is closed and all messages were consumed, or when the context is done.
+func (c *DataChannelConn) Messages(ctx context.Context) iter.Seq[DataChannelMessage] {
+	return func(yield func(DataChannelMessage) bool) {
+		stop := context.AfterFunc(ctx, func() {
+			c.mu.Lock()
+			c.notEmpty.Broadcast()
+			c.mu.Unlock()
+		})
+		defer stop()
+
+		for {
+			msg, ok := c.receive(ctx)
+			if !ok {
+				return
+			}
+
+			if !yield(msg) {
+				return
+			}
+		}
+	}
+}
+
+func (c *DataChannelConn) receive(ctx context.Context) (DataChannelMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.queue) == 0 && !c.closed && ctx.Err() == nil {
+		c.notEmpty.Wait()
+	}
+
+	if len(c.queue) == 0 || ctx.Err() != nil {
+		return DataChannelMessage{}, false
+	}
+
+	msg := c.queue[0]
+	c.queue = c.queue[1:]
+
+	return msg, true
+}
+
+// Err returns the error that closed the channel, if any.
+func (c *DataChannelConn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Close implements [io.Closer]. It closes the data channel and disconnects all signal handlers.
+// Messages that were already received can still be read after Close. Calling Close more than
+// once has no effect.
+func (c *DataChannelConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeWithError(nil)
+
+		c.mu.Lock()
+		handles := c.handles
+		c.handles = nil
+		c.mu.Unlock()
+
+		for _, h := range handles {
+			c.channel.HandlerDisconnect(h)
+		}
+
+		c.channel.Close()
+	})
+
+	return nil
+}
+
+func (c *DataChannelConn) push(msg DataChannelMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.queue = append(c.queue, msg)
+	c.notEmpty.Signal()
+}
+
+func (c *DataChannelConn) closeWithError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	c.err = err
+	c.notEmpty.Broadcast()
+	c.signalLowWater()
+}
+
+// signalLowWater wakes up all blocked writers. Must be called with the lock held.
+func (c *DataChannelConn) signalLowWater() {
+	close(c.lowWater)
+	c.lowWater = make(chan struct{})
+}
+
+// bytesToGo copies the contents of the GBytes into a go slice
+func bytesToGo(b *glib.Bytes) []byte {
+	if b == nil {
+		return nil
+	}
+
+	var size C.gsize
+	data := C.g_bytes_get_data((*C.GBytes)(glib.UnsafeBytesToGlibNone(b)), &size)
+	runtime.KeepAlive(b)
+
+	if data == nil || size == 0 {
+		return []byte{}
+	}
+
+	return C.GoBytes(unsafe.Pointer(data), C.int(size))
+}
+
+// bytesFromGo copies p into a new GBytes
+func bytesFromGo(p []byte) *glib.Bytes {
+	var data C.gconstpointer
+	if len(p) > 0 {
+		data = C.gconstpointer(unsafe.Pointer(unsafe.SliceData(p)))
+	}
+
+	cret := C.g_bytes_new(data, C.gsize(len(p)))
+	runtime.KeepAlive(p)
+
+	return glib.UnsafeBytesFromGlibFull(unsafe.Pointer(cret))
+}
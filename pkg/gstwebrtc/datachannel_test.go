@@ -0,0 +1,181 @@
+package gstwebrtc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-gst/go-gst/pkg/gst"
+	"github.com/go-gst/go-gst/pkg/gstwebrtc"
+)
+
+// createDescription emits the create-offer or create-answer signal and returns the description
+// of the reply
+func createDescription(ctx context.Context, t *testing.T, webrtcbin gst.Element, signal, field string) *gstwebrtc.WebRTCSessionDescription {
+	t.Helper()
+
+	promise := gst.NewPromise()
+	webrtcbin.Emit(signal, gst.NewStructureEmpty("options"), promise)
+
+	reply, err := promise.Await(ctx)
+	if err != nil {
+		t.Fatalf("%s: %v", signal, err)
+	}
+
+	desc, ok := reply.GetValue(field).(*gstwebrtc.WebRTCSessionDescription)
+	if !ok {
+		t.Fatalf("%s: no %s in reply %s", signal, field, reply.String())
+	}
+
+	return desc
+}
+
+// setDescription emits the set-local-description or set-remote-description signal and waits
+// until it was applied
+func setDescription(ctx context.Context, t *testing.T, webrtcbin gst.Element, signal string, desc *gstwebrtc.WebRTCSessionDescription) {
+	t.Helper()
+
+	promise := gst.NewPromise()
+	webrtcbin.Emit(signal, desc, promise)
+
+	if _, err := promise.Await(ctx); err != nil {
+		t.Fatalf("%s: %v", signal, err)
+	}
+}
+
+// connectDataChannels negotiates a data channel between two webrtcbins in the same pipeline and
+// returns the channels of both sides once they are open
+func connectDataChannels(ctx context.Context, t *testing.T) (gstwebrtc.WebRTCDataChannel, gstwebrtc.WebRTCDataChannel) {
+	t.Helper()
+
+	gst.Init()
+
+	for _, name := range []string{"webrtcbin", "sctpenc", "nicesrc"} {
+		if gst.ElementFactoryFind(name) == nil {
+			t.Skipf("%s is not available", name)
+		}
+	}
+
+	pipeline := gst.NewPipeline("").(gst.Pipeline)
+	offerer := gst.ElementFactoryMake("webrtcbin", "")
+	answerer := gst.ElementFactoryMake("webrtcbin", "")
+
+	pipeline.AddMany(offerer, answerer)
+
+	t.Cleanup(func() {
+		pipeline.SetState(gst.StateNull)
+	})
+
+	offerer.Connect("on-ice-candidate", func(_ gst.Element, mline uint32, candidate string) {
+		answerer.Emit("add-ice-candidate", mline, candidate)
+	})
+
+	answerer.Connect("on-ice-candidate", func(_ gst.Element, mline uint32, candidate string) {
+		offerer.Emit("add-ice-candidate", mline, candidate)
+	})
+
+	remoteChannels := make(chan gstwebrtc.WebRTCDataChannel, 1)
+
+	answerer.Connect("on-data-channel", func(_ gst.Element, channel gstwebrtc.WebRTCDataChannel) {
+		remoteChannels <- channel
+	})
+
+	pipeline.SetState(gst.StatePlaying)
+
+	local, ok := offerer.Emit("create-data-channel", "test", gst.NewStructureEmpty("options")).(gstwebrtc.WebRTCDataChannel)
+	if !ok {
+		t.Fatal("could not create data channel")
+	}
+
+	opened := make(chan struct{})
+	local.ConnectOnOpen(func(gstwebrtc.WebRTCDataChannel) {
+		close(opened)
+	})
+
+	offer := createDescription(ctx, t, offerer, "create-offer", "offer")
+	setDescription(ctx, t, offerer, "set-local-description", offer)
+	setDescription(ctx, t, answerer, "set-remote-description", offer)
+
+	answer := createDescription(ctx, t, answerer, "create-answer", "answer")
+	setDescription(ctx, t, answerer, "set-local-description", answer)
+	setDescription(ctx, t, offerer, "set-remote-description", answer)
+
+	var remote gstwebrtc.WebRTCDataChannel
+
+	select {
+	case remote = <-remoteChannels:
+	case <-ctx.Done():
+		t.Fatal("remote data channel was not announced")
+	}
+
+	select {
+	case <-opened:
+	case <-ctx.Done():
+		t.Fatal("data channel was not opened")
+	}
+
+	return local, remote
+}
+
+func TestDataChannelConn(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	localChannel, remoteChannel := connectDataChannels(ctx, t)
+
+	local := gstwebrtc.NewDataChannelConn(localChannel, 0)
+	remote := gstwebrtc.NewDataChannelConn(remoteChannel, 0)
+	defer remote.Close()
+
+	for _, msg := range []string{"hello", "world"} {
+		if n, err := local.Write([]byte(msg)); err != nil || n != len(msg) {
+			t.Fatalf("write returned %d, %v", n, err)
+		}
+	}
+
+	buf := make([]byte, 3)
+	var received []byte
+
+	for len(received) < len("helloworld") {
+		n, err := remote.Read(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+
+		received = append(received, buf[:n]...)
+	}
+
+	if string(received) != "helloworld" {
+		t.Fatalf("unexpected data %q", received)
+	}
+
+	if err := local.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// closing twice must not close the channel again
+	if err := local.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+
+	if _, err := local.Write([]byte("closed")); err != io.ErrClosedPipe {
+		t.Fatalf("expected io.ErrClosedPipe after close, got %v", err)
+	}
+
+	read := make(chan error, 1)
+
+	go func() {
+		_, err := remote.Read(buf)
+		read <- err
+	}()
+
+	select {
+	case err := <-read:
+		if err != io.EOF {
+			t.Fatalf("expected io.EOF after the peer closed, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("remote was not closed")
+	}
+}